@@ -0,0 +1,353 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+)
+
+// Union returns the family of solutions contained in z or other.
+//
+// Both ZDDs must be over the same number of variables. This merges diagrams
+// built for different scenarios without enumerating their solutions. The
+// result uses the configuration of z; neither operand is modified.
+func (z *ZDD) Union(ctx context.Context, other *ZDD) (*ZDD, error) {
+	return z.apply(ctx, "union", other, func(ops *familyOps, f, g NodeID) (NodeID, error) {
+		return ops.union(f, g)
+	})
+}
+
+// Intersect returns the family of solutions contained in both z and other.
+//
+// Both ZDDs must be over the same number of variables. Building one diagram
+// per group of constraints and intersecting them gives the solutions that
+// satisfy all groups. The result uses the configuration of z; neither operand
+// is modified.
+func (z *ZDD) Intersect(ctx context.Context, other *ZDD) (*ZDD, error) {
+	return z.apply(ctx, "intersect", other, func(ops *familyOps, f, g NodeID) (NodeID, error) {
+		return ops.intersect(f, g)
+	})
+}
+
+// Difference returns the family of solutions contained in z but not in other.
+//
+// Both ZDDs must be over the same number of variables. Subtracting the
+// diagram built with an extra constraint from the one built without it shows
+// exactly the solutions that constraint eliminates. The result uses the
+// configuration of z; neither operand is modified.
+func (z *ZDD) Difference(ctx context.Context, other *ZDD) (*ZDD, error) {
+	return z.apply(ctx, "difference", other, func(ops *familyOps, f, g NodeID) (NodeID, error) {
+		return ops.difference(f, g)
+	})
+}
+
+// SymmetricDifference returns the family of solutions contained in exactly one
+// of z and other.
+//
+// Both ZDDs must be over the same number of variables. An empty result means
+// the two diagrams represent the same family, which makes this a direct check
+// that a model change kept the solution set intact. The result uses the
+// configuration of z; neither operand is modified.
+func (z *ZDD) SymmetricDifference(ctx context.Context, other *ZDD) (*ZDD, error) {
+	return z.apply(ctx, "symmetric difference", other, func(ops *familyOps, f, g NodeID) (NodeID, error) {
+		onlyF, err := ops.difference(f, g)
+		if err != nil {
+			return NullNode, err
+		}
+		onlyG, err := ops.difference(g, f)
+		if err != nil {
+			return NullNode, err
+		}
+		return ops.union(onlyF, onlyG)
+	})
+}
+
+// Join returns the family of all unions A ∪ B of a solution A of z and a
+// solution B of other.
+//
+// The operands may use different numbers of variables; the result has as many
+// as the larger one. Over disjoint variable ranges this composes the solutions
+// of independent sub-problems, and over shared ranges variables selected by
+// both sides appear once. The result uses the configuration of z; neither
+// operand is modified.
+func (z *ZDD) Join(ctx context.Context, other *ZDD) (*ZDD, error) {
+	if other == nil {
+		return nil, fmt.Errorf("%w: ZDD is nil", ErrInvalidNode)
+	}
+	return z.combine(ctx, "join", other, max(z.vars, other.vars), func(ops *familyOps, f, g NodeID) (NodeID, error) {
+		return ops.join(f, g)
+	})
+}
+
+// Meet returns the family of all intersections A ∩ B of a solution A of z and
+// a solution B of other.
+//
+// Meet is the counterpart of Join. The operands may use different numbers of
+// variables; the result has as many as the larger one. The result uses the
+// configuration of z; neither operand is modified.
+func (z *ZDD) Meet(ctx context.Context, other *ZDD) (*ZDD, error) {
+	if other == nil {
+		return nil, fmt.Errorf("%w: ZDD is nil", ErrInvalidNode)
+	}
+	return z.combine(ctx, "meet", other, max(z.vars, other.vars), func(ops *familyOps, f, g NodeID) (NodeID, error) {
+		return ops.meet(f, g)
+	})
+}
+
+// Divide returns the quotient of z by other in the sense of Minato's weak
+// division.
+//
+// The quotient is the largest family Q such that every set of Q is disjoint
+// from every solution of other and Join(Q, other) is contained in z. Dividing
+// by a single set S yields the solutions containing S with S removed, which
+// factors a common sub-structure out of a family. other may use fewer or more
+// variables than z; the result has z.Variables() variables. Returns
+// ErrInvalidConstraint if other is the empty family.
+func (z *ZDD) Divide(ctx context.Context, other *ZDD) (*ZDD, error) {
+	if err := checkDivisor(other); err != nil {
+		return nil, err
+	}
+	return z.combine(ctx, "divide", other, z.vars, func(ops *familyOps, f, g NodeID) (NodeID, error) {
+		return ops.divide(f, g)
+	})
+}
+
+// Remainder returns the solutions of z not covered by Divide: z minus
+// Join(Divide(z, other), other).
+//
+// Together with Divide this gives z = Join(Q, other) ∪ R with R disjoint from
+// the joined part. Returns ErrInvalidConstraint if other is the empty family.
+func (z *ZDD) Remainder(ctx context.Context, other *ZDD) (*ZDD, error) {
+	if err := checkDivisor(other); err != nil {
+		return nil, err
+	}
+	return z.combine(ctx, "remainder", other, z.vars, func(ops *familyOps, f, g NodeID) (NodeID, error) {
+		q, err := ops.divide(f, g)
+		if err != nil {
+			return NullNode, err
+		}
+		covered, err := ops.join(q, g)
+		if err != nil {
+			return NullNode, err
+		}
+		return ops.difference(f, covered)
+	})
+}
+
+// checkDivisor rejects nil and empty divisors
+func checkDivisor(g *ZDD) error {
+	if g == nil {
+		return fmt.Errorf("%w: ZDD is nil", ErrInvalidNode)
+	}
+	if g.family() == ZeroNode {
+		return fmt.Errorf("%w: division by the empty family", ErrInvalidConstraint)
+	}
+	return nil
+}
+
+// NonSubsets returns the solutions of z that are not a subset of any
+// solution of other.
+//
+// other may use fewer or more variables than z; the result has
+// z.Variables() variables. The result uses the configuration of z; neither
+// operand is modified.
+func (z *ZDD) NonSubsets(ctx context.Context, other *ZDD) (*ZDD, error) {
+	if other == nil {
+		return nil, fmt.Errorf("%w: ZDD is nil", ErrInvalidNode)
+	}
+	return z.combine(ctx, "non-subsets", other, z.vars, func(ops *familyOps, f, g NodeID) (NodeID, error) {
+		return ops.nonSubsets(f, g)
+	})
+}
+
+// NonSupersets returns the solutions of z that do not contain any solution
+// of other.
+//
+// With other holding forbidden combinations, such as pairs of conflicting
+// tasks, this keeps exactly the solutions that avoid all of them. other may
+// use fewer or more variables than z; the result has z.Variables() variables.
+// The result uses the configuration of z; neither operand is modified.
+func (z *ZDD) NonSupersets(ctx context.Context, other *ZDD) (*ZDD, error) {
+	if other == nil {
+		return nil, fmt.Errorf("%w: ZDD is nil", ErrInvalidNode)
+	}
+	return z.combine(ctx, "non-supersets", other, z.vars, func(ops *familyOps, f, g NodeID) (NodeID, error) {
+		return ops.nonSupersets(f, g)
+	})
+}
+
+// Maximal returns the solutions that are not a proper subset of another
+// solution.
+//
+// Applied to a family closed under taking subsets, such as all independent
+// sets of a graph, this gives the maximal members without enumerating the
+// family. The result uses the configuration of z; z is not modified.
+func (z *ZDD) Maximal(ctx context.Context) (*ZDD, error) {
+	return z.transform(ctx, "maximal", func(ops *familyOps, f NodeID) (NodeID, error) {
+		return ops.maximal(f)
+	})
+}
+
+// Minimal returns the solutions that are not a proper superset of another
+// solution.
+//
+// Applied to a family closed under taking supersets, such as all covers,
+// this gives the minimal members. The result uses the configuration of z; z
+// is not modified.
+func (z *ZDD) Minimal(ctx context.Context) (*ZDD, error) {
+	return z.transform(ctx, "minimal", func(ops *familyOps, f NodeID) (NodeID, error) {
+		return ops.minimal(f)
+	})
+}
+
+// DownwardClosure returns every subset of a solution.
+//
+// The result is the smallest family closed under taking subsets that contains
+// z, such as all partial plans that can still be completed to a solution.
+// The result uses the configuration of z; z is not modified.
+func (z *ZDD) DownwardClosure(ctx context.Context) (*ZDD, error) {
+	return z.transform(ctx, "downward closure", func(ops *familyOps, f NodeID) (NodeID, error) {
+		return ops.downClosure(f)
+	})
+}
+
+// UpwardClosure returns every set of variables 1..maxVar that contains a
+// solution.
+//
+// The result is the smallest family over maxVar variables closed under taking
+// supersets that contains z, such as all selections that cover some solution.
+// maxVar sets the universe of the supersets and the number of variables of the
+// result. Returns ErrInvalidVariable if maxVar is less than Variables().
+func (z *ZDD) UpwardClosure(ctx context.Context, maxVar int) (*ZDD, error) {
+	if maxVar < z.vars {
+		return nil, fmt.Errorf("%w: universe of %d variables is smaller than the ZDD's %d", ErrInvalidVariable, maxVar, z.vars)
+	}
+	ops, roots, err := workspace(ctx, z)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := ops.upClosure(roots[0], maxVar)
+	if err != nil {
+		return nil, fmt.Errorf("upward closure failed: %w", err)
+	}
+
+	return z.derive(maxVar, ops.nt, root)
+}
+
+// Subset0 returns the solutions that do not select variable v.
+//
+// Returns ErrInvalidVariable if v is outside 1..Variables(). The result uses
+// the configuration of z; z is not modified.
+func (z *ZDD) Subset0(ctx context.Context, v int) (*ZDD, error) {
+	if err := z.checkVariable(v); err != nil {
+		return nil, err
+	}
+	return z.transform(ctx, "subset0", func(ops *familyOps, f NodeID) (NodeID, error) {
+		return ops.subset0(f, v)
+	})
+}
+
+// Subset1 returns the solutions that select variable v, with v removed from
+// each of them.
+//
+// Returns ErrInvalidVariable if v is outside 1..Variables(). The result uses
+// the configuration of z; z is not modified.
+func (z *ZDD) Subset1(ctx context.Context, v int) (*ZDD, error) {
+	if err := z.checkVariable(v); err != nil {
+		return nil, err
+	}
+	return z.transform(ctx, "subset1", func(ops *familyOps, f NodeID) (NodeID, error) {
+		return ops.subset1(f, v)
+	})
+}
+
+// Change toggles variable v in every solution: v is added to the solutions
+// without it and removed from the solutions with it.
+//
+// Returns ErrInvalidVariable if v is outside 1..Variables(). The result uses
+// the configuration of z; z is not modified.
+func (z *ZDD) Change(ctx context.Context, v int) (*ZDD, error) {
+	if err := z.checkVariable(v); err != nil {
+		return nil, err
+	}
+	return z.transform(ctx, "change", func(ops *familyOps, f NodeID) (NodeID, error) {
+		return ops.change(f, v)
+	})
+}
+
+// OnSet returns the solutions that select variable v, unchanged.
+//
+// Unlike Subset1, v stays in every returned solution, so the result answers
+// conditional queries such as "all plans that include server 3" directly.
+// Returns ErrInvalidVariable if v is outside 1..Variables().
+func (z *ZDD) OnSet(ctx context.Context, v int) (*ZDD, error) {
+	if err := z.checkVariable(v); err != nil {
+		return nil, err
+	}
+	return z.transform(ctx, "onset", func(ops *familyOps, f NodeID) (NodeID, error) {
+		with, err := ops.subset1(f, v)
+		if err != nil {
+			return NullNode, err
+		}
+		return ops.change(with, v)
+	})
+}
+
+// OffSet returns the solutions that do not select variable v.
+//
+// It is the same family as Subset0 and is provided as the counterpart of
+// OnSet. Returns ErrInvalidVariable if v is outside 1..Variables().
+func (z *ZDD) OffSet(ctx context.Context, v int) (*ZDD, error) {
+	return z.Subset0(ctx, v)
+}
+
+// checkVariable rejects variables outside 1..Variables()
+func (z *ZDD) checkVariable(v int) error {
+	if v < 1 || v > z.vars {
+		return fmt.Errorf("%w: variable %d", ErrInvalidVariable, v)
+	}
+	return nil
+}
+
+// transform evaluates a unary family operation on z in a scratch table
+func (z *ZDD) transform(ctx context.Context, name string, op func(ops *familyOps, f NodeID) (NodeID, error)) (*ZDD, error) {
+	ops, roots, err := workspace(ctx, z)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := op(ops, roots[0])
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w", name, err)
+	}
+
+	return z.derive(z.vars, ops.nt, root)
+}
+
+// apply evaluates a binary family operation on z and other, which must have
+// the same number of variables
+func (z *ZDD) apply(ctx context.Context, name string, other *ZDD, op func(ops *familyOps, f, g NodeID) (NodeID, error)) (*ZDD, error) {
+	if other == nil {
+		return nil, fmt.Errorf("%w: ZDD is nil", ErrInvalidNode)
+	}
+	if z.vars != other.vars {
+		return nil, fmt.Errorf("%w: operands have %d and %d variables", ErrInvalidVariable, z.vars, other.vars)
+	}
+	return z.combine(ctx, name, other, z.vars, op)
+}
+
+// combine evaluates a binary family operation in a scratch table and returns
+// the result as a ZDD over vars variables
+func (z *ZDD) combine(ctx context.Context, name string, other *ZDD, vars int, op func(ops *familyOps, f, g NodeID) (NodeID, error)) (*ZDD, error) {
+	ops, roots, err := workspace(ctx, z, other)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := op(ops, roots[0], roots[1])
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w", name, err)
+	}
+
+	return z.derive(vars, ops.nt, root)
+}
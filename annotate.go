@@ -0,0 +1,160 @@
+package gozdd
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// NodeAnnotator is an optional interface for ConstraintSpecs that record
+// provenance information on the nodes they create.
+//
+// When a spec implements NodeAnnotator, Build calls AnnotateNode for every
+// state that produces a new node and attaches the returned key/value pairs to
+// that node. Because equivalent sub-diagrams are shared, a node may be reached
+// from several states; keys already present on a node are kept.
+type NodeAnnotator interface {
+	// AnnotateNode returns metadata for the node built from state at level.
+	// Returning nil attaches nothing.
+	AnnotateNode(state State, level int) map[string]string
+}
+
+// annotations stores user metadata per node
+type annotations struct {
+	mu     sync.RWMutex
+	byNode map[NodeID]map[string]string
+}
+
+// set attaches key=value to id, replacing an existing value if overwrite is set
+func (a *annotations) set(id NodeID, key, value string, overwrite bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.byNode == nil {
+		a.byNode = make(map[NodeID]map[string]string)
+	}
+	meta := a.byNode[id]
+	if meta == nil {
+		meta = make(map[string]string)
+		a.byNode[id] = meta
+	}
+	if _, exists := meta[key]; exists && !overwrite {
+		return
+	}
+	meta[key] = value
+}
+
+// get returns a copy of the metadata of id
+func (a *annotations) get(id NodeID) map[string]string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	meta := a.byNode[id]
+	if len(meta) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(meta))
+	for k, v := range meta {
+		out[k] = v
+	}
+	return out
+}
+
+// remap renumbers annotated nodes and drops those that no longer exist.
+// Nodes mapped to one node pool their metadata; for a key they share, the
+// value of the lowest old ID is kept.
+func (a *annotations) remap(ids map[NodeID]NodeID) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	old := make([]NodeID, 0, len(a.byNode))
+	for id := range a.byNode {
+		old = append(old, id)
+	}
+	sort.Slice(old, func(i, j int) bool { return old[i] < old[j] })
+
+	remapped := make(map[NodeID]map[string]string, len(a.byNode))
+	for _, id := range old {
+		mapped := id
+		if id != ZeroNode && id != OneNode {
+			var ok bool
+			if mapped, ok = ids[id]; !ok {
+				continue
+			}
+		}
+		meta, exists := remapped[mapped]
+		if !exists {
+			remapped[mapped] = a.byNode[id]
+			continue
+		}
+		for k, v := range a.byNode[id] {
+			if _, shared := meta[k]; !shared {
+				meta[k] = v
+			}
+		}
+	}
+	a.byNode = remapped
+}
+
+// Annotate attaches a key/value pair to a node of the ZDD, replacing any
+// previous value for the key.
+//
+// Annotations belong to this ZDD, not to the node table, so ZDDs sharing
+// nodes through a Manager annotate them independently. They are kept across
+// Manager.GC but are not carried over to ZDDs derived by family operations.
+//
+// Returns ErrInvalidNode if id does not exist.
+func (z *ZDD) Annotate(id NodeID, key, value string) error {
+	if _, err := z.nodes.GetNode(id); err != nil {
+		return err
+	}
+
+	z.notes.set(id, key, value, true)
+	return nil
+}
+
+// Annotation returns the value of key on a node.
+func (z *ZDD) Annotation(id NodeID, key string) (string, bool) {
+	z.notes.mu.RLock()
+	defer z.notes.mu.RUnlock()
+
+	value, ok := z.notes.byNode[id][key]
+	return value, ok
+}
+
+// Annotations returns a copy of all metadata attached to a node, or nil.
+func (z *ZDD) Annotations(id NodeID) map[string]string {
+	return z.notes.get(id)
+}
+
+// annotateBuilt records spec-provided provenance for a node created during Build
+func (z *ZDD) annotateBuilt(spec NodeAnnotator, state State, level int, node NodeID) {
+	if node == ZeroNode || node == OneNode {
+		return
+	}
+
+	// a node at a lower level was returned by zero suppression and belongs
+	// to another state
+	if n, err := z.nodes.GetNode(node); err != nil || n.Level != level {
+		return
+	}
+
+	for k, v := range spec.AnnotateNode(state, level) {
+		z.notes.set(node, k, v, false)
+	}
+}
+
+// formatAnnotations renders metadata as sorted key: value lines
+func formatAnnotations(meta map[string]string) []string {
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, len(keys))
+	for i, k := range keys {
+		lines[i] = fmt.Sprintf("%s: %s", k, meta[k])
+	}
+	return lines
+}
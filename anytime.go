@@ -0,0 +1,434 @@
+package gozdd
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// CountProgress describes the state of an anytime count.
+type CountProgress struct {
+	// LevelsDone is the number of levels evaluated so far, from the top
+	LevelsDone int
+
+	// Levels is the total number of levels of the ZDD
+	Levels int
+
+	// LowerBound counts the solutions confirmed so far
+	LowerBound int64
+
+	// UpperBound bounds the solution count from above, saturating at
+	// math.MaxInt64
+	UpperBound int64
+
+	// Exact reports that the bounds have met and LowerBound is the count
+	Exact bool
+}
+
+// CountAnytime counts solutions top-down, reporting progress after every
+// level so callers of very large diagrams get interim results.
+//
+// The evaluation pushes the number of root paths down through the diagram one
+// level at a time. Paths that have reached the 1-terminal form the lower
+// bound; every path still inside the unevaluated part can complete in at most
+// 2^level ways, which gives the upper bound. Both bounds tighten monotonically
+// and meet when all levels are evaluated.
+//
+// Parameters:
+//   - ctx: Context for cancellation; on cancellation the bounds known so far
+//     are returned together with the context error
+//   - progress: Optional callback invoked after each evaluated level
+//
+// The final count agrees with Count, including its int64 range.
+func (z *ZDD) CountAnytime(ctx context.Context, progress func(CountProgress)) (CountProgress, error) {
+	state := CountProgress{Levels: z.vars, LevelsDone: z.vars, Exact: true}
+	switch z.root {
+	case NullNode, ZeroNode:
+		return state, nil
+	case OneNode:
+		state.LowerBound, state.UpperBound = 1, 1
+		return state, nil
+	}
+
+	root, err := z.GetNode(z.root)
+	if err != nil {
+		return CountProgress{}, err
+	}
+
+	// paths[n] counts the root paths that reach n through evaluated nodes
+	paths := map[NodeID]int64{z.root: 1}
+	lower := int64(0)
+
+	// pending bounds the completions of paths inside the unevaluated part
+	pending := new(big.Int)
+	addPending := func(count int64, level int, sign int) {
+		term := new(big.Int).Lsh(big.NewInt(count), uint(level))
+		if sign < 0 {
+			term.Neg(term)
+		}
+		pending.Add(pending, term)
+	}
+
+	addPending(1, root.Level, 1)
+	state = CountProgress{Levels: z.vars, UpperBound: saturatedAdd(0, pending)}
+
+	report := func(level int) {
+		state.LevelsDone = z.vars - level + 1
+		state.LowerBound = lower
+		state.UpperBound = saturatedAdd(lower, pending)
+		state.Exact = pending.Sign() == 0
+		if progress != nil {
+			progress(state)
+		}
+	}
+
+	for level := z.vars; level >= 1; level-- {
+		for id := range z.NodesAtLevel(level) {
+			select {
+			case <-ctx.Done():
+				return state, ctx.Err()
+			default:
+			}
+
+			node, err := z.GetNode(id)
+			if err != nil {
+				return state, err
+			}
+
+			count := paths[id]
+			delete(paths, id)
+			addPending(count, level, -1)
+
+			for _, child := range []NodeID{node.Lo, node.Hi} {
+				switch child {
+				case ZeroNode:
+				case OneNode:
+					lower += count
+				default:
+					cn, err := z.GetNode(child)
+					if err != nil {
+						return state, err
+					}
+					paths[child] += count
+					addPending(count, cn.Level, 1)
+				}
+			}
+		}
+
+		report(level)
+	}
+
+	return state, nil
+}
+
+// saturatedAdd returns base+extra, clamped to math.MaxInt64
+func saturatedAdd(base int64, extra *big.Int) int64 {
+	sum := new(big.Int).Add(big.NewInt(base), extra)
+	if !sum.IsInt64() {
+		return math.MaxInt64
+	}
+	return sum.Int64()
+}
+
+// AnytimeResult holds the outcome of a time-bounded optimization.
+type AnytimeResult struct {
+	// Solutions are the best solutions found, in ascending cost order
+	Solutions []*Solution
+
+	// Optimal reports that the search finished, so Solutions are the proven
+	// k best. It is false when the context ended the search early.
+	Optimal bool
+
+	// LowerBound is a proven lower bound on the optimal cost; +Inf if the
+	// family has no solutions
+	LowerBound float64
+
+	// Gap is the cost of the best solution found minus LowerBound, so the
+	// optimum is within Gap of Solutions[0]. It is 0 when the best solution
+	// is proven optimal.
+	Gap float64
+}
+
+// FindKBestAnytime finds the k lowest-cost solutions within the time allowed
+// by ctx, returning the best found so far when the deadline hits.
+//
+// The search first computes the exact minimum completion cost of every node,
+// then runs a depth-first branch-and-bound that follows the cheaper arc first
+// and skips sub-diagrams that cannot improve on the current k-th solution.
+// The first solution found is the optimum, and later ones only improve the
+// collection, so an interrupted search still returns useful answers.
+//
+// Cancellation or an expired deadline is not an error: the result carries
+// the solutions found so far with Optimal set to false, and LowerBound and
+// Gap bound how far they can be from optimal. Costs use the same 1-based
+// layout as FindKBest.
+func (z *ZDD) FindKBestAnytime(ctx context.Context, k int, costs []float64) (AnytimeResult, error) {
+	if z.root == NullNode || k <= 0 {
+		return AnytimeResult{Solutions: []*Solution{}, Optimal: true}, nil
+	}
+	if len(costs) <= z.vars {
+		return AnytimeResult{}, fmt.Errorf("insufficient cost data: need %d costs, got %d", z.vars, len(costs)-1)
+	}
+
+	bound, err := z.minCosts(ctx, costs)
+	if err != nil {
+		if ctx.Err() != nil {
+			return z.fallbackSolution(costs), nil
+		}
+		return AnytimeResult{}, err
+	}
+
+	s := &kBestSearch{
+		ctx:    ctx,
+		node:   z.GetNode,
+		bound:  func(id NodeID) float64 { return bound[id] },
+		costs:  costs,
+		k:      k,
+		stable: z.config.StableOrder,
+	}
+	err = s.search(z.root, 0)
+	if err != nil && ctx.Err() == nil {
+		return AnytimeResult{}, err
+	}
+
+	solutions := s.sorted()
+	if len(solutions) == 0 && !math.IsInf(bound[z.root], 1) {
+		// interrupted before the first leaf; the bounds lead to an optimum
+		solutions = append(solutions, z.walkSolution(costs, func(node Node) bool {
+			return bound[node.Hi]+costs[node.Level] < bound[node.Lo]
+		}))
+	}
+	z.decorate(solutions...)
+	result := AnytimeResult{Solutions: solutions, Optimal: err == nil, LowerBound: bound[z.root]}
+	if len(solutions) > 0 {
+		result.Gap = solutions[0].Cost - result.LowerBound
+	}
+	return result, nil
+}
+
+// fallbackSolution returns a solution and a trivial bound for a search that
+// ran out of time before the exact bounds were known.
+//
+// Every non-terminal node has a Hi arc to a non-empty family, so walking
+// down the locally cheaper arc, or Hi where Lo is the 0-terminal, always
+// reaches the 1-terminal. The bound assumes every variable of negative cost
+// is selected.
+func (z *ZDD) fallbackSolution(costs []float64) AnytimeResult {
+	result := AnytimeResult{Solutions: []*Solution{}}
+	for v := 1; v <= z.vars; v++ {
+		result.LowerBound += math.Min(0, costs[v])
+	}
+	if z.family() == ZeroNode {
+		result.LowerBound = math.Inf(1)
+		return result
+	}
+
+	solution := z.walkSolution(costs, func(node Node) bool {
+		return node.Lo == ZeroNode || costs[node.Level] < 0
+	})
+	z.decorate(solution)
+	result.Solutions = append(result.Solutions, solution)
+	result.Gap = solution.Cost - result.LowerBound
+	return result
+}
+
+// walkSolution follows one path from the root to the 1-terminal, taking the
+// Hi arc where take says so, and returns its solution. take must choose Hi
+// whenever Lo is the 0-terminal.
+func (z *ZDD) walkSolution(costs []float64, take func(node Node) bool) *Solution {
+	solution := &Solution{Variables: []int{}, Metadata: make(map[string]interface{})}
+	for id := z.root; id != OneNode; {
+		node, err := z.GetNode(id)
+		if err != nil {
+			break
+		}
+		if take(node) {
+			solution.Variables = append(solution.Variables, node.Level)
+			solution.Cost += costs[node.Level]
+			id = node.Hi
+		} else {
+			id = node.Lo
+		}
+	}
+	sort.Ints(solution.Variables)
+	return solution
+}
+
+// FindBestBefore finds the lowest-cost solution it can before deadline.
+//
+// It is FindKBestAnytime for a single solution: when the deadline passes, the
+// best solution found so far is returned with a proven bound instead of an
+// error, and Gap tells how far from optimal it can be. Even a deadline that
+// passes before the search begins yields a feasible solution if there is
+// one.
+func (z *ZDD) FindBestBefore(ctx context.Context, deadline time.Time, costs []float64) (AnytimeResult, error) {
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	return z.FindKBestAnytime(ctx, 1, costs)
+}
+
+// kBestSearch holds the state of a branch-and-bound k-best search
+type kBestSearch struct {
+	ctx context.Context
+
+	// node resolves node IDs of the searched diagram
+	node func(id NodeID) (Node, error)
+
+	// bound returns the minimum completion cost of a node
+	bound func(id NodeID) float64
+
+	costs []float64
+	k     int
+
+	// stable explores Lo arcs first so ties resolve in the stable order
+	stable bool
+
+	path []int
+	best solutionHeap
+}
+
+// search explores the completions of id reached with the given path cost.
+//
+// The search is depth-first on an explicit stack of frames, so deep
+// diagrams need no recursion. An arc is checked against the bound only once
+// the arcs before it are explored, so it sees the k best found so far.
+func (s *kBestSearch) search(id NodeID, cost float64) error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	switch id {
+	case ZeroNode:
+		return nil
+	case OneNode:
+		s.offer(cost)
+		return nil
+	}
+
+	root, err := s.frame(id, cost, false)
+	if err != nil {
+		return err
+	}
+	stack := []searchFrame{root}
+	for len(stack) > 0 {
+		if err := s.ctx.Err(); err != nil {
+			return err
+		}
+
+		f := &stack[len(stack)-1]
+		if f.next == 2 {
+			if f.took {
+				s.path = s.path[:len(s.path)-1]
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		take := f.takeFirst == (f.next == 0)
+		f.next++
+
+		child, childCost := f.node.Lo, f.cost
+		if take {
+			child, childCost = f.node.Hi, f.cost+s.costs[f.node.Level]
+		}
+		if child == ZeroNode || !s.promising(childCost+s.bound(child)) {
+			continue
+		}
+
+		if take {
+			s.path = append(s.path, f.node.Level)
+		}
+		if child == OneNode {
+			s.offer(childCost)
+			if take {
+				s.path = s.path[:len(s.path)-1]
+			}
+			continue
+		}
+		next, err := s.frame(child, childCost, take)
+		if err != nil {
+			return err
+		}
+		stack = append(stack, next)
+	}
+	return nil
+}
+
+// searchFrame is a node on the path of a kBestSearch
+type searchFrame struct {
+	node Node
+	cost float64
+
+	// takeFirst explores the Hi arc before the Lo arc
+	takeFirst bool
+
+	// next counts the arcs explored so far
+	next int
+
+	// took records whether the node was reached by a Hi arc
+	took bool
+}
+
+// frame returns the search frame of node id reached with the given path cost
+func (s *kBestSearch) frame(id NodeID, cost float64, took bool) (searchFrame, error) {
+	node, err := s.node(id)
+	if err != nil {
+		return searchFrame{}, err
+	}
+
+	loCost := cost
+	hiCost := cost + s.costs[node.Level]
+	takeFirst := !s.stable && hiCost+s.bound(node.Hi) < loCost+s.bound(node.Lo)
+	return searchFrame{node: node, cost: cost, takeFirst: takeFirst, took: took}, nil
+}
+
+// promising reports whether a completion of cost at least lb can enter the k best
+func (s *kBestSearch) promising(lb float64) bool {
+	if math.IsInf(lb, 1) {
+		return false
+	}
+	return len(s.best) < s.k || lb < s.best[0].Cost
+}
+
+// offer records the current path as a solution, evicting the worst if full
+func (s *kBestSearch) offer(cost float64) {
+	if !s.promising(cost) {
+		return
+	}
+
+	vars := append([]int(nil), s.path...)
+	sort.Ints(vars)
+	heap.Push(&s.best, &Solution{Variables: vars, Cost: cost, Metadata: make(map[string]interface{})})
+	if len(s.best) > s.k {
+		heap.Pop(&s.best)
+	}
+}
+
+// sorted returns the collected solutions in ascending cost order
+func (s *kBestSearch) sorted() []*Solution {
+	out := append([]*Solution{}, s.best...)
+	sort.Slice(out, func(i, j int) bool {
+		return solutionLess(out[i], out[j])
+	})
+	return out
+}
+
+// solutionHeap is a max-heap of solutions in solutionLess order
+type solutionHeap []*Solution
+
+func (h solutionHeap) Len() int           { return len(h) }
+func (h solutionHeap) Less(i, j int) bool { return solutionLess(h[j], h[i]) }
+func (h solutionHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *solutionHeap) Push(x interface{}) {
+	*h = append(*h, x.(*Solution))
+}
+
+func (h *solutionHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,262 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// ApproxCountEvaluator estimates the solution count by random sampling.
+//
+// Each sample walks from the root to the 1-terminal, choosing uniformly among
+// the arcs that lead to solutions, and scores the product of the number of
+// choices along the way (Knuth's estimator). The mean score is an unbiased
+// estimate of the count, and the spread of the scores yields a normal
+// approximation confidence interval. A sample costs one root-to-terminal walk,
+// independent of the size of the diagram below the visited path.
+type ApproxCountEvaluator struct {
+	// Samples is the number of random walks; more samples tighten the interval
+	Samples int
+
+	// Confidence is the coverage of the interval, e.g. 0.95 (default 0.95)
+	Confidence float64
+
+	// Seed makes the estimate reproducible
+	Seed int64
+}
+
+// ApproxCountResult is an approximate solution count with its uncertainty.
+type ApproxCountResult struct {
+	// Estimate is the estimated number of solutions
+	Estimate float64
+
+	// Lower and Upper bound the confidence interval; Lower is never negative
+	Lower float64
+	Upper float64
+
+	// Confidence is the coverage of [Lower, Upper]
+	Confidence float64
+
+	// Samples is the number of random walks taken
+	Samples int
+
+	// Approximate is always true and marks the result as an estimate
+	Approximate bool
+}
+
+// Evaluate estimates the number of solutions in the ZDD
+func (e ApproxCountEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
+	if e.Samples <= 0 {
+		return ApproxCountResult{}, fmt.Errorf("approximate count needs a positive sample count, got %d", e.Samples)
+	}
+
+	confidence := e.Confidence
+	if confidence == 0 {
+		confidence = 0.95
+	}
+	if confidence <= 0 || confidence >= 1 {
+		return ApproxCountResult{}, fmt.Errorf("confidence must be in (0, 1), got %g", confidence)
+	}
+
+	result := ApproxCountResult{Confidence: confidence, Samples: e.Samples, Approximate: true}
+	root := zdd.family()
+	if root == ZeroNode {
+		return result, nil
+	}
+
+	rng := rand.New(rand.NewSource(e.Seed))
+	var sum, sumSq float64
+
+	for i := 0; i < e.Samples; i++ {
+		select {
+		case <-ctx.Done():
+			return ApproxCountResult{}, ctx.Err()
+		default:
+		}
+
+		score, err := e.walk(zdd, root, rng)
+		if err != nil {
+			return ApproxCountResult{}, fmt.Errorf("approximate count failed: %w", err)
+		}
+		sum += score
+		sumSq += score * score
+	}
+
+	n := float64(e.Samples)
+	mean := sum / n
+	result.Estimate = mean
+
+	if e.Samples > 1 {
+		variance := math.Max(0, (sumSq-n*mean*mean)/(n-1))
+		half := math.Sqrt2 * math.Erfinv(confidence) * math.Sqrt(variance/n)
+		result.Lower = math.Max(0, mean-half)
+		result.Upper = mean + half
+	} else {
+		result.Lower, result.Upper = 0, math.Inf(1)
+	}
+
+	return result, nil
+}
+
+// walk takes one random root-to-terminal walk and returns its score.
+//
+// Every non-terminal node has a non-empty family, so the walk always reaches
+// the 1-terminal. The nodes quasi-reduced diagrams keep, whose Hi arc leads
+// to the 0-terminal, are passed through like nodes without a Lo arc.
+func (e ApproxCountEvaluator) walk(zdd *ZDD, root NodeID, rng *rand.Rand) (float64, error) {
+	score := 1.0
+	for id := root; id != OneNode; {
+		node, err := zdd.GetNode(id)
+		if err != nil {
+			return 0, err
+		}
+
+		if node.Lo == ZeroNode {
+			id = node.Hi
+			continue
+		}
+		if node.Hi == ZeroNode {
+			id = node.Lo
+			continue
+		}
+
+		score *= 2
+		if rng.Intn(2) == 0 {
+			id = node.Lo
+		} else {
+			id = node.Hi
+		}
+	}
+	return score, nil
+}
+
+// ApproxCount estimates the number of solutions from random samples.
+//
+// This is a type-safe convenience method for ApproxCountEvaluator with the
+// default 95% confidence level.
+func (z *ZDD) ApproxCount(ctx context.Context, samples int, seed int64) (ApproxCountResult, error) {
+	result, err := EvaluateZDD(ctx, z, ApproxCountEvaluator{Samples: samples, Seed: seed})
+	if err != nil {
+		return ApproxCountResult{}, err
+	}
+	return result.(ApproxCountResult), nil
+}
+
+// LogCountEvaluator counts the solutions in floating point with an unbounded
+// exponent.
+//
+// Each node stores its count as a float64 mantissa and a separate binary
+// exponent, so counts far beyond the float64 range, such as 2^5000, stay
+// representable. This needs one small fixed-size value per node, much less
+// than the exact counts of BigCountEvaluator, and the result carries a
+// rigorous bound on its relative error. The result is a LogCountResult.
+type LogCountEvaluator struct{}
+
+// LogCountResult is a solution count in scaled floating point.
+type LogCountResult struct {
+	// Mantissa and Exponent give the estimate as Mantissa·2^Exponent, with
+	// Mantissa in [0.5, 1), or zero when there are no solutions
+	Mantissa float64
+	Exponent int
+
+	// Log2 is the base-2 logarithm of the estimate, -Inf for no solutions
+	Log2 float64
+
+	// RelativeError bounds |estimate - count| / count
+	RelativeError float64
+}
+
+// Float64 returns the estimate as a float64, which is +Inf if it exceeds
+// the float64 range.
+func (r LogCountResult) Float64() float64 {
+	return math.Ldexp(r.Mantissa, r.Exponent)
+}
+
+// Log10 returns the base-10 logarithm of the estimate, -Inf for no solutions.
+func (r LogCountResult) Log10() float64 {
+	return r.Log2 * math.Log10(2)
+}
+
+// scaledCount is a non-negative number m·2^e with m in [0.5, 1), or m = 0
+type scaledCount struct {
+	m float64
+	e int
+}
+
+// add returns a+b rounded once to float64 precision
+func (a scaledCount) add(b scaledCount) scaledCount {
+	if a.m == 0 {
+		return b
+	}
+	if b.m == 0 {
+		return a
+	}
+	if a.e < b.e {
+		a, b = b, a
+	}
+	if a.e-b.e > 64 {
+		// b is below half an ulp of a, so the rounded sum is a
+		return a
+	}
+	m, e := math.Frexp(a.m + math.Ldexp(b.m, b.e-a.e))
+	return scaledCount{m: m, e: e + a.e}
+}
+
+// Evaluate counts all solutions in the ZDD
+func (e LogCountEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
+	counts, err := zdd.scaledCounts(ctx)
+	if err != nil {
+		return LogCountResult{}, fmt.Errorf("log count failed: %w", err)
+	}
+	count := counts[zdd.family()]
+
+	// every root path adds at most Variables() times, each rounding once
+	const u = 0x1p-53
+	n := float64(zdd.vars)
+	result := LogCountResult{
+		Mantissa:      count.m,
+		Exponent:      count.e,
+		Log2:          math.Inf(-1),
+		RelativeError: n * u / (1 - n*u),
+	}
+	if count.m > 0 {
+		result.Log2 = math.Log2(count.m) + float64(count.e)
+	} else {
+		result.Exponent = 0
+	}
+	return result, nil
+}
+
+// scaledCounts counts the solutions below every reachable node in scaled
+// floating point, bottom-up in level order
+func (z *ZDD) scaledCounts(ctx context.Context) (map[NodeID]scaledCount, error) {
+	return nodeTable(z, "count-scaled", func() (map[NodeID]scaledCount, error) {
+		counts := map[NodeID]scaledCount{
+			ZeroNode: {},
+			OneNode:  {m: 0.5, e: 1},
+		}
+		err := z.bottomUp(ctx, func(id NodeID, node Node) error {
+			counts[id] = counts[node.Lo].add(counts[node.Hi])
+			return nil
+		})
+		return counts, err
+	})
+}
+
+// LogCount returns the solution count in scaled floating point.
+//
+// This is a type-safe convenience method for LogCountEvaluator, for diagrams
+// whose counts are too large for Count and too costly for CountBig.
+func (z *ZDD) LogCount(ctx context.Context) (LogCountResult, error) {
+	result, err := EvaluateZDD(ctx, z, LogCountEvaluator{})
+	if err != nil {
+		return LogCountResult{}, err
+	}
+	return result.(LogCountResult), nil
+}
+
+// ratio returns a/b as a float64; b must be non-zero
+func (a scaledCount) ratio(b scaledCount) float64 {
+	return math.Ldexp(a.m/b.m, a.e-b.e)
+}
@@ -0,0 +1,254 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+)
+
+// BDD is an ordinary reduced ordered binary decision diagram over the same
+// variables as a ZDD.
+//
+// A BDD represents the Boolean function whose satisfying assignments are the
+// solutions, with ZeroNode and OneNode as the false and true terminals. Unlike
+// a ZDD it removes nodes whose arcs lead to the same child, so a variable
+// that does not appear on a path is a don't-care rather than unselected.
+// Functions with many don't-cares, such as implications, are smaller as BDDs,
+// while sparse families are smaller as ZDDs. Convert with ZDD.ToBDD and
+// BDD.ToZDD. A BDD is immutable.
+type BDD struct {
+	root  NodeID
+	nodes *NodeTable
+	vars  int
+}
+
+// ToBDD converts the ZDD into the BDD of its characteristic function, which is
+// true exactly for the assignments whose selected variables form a solution.
+//
+// Every ZDD node is visited once per level it spans, so the conversion takes
+// O(Size()·Variables()) time.
+func (z *ZDD) ToBDD(ctx context.Context) (*BDD, error) {
+	b := &BDD{nodes: NewNodeTable(), vars: z.vars}
+	conv := &diagramConversion{ctx: ctx, src: z.nodes, dst: b.nodes, memo: make(map[nodeLevel]NodeID)}
+
+	root, err := conv.toBDD(z.family(), z.vars)
+	if err != nil {
+		return nil, fmt.Errorf("BDD conversion failed: %w", err)
+	}
+	b.root = root
+	return b, nil
+}
+
+// ToZDD converts the BDD into the ZDD of its satisfying assignments.
+//
+// Don't-care variables become nodes whose arcs lead to the same child, so the
+// result takes O(Size()·Variables()) time and may be larger than the BDD. The
+// ZDD is created with the given options.
+func (b *BDD) ToZDD(ctx context.Context, opts ...Option) (*ZDD, error) {
+	z := NewZDD(b.vars, opts...)
+	conv := &diagramConversion{ctx: ctx, src: b.nodes, dst: z.nodes, memo: make(map[nodeLevel]NodeID)}
+
+	root, err := conv.toZDD(b.root, b.vars)
+	if err != nil {
+		return nil, fmt.Errorf("ZDD conversion failed: %w", err)
+	}
+	z.root = root
+	return z, nil
+}
+
+// Variables returns the number of variables of the BDD.
+func (b *BDD) Variables() int {
+	return b.vars
+}
+
+// Root returns the root node of the BDD.
+func (b *BDD) Root() NodeID {
+	return b.root
+}
+
+// GetNode retrieves a node of the BDD by ID.
+func (b *BDD) GetNode(id NodeID) (Node, error) {
+	return b.nodes.GetNode(id)
+}
+
+// Size returns the number of nodes of the BDD, including both terminals.
+func (b *BDD) Size() int {
+	seen := make(map[NodeID]bool)
+	var visit func(id NodeID)
+	visit = func(id NodeID) {
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		if id == ZeroNode || id == OneNode {
+			return
+		}
+		node, _ := b.nodes.GetNode(id)
+		visit(node.Lo)
+		visit(node.Hi)
+	}
+	visit(ZeroNode)
+	visit(OneNode)
+	visit(b.root)
+	return len(seen)
+}
+
+// Eval reports whether the function is true when exactly the variables in
+// selected are true.
+func (b *BDD) Eval(selected []int) bool {
+	set := make(map[int]bool, len(selected))
+	for _, v := range selected {
+		set[v] = true
+	}
+
+	id := b.root
+	for id != ZeroNode && id != OneNode {
+		node, err := b.nodes.GetNode(id)
+		if err != nil {
+			return false
+		}
+		id = node.Lo
+		if set[node.Level] {
+			id = node.Hi
+		}
+	}
+	return id == OneNode
+}
+
+// IsTautology reports whether the function is true for every assignment.
+func (b *BDD) IsTautology() bool {
+	return b.root == OneNode
+}
+
+// IsSatisfiable reports whether the function is true for some assignment.
+func (b *BDD) IsSatisfiable() bool {
+	return b.root != ZeroNode
+}
+
+// Implies reports whether every assignment satisfying b also satisfies other.
+//
+// Both diagrams are walked in step without building new nodes. For BDDs
+// converted from ZDDs over the same variables this is the same test as
+// ZDD.IsSubfamilyOf.
+func (b *BDD) Implies(other *BDD) bool {
+	if other == nil {
+		return false
+	}
+	return b.implies(other, b.root, other.root, make(map[nodePair]bool))
+}
+
+// implies reports whether node f of b implies node g of other
+func (b *BDD) implies(other *BDD, f, g NodeID, memo map[nodePair]bool) bool {
+	if f == ZeroNode || g == OneNode {
+		return true
+	}
+	if f == OneNode && g == ZeroNode {
+		return false
+	}
+	key := nodePair{f, g}
+	if r, ok := memo[key]; ok {
+		return r
+	}
+
+	// split both functions on the top variable of either
+	fn, _ := b.nodes.GetNode(f)
+	gn, _ := other.nodes.GetNode(g)
+	level := max(fn.Level, gn.Level)
+	f0, f1 := f, f
+	if fn.Level == level {
+		f0, f1 = fn.Lo, fn.Hi
+	}
+	g0, g1 := g, g
+	if gn.Level == level {
+		g0, g1 = gn.Lo, gn.Hi
+	}
+
+	r := b.implies(other, f0, g0, memo) && b.implies(other, f1, g1, memo)
+	memo[key] = r
+	return r
+}
+
+// nodeLevel identifies a source node reached at a given level
+type nodeLevel struct {
+	id    NodeID
+	level int
+}
+
+// diagramConversion copies a diagram between the ZDD and BDD reduction rules
+type diagramConversion struct {
+	ctx      context.Context
+	src, dst *NodeTable
+	memo     map[nodeLevel]NodeID
+}
+
+// toBDD returns the BDD for ZDD node f over variables level down to 1
+func (c *diagramConversion) toBDD(f NodeID, level int) (NodeID, error) {
+	if f == ZeroNode || (f == OneNode && level == 0) {
+		return f, nil
+	}
+	key := nodeLevel{f, level}
+	if r, ok := c.memo[key]; ok {
+		return r, nil
+	}
+	if err := c.ctx.Err(); err != nil {
+		return NullNode, err
+	}
+
+	node, err := c.src.GetNode(f)
+	if err != nil {
+		return NullNode, err
+	}
+
+	// variables skipped by the ZDD are unselected in every solution
+	lo, hi := f, ZeroNode
+	if node.Level == level {
+		lo, hi = node.Lo, node.Hi
+	}
+	if lo, err = c.toBDD(lo, level-1); err != nil {
+		return NullNode, err
+	}
+	if hi, err = c.toBDD(hi, level-1); err != nil {
+		return NullNode, err
+	}
+
+	r := lo
+	if lo != hi {
+		r = c.dst.insert(level, lo, hi)
+	}
+	c.memo[key] = r
+	return r, nil
+}
+
+// toZDD returns the ZDD for BDD node f over variables level down to 1
+func (c *diagramConversion) toZDD(f NodeID, level int) (NodeID, error) {
+	if f == ZeroNode || (f == OneNode && level == 0) {
+		return f, nil
+	}
+	key := nodeLevel{f, level}
+	if r, ok := c.memo[key]; ok {
+		return r, nil
+	}
+	if err := c.ctx.Err(); err != nil {
+		return NullNode, err
+	}
+
+	node, err := c.src.GetNode(f)
+	if err != nil {
+		return NullNode, err
+	}
+
+	// variables skipped by the BDD may be selected or not
+	lo, hi := f, f
+	if node.Level == level {
+		lo, hi = node.Lo, node.Hi
+	}
+	if lo, err = c.toZDD(lo, level-1); err != nil {
+		return NullNode, err
+	}
+	if hi, err = c.toZDD(hi, level-1); err != nil {
+		return NullNode, err
+	}
+
+	r := c.dst.AddNode(level, lo, hi)
+	c.memo[key] = r
+	return r, nil
+}
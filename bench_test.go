@@ -0,0 +1,72 @@
+package gozdd_test
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"testing"
+
+	"github.com/zzenonn/go-zdd"
+)
+
+// costlySpec is a knapsack whose transitions do a fixed amount of extra
+// hashing, standing in for constraints that are expensive to check
+type costlySpec struct {
+	weights  []int
+	capacity int
+	work     int
+}
+
+func (s *costlySpec) Variables() int { return len(s.weights) - 1 }
+
+func (s *costlySpec) InitialState() gozdd.State { return gozdd.NewIntState(0) }
+
+func (s *costlySpec) GetChild(ctx context.Context, state gozdd.State, level int, take bool) (gozdd.State, error) {
+	h := fnv.New64a()
+	for i := 0; i < s.work; i++ {
+		fmt.Fprint(h, level, i)
+	}
+
+	weight := state.(*gozdd.IntState).Values[0]
+	if take {
+		weight += s.weights[level]
+	}
+	if weight > s.capacity {
+		return nil, fmt.Errorf("over capacity")
+	}
+	return gozdd.NewIntState(weight), nil
+}
+
+func (s *costlySpec) IsValid(state gozdd.State) bool { return true }
+
+// BenchmarkBuildParallel compares sequential construction with level-by-level
+// construction on several workers, with and without deterministic node IDs.
+func BenchmarkBuildParallel(b *testing.B) {
+	spec := &costlySpec{weights: make([]int, 41), capacity: 200, work: 50}
+	for l := 1; l < len(spec.weights); l++ {
+		spec.weights[l] = 3 + l*7%23
+	}
+
+	ctx := context.Background()
+	for _, workers := range []int{1, 2, 4, 8} {
+		for _, deterministic := range []bool{false, true} {
+			if deterministic && workers == 1 {
+				continue
+			}
+			opts := []gozdd.Option{gozdd.WithParallel(workers)}
+			name := fmt.Sprintf("workers=%d", workers)
+			if deterministic {
+				opts = append(opts, gozdd.WithDeterministic())
+				name += "/deterministic"
+			}
+			b.Run(name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					zdd := gozdd.NewZDD(spec.Variables(), opts...)
+					if err := zdd.Build(ctx, spec); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// BottleneckEvaluator finds the solution whose most costly selected variable
+// is as cheap as possible, as in load balancing where the heaviest load
+// matters rather than the total.
+//
+// The recurrence replaces the sum of CostEvaluator with a maximum: a single
+// bottom-up pass keeps the best bottleneck below every node, so no solutions
+// are enumerated. The empty solution has no selected variable and a
+// bottleneck of -Inf, so it is optimal whenever it is a solution. Among
+// solutions with equal bottlenecks the first in the order documented at
+// WithStableOrder is returned. The result is an OptimalResult whose Cost is
+// the bottleneck.
+type BottleneckEvaluator struct {
+	// Costs specifies the cost of each variable (1-based indexing)
+	Costs []float64
+
+	// SparseCosts maps variables to their cost; variables without an entry
+	// cost 0. Use instead of Costs when few variables have costs.
+	SparseCosts map[int]float64
+}
+
+// Evaluate finds the solution with the smallest bottleneck
+func (e BottleneckEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
+	costs, _, err := resolveCosts(zdd.vars, e.Costs, e.SparseCosts, nil)
+	if err != nil {
+		return OptimalResult{Found: false}, err
+	}
+	root := zdd.family()
+	if root == ZeroNode {
+		return OptimalResult{Found: false}, nil
+	}
+
+	// bottleneck[n] is the best bottleneck below n; ZeroNode has none
+	bottleneck := map[NodeID]float64{OneNode: math.Inf(-1)}
+	err = zdd.bottomUp(ctx, func(id NodeID, node Node) error {
+		// quasi-reduced diagrams keep nodes whose Hi arc has no solutions
+		lo, loOK := bottleneck[node.Lo]
+		hi, hiOK := bottleneck[node.Hi]
+		if !hiOK {
+			bottleneck[id] = lo
+			return nil
+		}
+		best := math.Max(hi, costs[node.Level])
+		if loOK && lo <= best {
+			best = lo
+		}
+		bottleneck[id] = best
+		return nil
+	})
+	if err != nil {
+		return OptimalResult{Found: false}, fmt.Errorf("bottleneck evaluation failed: %w", err)
+	}
+
+	// follow Lo whenever it still reaches the optimal bottleneck; a maximum
+	// does not pin down the completion below Hi, so the walk uses the
+	// optimum as a bound rather than each node's own best
+	cost := bottleneck[root]
+	vars := []int{}
+	for id := root; id != OneNode; {
+		node, err := zdd.GetNode(id)
+		if err != nil {
+			return OptimalResult{Found: false}, err
+		}
+		if lo, ok := bottleneck[node.Lo]; ok && lo <= cost {
+			id = node.Lo
+			continue
+		}
+		vars = append(vars, node.Level)
+		id = node.Hi
+	}
+	for i, j := 0, len(vars)-1; i < j; i, j = i+1, j-1 {
+		vars[i], vars[j] = vars[j], vars[i]
+	}
+
+	solution := &Solution{Variables: vars, Cost: cost, Metadata: make(map[string]interface{})}
+	return OptimalResult{Solution: solution, Cost: cost, Found: true}, nil
+}
+
+// FindBottleneck returns the solution whose most costly selected variable is
+// as cheap as possible.
+//
+// This is a type-safe convenience method for BottleneckEvaluator.
+// Returns ErrInfeasible if the ZDD has no solutions.
+func (z *ZDD) FindBottleneck(ctx context.Context, costs []float64) (*Solution, error) {
+	return z.extremeSolution(ctx, BottleneckEvaluator{Costs: costs})
+}
@@ -0,0 +1,107 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+)
+
+// EvaluateBottomUp computes a value of type T for the ZDD by combining the
+// values of each node's children, from the terminals up to the root.
+//
+// terminal gives the values of the 0-terminal (false) and the 1-terminal
+// (true). merge gives the value of a node at level from the values of its Lo
+// and Hi children; variables skipped by an arc are unselected in every
+// solution through it, so merge sees only the levels that have nodes, and
+// EvaluateBottomUpSkips reads them otherwise. The framework visits every
+// reachable node once, level by level from the bottom, memoizes the values,
+// and checks ctx between nodes, so evaluators written with it need no
+// recursion and no type assertions. The solution count, for instance, is
+//
+//	count, err := gozdd.EvaluateBottomUp(ctx, zdd,
+//		func(one bool) int64 {
+//			if one {
+//				return 1
+//			}
+//			return 0
+//		},
+//		func(level int, lo, hi int64) int64 { return lo + hi })
+//
+// An unbuilt ZDD has the value of the 0-terminal.
+func EvaluateBottomUp[T any](ctx context.Context, zdd *ZDD, terminal func(one bool) T, merge func(level int, lo, hi T) T) (T, error) {
+	return EvaluateBottomUpSkips(ctx, zdd, terminal, merge, nil)
+}
+
+// EvaluateBottomUpSkips is EvaluateBottomUp with control over the variables
+// that arcs skip.
+//
+// Before merge sees a child's value, skip maps it across the levels the arc
+// jumps over: levels is the number of variables strictly between the node
+// and the child, counting the levels down to the terminals. The root's value
+// is mapped across the levels above it in the same way. skip is called only
+// for levels > 0; a nil skip leaves values unchanged, which is
+// EvaluateBottomUp. Counting skipped variables as free, as for models that
+// use SkipState to leave variables unconstrained, is
+//
+//	count, err := gozdd.EvaluateBottomUpSkips(ctx, zdd,
+//		func(one bool) int64 {
+//			if one {
+//				return 1
+//			}
+//			return 0
+//		},
+//		func(level int, lo, hi int64) int64 { return lo + hi },
+//		func(levels int, count int64) int64 { return count << levels })
+func EvaluateBottomUpSkips[T any](ctx context.Context, zdd *ZDD, terminal func(one bool) T, merge func(level int, lo, hi T) T, skip func(levels int, value T) T) (T, error) {
+	values := map[NodeID]T{
+		ZeroNode: terminal(false),
+		OneNode:  terminal(true),
+	}
+
+	// across maps the value of child from the level above it
+	across := func(from int, child NodeID) T {
+		levels := from - 1 - levelOf(zdd, child)
+		if skip == nil || levels == 0 {
+			return values[child]
+		}
+		return skip(levels, values[child])
+	}
+
+	root := zdd.family()
+	if _, ok := values[root]; ok {
+		return across(zdd.vars+1, root), nil
+	}
+
+	err := zdd.bottomUp(ctx, func(id NodeID, node Node) error {
+		values[id] = merge(node.Level, across(node.Level, node.Lo), across(node.Level, node.Hi))
+		return nil
+	})
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("bottom-up evaluation failed: %w", err)
+	}
+	return across(zdd.vars+1, root), nil
+}
+
+// bottomUp calls visit for every non-terminal node reachable from the root,
+// children before parents.
+//
+// Nodes are visited level by level from the bottom, so arbitrarily deep
+// diagrams need no recursion. Stops at the first error from visit or ctx.
+func (z *ZDD) bottomUp(ctx context.Context, visit func(id NodeID, node Node) error) error {
+	// children sit on lower levels, so each layer only needs layers below it
+	for l := 1; l <= z.vars; l++ {
+		for _, id := range z.layer(l) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			node, err := z.GetNode(id)
+			if err != nil {
+				return err
+			}
+			if err := visit(id, node); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package gozdd
+
+// Branching tells the builder which assignments of a variable to explore.
+type Branching int
+
+const (
+	// BranchBoth explores both assignments through GetChild, as for specs
+	// that do not implement BranchingSpec
+	BranchBoth Branching = iota
+
+	// BranchTakeOnly forces the variable to be selected; only the take
+	// branch is explored
+	BranchTakeOnly
+
+	// BranchLeaveOnly forces the variable to be left unselected; only the
+	// other branch is explored
+	BranchLeaveOnly
+
+	// BranchDontCare makes the variable irrelevant: both assignments lead
+	// to the same child, which GetChild computes once with take false
+	BranchDontCare
+)
+
+// BranchingSpec is an optional interface for ConstraintSpecs that know,
+// before any transition, which assignments of a variable are possible.
+//
+// When a spec implements BranchingSpec, Build asks it for each state it
+// expands. A forced branch creates its node with the other arc leading to
+// the 0-terminal, without calling GetChild for the excluded assignment, so
+// a spec needs no error return to rule it out. A don't-care variable gets a
+// node whose arcs both lead to the same child, which doubles the solutions
+// through it; GetChild runs once for both. Filter honours the declarations
+// as well.
+type BranchingSpec interface {
+	// Branching returns the assignments of the variable at level to explore
+	// from state.
+	Branching(state State, level int) Branching
+}
+
+// branchingOf returns the branching spec declares for state at level
+func branchingOf(spec ConstraintSpec, state State, level int) Branching {
+	if bs, ok := unwrapSpec(spec).(BranchingSpec); ok {
+		return bs.Branching(state, level)
+	}
+	return BranchBoth
+}
+
+// explores reports whether the branch take is explored
+func (b Branching) explores(take bool) bool {
+	switch b {
+	case BranchTakeOnly:
+		return take
+	case BranchLeaveOnly:
+		return !take
+	default:
+		return true
+	}
+}
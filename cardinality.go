@@ -0,0 +1,248 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// CardinalityEvaluator counts the solutions of each size.
+//
+// The result is a []*big.Int of length Variables()+1 whose entry k is the
+// number of solutions selecting exactly k variables. Each node holds the
+// generating polynomial of its solutions by size, the sum of its Lo
+// polynomial and its Hi polynomial shifted by one, so the pass is bottom-up
+// without enumeration. Exact arithmetic keeps the histogram correct for
+// families with more than 2^63 solutions.
+type CardinalityEvaluator struct{}
+
+// Evaluate computes the number of solutions per size
+func (e CardinalityEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
+	memo := map[NodeID][]*big.Int{
+		ZeroNode: nil,
+		OneNode:  {big.NewInt(1)},
+	}
+	err := zdd.bottomUp(ctx, func(id NodeID, node Node) error {
+		lo, hi := memo[node.Lo], memo[node.Hi]
+
+		// selecting the variable adds one to the size of every Hi solution
+		poly := make([]*big.Int, max(len(lo), len(hi)+1))
+		for k := range poly {
+			poly[k] = new(big.Int)
+			if k < len(lo) {
+				poly[k].Add(poly[k], lo[k])
+			}
+			if k > 0 && k <= len(hi) {
+				poly[k].Add(poly[k], hi[k-1])
+			}
+		}
+		memo[id] = poly
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cardinality evaluation failed: %w", err)
+	}
+	poly := memo[zdd.family()]
+
+	counts := make([]*big.Int, zdd.vars+1)
+	for k := range counts {
+		counts[k] = new(big.Int)
+		if k < len(poly) {
+			counts[k].Set(poly[k])
+		}
+	}
+	return counts, nil
+}
+
+// CardinalityDistribution returns the number of solutions of each size.
+//
+// This is a type-safe convenience method for CardinalityEvaluator. Entry k of
+// the result counts the solutions selecting exactly k variables.
+func (z *ZDD) CardinalityDistribution(ctx context.Context) ([]*big.Int, error) {
+	result, err := EvaluateZDD(ctx, z, CardinalityEvaluator{})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]*big.Int), nil
+}
+
+// CardinalityCountEvaluator counts the solutions selecting exactly K
+// variables.
+//
+// It answers a single entry of CardinalityEvaluator's histogram in a
+// bottom-up pass that keeps each node's size polynomial only up to degree K,
+// so small sizes stay cheap on diagrams over many variables. The result is
+// an int64; counts that exceed its range fail with ErrCountOverflow, as for
+// CountEvaluator.
+type CardinalityCountEvaluator struct {
+	// K is the number of selected variables
+	K int
+}
+
+// Evaluate counts the solutions of size K
+func (e CardinalityCountEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
+	if e.K < 0 || e.K > zdd.vars {
+		return int64(0), nil
+	}
+
+	// coefficients beyond the int64 range are -1
+	memo := map[NodeID][]int64{
+		ZeroNode: nil,
+		OneNode:  {1},
+	}
+	err := zdd.bottomUp(ctx, func(id NodeID, node Node) error {
+		lo, hi := memo[node.Lo], memo[node.Hi]
+		poly := make([]int64, min(max(len(lo), len(hi)+1), e.K+1))
+		for k := range poly {
+			var a, b int64
+			if k < len(lo) {
+				a = lo[k]
+			}
+			if k > 0 && k <= len(hi) {
+				b = hi[k-1]
+			}
+			if a < 0 || b < 0 || a > math.MaxInt64-b {
+				poly[k] = -1
+			} else {
+				poly[k] = a + b
+			}
+		}
+		memo[id] = poly
+		return nil
+	})
+	if err != nil {
+		return int64(0), fmt.Errorf("cardinality evaluation failed: %w", err)
+	}
+
+	poly := memo[zdd.family()]
+	if e.K >= len(poly) {
+		return int64(0), nil
+	}
+	if poly[e.K] < 0 {
+		return int64(0), fmt.Errorf("cardinality evaluation failed: %w", ErrCountOverflow)
+	}
+	return poly[e.K], nil
+}
+
+// CountWithCardinality returns the number of solutions selecting exactly k
+// variables.
+//
+// This is a type-safe convenience method for CardinalityCountEvaluator. It
+// is cheaper than CardinalityDistribution when only one size is needed.
+func (z *ZDD) CountWithCardinality(ctx context.Context, k int) (int64, error) {
+	result, err := EvaluateZDD(ctx, z, CardinalityCountEvaluator{K: k})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int64), nil
+}
+
+// MinCardinalityEvaluator finds a solution with the fewest selected
+// variables.
+//
+// The result is an OptimalResult whose Cost is the smallest solution size. A
+// single bottom-up pass computes the smallest size below every node, so no
+// solutions are enumerated. Among solutions of that size the witness is the
+// first in the order documented at WithStableOrder.
+type MinCardinalityEvaluator struct{}
+
+// Evaluate finds a smallest solution
+func (e MinCardinalityEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
+	return extremeCardinality(ctx, zdd, false)
+}
+
+// MaxCardinalityEvaluator finds a solution with the most selected variables.
+//
+// It is the counterpart of MinCardinalityEvaluator; the result's Cost is the
+// largest solution size.
+type MaxCardinalityEvaluator struct{}
+
+// Evaluate finds a largest solution
+func (e MaxCardinalityEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
+	return extremeCardinality(ctx, zdd, true)
+}
+
+// extremeCardinality finds a smallest or largest solution.
+//
+// Lo is preferred on ties, which yields the first solution in stable order.
+func extremeCardinality(ctx context.Context, zdd *ZDD, largest bool) (OptimalResult, error) {
+	root := zdd.family()
+	if root == ZeroNode {
+		return OptimalResult{Found: false}, nil
+	}
+
+	// size[n] is the extreme solution size below n; ZeroNode has none
+	size := map[NodeID]int{OneNode: 0}
+	err := zdd.bottomUp(ctx, func(id NodeID, node Node) error {
+		// quasi-reduced diagrams keep nodes whose Hi arc has no solutions
+		hi, hiOK := size[node.Hi]
+		lo, loOK := size[node.Lo]
+		switch {
+		case !hiOK:
+			size[id] = lo
+		case loOK && (lo == hi+1 || (lo < hi+1) != largest):
+			size[id] = lo
+		default:
+			size[id] = hi + 1
+		}
+		return nil
+	})
+	if err != nil {
+		return OptimalResult{Found: false}, fmt.Errorf("cardinality evaluation failed: %w", err)
+	}
+
+	// follow the arcs that attain the extreme size
+	var vars []int
+	for id := root; id != OneNode; {
+		node, err := zdd.GetNode(id)
+		if err != nil {
+			return OptimalResult{Found: false}, err
+		}
+		if lo, ok := size[node.Lo]; ok && lo == size[id] {
+			id = node.Lo
+			continue
+		}
+		vars = append(vars, node.Level)
+		id = node.Hi
+	}
+	for i, j := 0, len(vars)-1; i < j; i, j = i+1, j-1 {
+		vars[i], vars[j] = vars[j], vars[i]
+	}
+	if vars == nil {
+		vars = []int{}
+	}
+
+	cost := float64(size[root])
+	solution := &Solution{Variables: vars, Cost: cost, Metadata: make(map[string]interface{})}
+	return OptimalResult{Solution: solution, Cost: cost, Found: true}, nil
+}
+
+// MinCardinality returns a solution with the fewest selected variables.
+//
+// This is a type-safe convenience method for MinCardinalityEvaluator.
+// Returns ErrInfeasible if the ZDD has no solutions.
+func (z *ZDD) MinCardinality(ctx context.Context) (*Solution, error) {
+	return z.extremeSolution(ctx, MinCardinalityEvaluator{})
+}
+
+// MaxCardinality returns a solution with the most selected variables.
+//
+// This is a type-safe convenience method for MaxCardinalityEvaluator.
+// Returns ErrInfeasible if the ZDD has no solutions.
+func (z *ZDD) MaxCardinality(ctx context.Context) (*Solution, error) {
+	return z.extremeSolution(ctx, MaxCardinalityEvaluator{})
+}
+
+// extremeSolution runs an evaluator producing an OptimalResult
+func (z *ZDD) extremeSolution(ctx context.Context, evaluator Evaluator) (*Solution, error) {
+	result, err := EvaluateZDD(ctx, z, evaluator)
+	if err != nil {
+		return nil, err
+	}
+	r := result.(OptimalResult)
+	if !r.Found {
+		return nil, ErrInfeasible
+	}
+	return r.Solution, nil
+}
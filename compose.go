@@ -0,0 +1,58 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+)
+
+// Compose substitutes variable v by the family f.
+//
+// Every solution that does not contain v is kept unchanged. Every solution S
+// that contains v is replaced by the sets (S \ {v}) ∪ T for each solution T of
+// f; variables selected by both S and T appear once. Solutions of f may select
+// v itself. This supports hierarchical modeling, where a macro decision v
+// expands into one of several detailed decision patterns.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - v: The variable to substitute (1-based)
+//   - f: The replacement family, over at most z.Variables() variables
+//
+// Returns a new ZDD with the same variables; z and f are not modified.
+func (z *ZDD) Compose(ctx context.Context, v int, f *ZDD) (*ZDD, error) {
+	if err := z.checkVariable(v); err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, fmt.Errorf("%w: replacement ZDD is nil", ErrInvalidNode)
+	}
+	if f.vars > z.vars {
+		return nil, fmt.Errorf("%w: replacement uses %d variables, ZDD has %d", ErrInvalidVariable, f.vars, z.vars)
+	}
+
+	ops, roots, err := workspace(ctx, z, f)
+	if err != nil {
+		return nil, err
+	}
+
+	without, err := ops.subset0(roots[0], v)
+	if err != nil {
+		return nil, fmt.Errorf("compose failed: %w", err)
+	}
+	with, err := ops.subset1(roots[0], v)
+	if err != nil {
+		return nil, fmt.Errorf("compose failed: %w", err)
+	}
+
+	expanded, err := ops.join(with, roots[1])
+	if err != nil {
+		return nil, fmt.Errorf("compose failed: %w", err)
+	}
+
+	root, err := ops.union(without, expanded)
+	if err != nil {
+		return nil, fmt.Errorf("compose failed: %w", err)
+	}
+
+	return z.derive(z.vars, ops.nt, root)
+}
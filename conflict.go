@@ -0,0 +1,95 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+)
+
+// ConflictSet is a minimal subset of constraints whose conjunction is infeasible.
+//
+// Removing any single member of the set makes the remaining members feasible,
+// which is the ZDD analogue of an unsatisfiable core.
+type ConflictSet struct {
+	// Indices are the positions of the conflicting constraints in the composite spec
+	Indices []int
+
+	// Names identify the conflicting constraints for reporting purposes
+	Names []string
+
+	// Builds is the number of ZDD constructions performed during minimization
+	Builds int
+}
+
+// FindMinimalConflict finds a minimal subset of constraints that is already infeasible.
+//
+// The search uses deletion-based minimization: starting from the full constraint
+// set, each constraint is tentatively removed and the ZDD is rebuilt. If the
+// family stays empty the constraint is dropped for good, otherwise it is kept.
+// This requires one build per constraint.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout handling
+//   - spec: The composite specification whose ZDD is empty
+//   - opts: Configuration options applied to every build
+//
+// Returns ErrFeasible if the full spec admits at least one solution.
+// An empty ConflictSet means the spec is infeasible even without constraints,
+// for example because IsValid rejects every terminal state.
+func FindMinimalConflict(ctx context.Context, spec *CompositeConstraintSpec, opts ...Option) (*ConflictSet, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("%w: spec is nil", ErrInvalidConstraint)
+	}
+
+	builds := 0
+	infeasible := func(indices []int) (bool, error) {
+		builds++
+		constraints := make([]Constraint, len(indices))
+		for i, idx := range indices {
+			constraints[i] = spec.constraints[idx]
+		}
+
+		zdd := NewZDD(spec.vars, opts...)
+		if err := zdd.Build(ctx, spec.withConstraints(constraints)); err != nil {
+			return false, err
+		}
+		return zdd.Root() == ZeroNode, nil
+	}
+
+	core := make([]int, len(spec.constraints))
+	for i := range core {
+		core[i] = i
+	}
+
+	empty, err := infeasible(core)
+	if err != nil {
+		return nil, fmt.Errorf("full build: %w", err)
+	}
+	if !empty {
+		return nil, fmt.Errorf("%w: spec has at least one solution", ErrFeasible)
+	}
+
+	// Deletion filter: drop every constraint that is not needed for infeasibility
+	for i := 0; i < len(core); {
+		candidate := make([]int, 0, len(core)-1)
+		candidate = append(candidate, core[:i]...)
+		candidate = append(candidate, core[i+1:]...)
+
+		empty, err := infeasible(candidate)
+		if err != nil {
+			return nil, fmt.Errorf("build without constraint %d: %w", core[i], err)
+		}
+
+		if empty {
+			core = candidate
+		} else {
+			i++
+		}
+	}
+
+	names := make([]string, len(core))
+	for i, idx := range core {
+		names[i] = constraintName(spec.constraints[idx])
+	}
+
+	return &ConflictSet{Indices: core, Names: names, Builds: builds}, nil
+}
@@ -3,6 +3,7 @@ package gozdd
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 )
 
 // Constraint represents a single constraint that can be evaluated during ZDD construction.
@@ -32,6 +33,28 @@ type Constraint interface {
 	CanPrune(state State, level int) bool
 }
 
+// StatefulConstraint is a Constraint that maintains its own slice of the
+// construction state.
+//
+// CompositeConstraintSpec allocates one state per stateful constraint, routes
+// that state (instead of the shared spec state) to Validate and CanPrune, and
+// advances it with Apply after every accepted assignment. This lets constraints
+// such as CountConstraint and SumConstraint track their progress without the
+// spec knowing their bookkeeping.
+type StatefulConstraint interface {
+	Constraint
+	
+	// InitialState returns the constraint's state before any assignment.
+	InitialState() State
+	
+	// Apply returns the constraint's state after assigning a variable.
+	//
+	// Validate is called with the same arguments first, so Apply only runs
+	// for accepted transitions. Implementations must not modify state;
+	// return a new value instead.
+	Apply(state State, level int, take bool) State
+}
+
 // BasicState provides a simple State implementation for common constraint types.
 //
 // Applications can embed BasicState and add domain-specific fields,
@@ -130,6 +153,20 @@ type CountConstraint struct {
 	CounterIndex int
 }
 
+// InitialState returns a BasicState with a zero counter at CounterIndex
+func (c CountConstraint) InitialState() State {
+	return BasicState{Counters: make([]int, c.CounterIndex+1)}
+}
+
+// Apply increments the counter when the variable is selected
+func (c CountConstraint) Apply(state State, level int, take bool) State {
+	s := state.Clone().(BasicState)
+	if take && c.CounterIndex < len(s.Counters) {
+		s.Counters[c.CounterIndex]++
+	}
+	return s
+}
+
 // Validate checks if the selection count constraint is satisfied
 func (c CountConstraint) Validate(ctx context.Context, state State, level int, take bool) error {
 	s, ok := state.(BasicState)
@@ -148,7 +185,7 @@ func (c CountConstraint) Validate(ctx context.Context, state State, level int, t
 	
 	// Check if count exceeds maximum
 	if count > c.Max {
-		return fmt.Errorf("count %d exceeds maximum %d", count, c.Max)
+		return fmt.Errorf("%w: %d > %d", ErrCountExceeded, count, c.Max)
 	}
 	
 	return nil
@@ -192,6 +229,20 @@ type SumConstraint struct {
 	Max float64
 }
 
+// InitialState returns a BasicState with a zero sum
+func (c SumConstraint) InitialState() State {
+	return BasicState{}
+}
+
+// Apply adds the variable's weight to the sum when it is selected
+func (c SumConstraint) Apply(state State, level int, take bool) State {
+	s := state.Clone().(BasicState)
+	if take && level > 0 && level < len(c.Weights) {
+		s.Sum += c.Weights[level]
+	}
+	return s
+}
+
 // Validate checks if the weighted sum constraint is satisfied
 func (c SumConstraint) Validate(ctx context.Context, state State, level int, take bool) error {
 	s, ok := state.(BasicState)
@@ -210,7 +261,7 @@ func (c SumConstraint) Validate(ctx context.Context, state State, level int, tak
 	
 	// Check if sum exceeds maximum
 	if sum > c.Max {
-		return fmt.Errorf("sum %.3f exceeds maximum %.3f", sum, c.Max)
+		return fmt.Errorf("%w: %.3f > %.3f", ErrSumExceeded, sum, c.Max)
 	}
 	
 	return nil
@@ -227,7 +278,7 @@ func (c SumConstraint) CanPrune(state State, level int) bool {
 	
 	// Calculate maximum possible sum from remaining variables
 	maxRemaining := 0.0
-	for i := 1; i < level && i < len(c.Weights); i++ {
+	for i := 1; i <= level && i < len(c.Weights); i++ {
 		if c.Weights[i] > 0 {
 			maxRemaining += c.Weights[i]
 		}
@@ -281,6 +332,27 @@ func (c CustomConstraint) CanPrune(state State, level int) bool {
 	return c.PruneFunc(state, level)
 }
 
+// ConstraintError reports which constraint of a CompositeConstraintSpec
+// rejected a branch.
+type ConstraintError struct {
+	// Index is the position of the constraint in NewCompositeSpec
+	Index int
+	
+	// Err is the reason the constraint gave, ErrPruned if CanPrune cut
+	// the branch
+	Err error
+}
+
+// Error returns the reason prefixed with the constraint index
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("constraint %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the reason
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
 // CompositeConstraintSpec combines multiple constraints into a single specification.
 //
 // This allows building complex constraint problems by composing simpler constraints.
@@ -289,6 +361,55 @@ type CompositeConstraintSpec struct {
 	vars        int
 	constraints []Constraint
 	initialState State
+	
+	// prunes counts the branches pruned by each constraint, indexed like constraints
+	prunes []int64
+	
+	// symmetry injects symmetry-breaking rules for declared groups (optional)
+	symmetry *symmetryBreaker
+	
+	// partOf maps a constraint index to its state slot, or -1 if not stateful
+	partOf []int
+	parts  int
+}
+
+// compositeState combines the shared spec state with the private states of
+// stateful constraints
+type compositeState struct {
+	shared State
+	parts  []State
+}
+
+// Clone creates a deep copy of the compositeState
+func (s *compositeState) Clone() State {
+	parts := make([]State, len(s.parts))
+	for i, p := range s.parts {
+		parts[i] = p.Clone()
+	}
+	return &compositeState{shared: s.shared.Clone(), parts: parts}
+}
+
+// Hash combines the hashes of the shared and constraint states
+func (s *compositeState) Hash() uint64 {
+	hash := s.shared.Hash()
+	for _, p := range s.parts {
+		hash = hash*1099511628211 ^ p.Hash()
+	}
+	return hash
+}
+
+// Equal checks equality with another compositeState
+func (s *compositeState) Equal(other State) bool {
+	o, ok := other.(*compositeState)
+	if !ok || len(s.parts) != len(o.parts) {
+		return false
+	}
+	for i, p := range s.parts {
+		if !p.Equal(o.parts[i]) {
+			return false
+		}
+	}
+	return s.shared.Equal(o.shared)
 }
 
 // NewCompositeSpec creates a new composite constraint specification.
@@ -301,10 +422,58 @@ type CompositeConstraintSpec struct {
 // The initialState is cloned for each ZDD construction, so it's safe to reuse
 // the same spec for multiple ZDD builds.
 func NewCompositeSpec(vars int, initialState State, constraints ...Constraint) *CompositeConstraintSpec {
-	return &CompositeConstraintSpec{
+	spec := &CompositeConstraintSpec{
 		vars:         vars,
 		constraints:  constraints,
 		initialState: initialState,
+		prunes:       make([]int64, len(constraints)),
+		partOf:       make([]int, len(constraints)),
+	}
+	
+	for i, constraint := range constraints {
+		spec.partOf[i] = -1
+		if _, ok := constraint.(StatefulConstraint); ok {
+			spec.partOf[i] = spec.parts
+			spec.parts++
+		}
+	}
+	
+	return spec
+}
+
+// withConstraints returns a spec with the same variables, initial state and
+// symmetry declarations but a different constraint list
+func (c *CompositeConstraintSpec) withConstraints(constraints []Constraint) *CompositeConstraintSpec {
+	spec := NewCompositeSpec(c.vars, c.initialState, constraints...)
+	spec.symmetry = c.symmetry
+	return spec
+}
+
+// Constraints returns the constraints composed into this specification.
+func (c *CompositeConstraintSpec) Constraints() []Constraint {
+	constraints := make([]Constraint, len(c.constraints))
+	copy(constraints, c.constraints)
+	return constraints
+}
+
+// PruneCounts returns how many branches each constraint has pruned since the
+// spec was created or ResetPruneCounts was last called.
+//
+// The returned slice is indexed like the constraints passed to NewCompositeSpec.
+// A branch is attributed to the first constraint that rejects it, either through
+// Validate or CanPrune.
+func (c *CompositeConstraintSpec) PruneCounts() []int64 {
+	counts := make([]int64, len(c.prunes))
+	for i := range c.prunes {
+		counts[i] = atomic.LoadInt64(&c.prunes[i])
+	}
+	return counts
+}
+
+// ResetPruneCounts clears the per-constraint prune counters.
+func (c *CompositeConstraintSpec) ResetPruneCounts() {
+	for i := range c.prunes {
+		atomic.StoreInt64(&c.prunes[i], 0)
 	}
 }
 
@@ -315,7 +484,22 @@ func (c *CompositeConstraintSpec) Variables() int {
 
 // InitialState returns a clone of the initial state
 func (c *CompositeConstraintSpec) InitialState() State {
-	return c.initialState.Clone()
+	state := c.initialState.Clone()
+	
+	if c.parts > 0 {
+		parts := make([]State, c.parts)
+		for i, constraint := range c.constraints {
+			if p := c.partOf[i]; p >= 0 {
+				parts[p] = constraint.(StatefulConstraint).InitialState()
+			}
+		}
+		state = &compositeState{shared: state, parts: parts}
+	}
+	
+	if c.symmetry != nil {
+		return c.symmetry.initial(state)
+	}
+	return state
 }
 
 // GetChild applies all constraints to compute the new state after variable assignment.
@@ -324,10 +508,35 @@ func (c *CompositeConstraintSpec) InitialState() State {
 //   1. Clones the current state
 //   2. Updates the state based on the variable assignment
 //   3. Validates the transition against all constraints
-//   4. Returns the new state or an error if any constraint is violated
+//   4. Advances the private state of every StatefulConstraint via Apply
+//   5. Returns the new state or an error if any constraint is violated
+//
+// Stateful constraints see their own state before the assignment, as documented
+// on Constraint.Validate. Other constraints see the shared state after the
+// built-in BasicState update.
 func (c *CompositeConstraintSpec) GetChild(ctx context.Context, state State, level int, take bool) (State, error) {
+	if c.symmetry != nil {
+		return c.symmetry.child(ctx, state, level, take, c.getChild)
+	}
+	return c.getChild(ctx, state, level, take)
+}
+
+// getChild applies the constraints to a state without symmetry bookkeeping
+func (c *CompositeConstraintSpec) getChild(ctx context.Context, state State, level int, take bool) (State, error) {
+	shared := state
+	var parts []State
+	if cs, ok := state.(*compositeState); ok {
+		shared = cs.shared
+		parts = cs.parts
+	}
+	if len(parts) != c.parts {
+		// a bare state carries nothing for the stateful constraints to resume from
+		return nil, fmt.Errorf("%w: state %T holds %d stateful constraint states, want %d; start from InitialState",
+			ErrInvalidConstraint, state, len(parts), c.parts)
+	}
+	
 	// Clone state for the new branch
-	newState := state.Clone()
+	newState := shared.Clone()
 	
 	// Update state based on assignment (for BasicState)
 	if bs, ok := newState.(BasicState); ok {
@@ -341,18 +550,41 @@ func (c *CompositeConstraintSpec) GetChild(ctx context.Context, state State, lev
 		newState = bs
 	}
 	
+	newParts := make([]State, len(parts))
+	
 	// Validate against all constraints
 	for i, constraint := range c.constraints {
+		if p := c.partOf[i]; p >= 0 {
+			// Stateful constraints validate and advance their own state
+			sc := constraint.(StatefulConstraint)
+			if err := sc.Validate(ctx, parts[p], level, take); err != nil {
+				atomic.AddInt64(&c.prunes[i], 1)
+				return nil, &ConstraintError{Index: i, Err: err}
+			}
+			
+			newParts[p] = sc.Apply(parts[p], level, take)
+			if sc.CanPrune(newParts[p], level-1) {
+				atomic.AddInt64(&c.prunes[i], 1)
+				return nil, &ConstraintError{Index: i, Err: ErrPruned}
+			}
+			continue
+		}
+		
 		if err := constraint.Validate(ctx, newState, level, take); err != nil {
-			return nil, fmt.Errorf("constraint %d: %w", i, err)
+			atomic.AddInt64(&c.prunes[i], 1)
+			return nil, &ConstraintError{Index: i, Err: err}
 		}
 		
 		// Check for early pruning
 		if constraint.CanPrune(newState, level-1) {
-			return nil, fmt.Errorf("constraint %d: branch pruned", i)
+			atomic.AddInt64(&c.prunes[i], 1)
+			return nil, &ConstraintError{Index: i, Err: ErrPruned}
 		}
 	}
 	
+	if c.parts > 0 {
+		return &compositeState{shared: newState, parts: newParts}, nil
+	}
 	return newState, nil
 }
 
@@ -362,6 +594,13 @@ func (c *CompositeConstraintSpec) GetChild(ctx context.Context, state State, lev
 // For most constraints, validation during GetChild is sufficient,
 // but some constraints may need final validation (e.g., minimum counts).
 func (c *CompositeConstraintSpec) IsValid(state State) bool {
+	if s, ok := state.(*symmetryState); ok {
+		state = s.inner
+	}
+	if cs, ok := state.(*compositeState); ok {
+		state = cs.shared
+	}
+	
 	// For BasicState, check minimum count constraints
 	if bs, ok := state.(BasicState); ok {
 		// This is a simplified check - applications should implement
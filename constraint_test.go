@@ -0,0 +1,24 @@
+package gozdd_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zzenonn/go-zdd"
+)
+
+func TestCompositeSpecRejectsBareState(t *testing.T) {
+	spec := gozdd.NewCompositeSpec(3, gozdd.BasicState{Counters: []int{0}},
+		gozdd.CountConstraint{Min: 0, Max: 2})
+
+	_, err := spec.GetChild(context.Background(), gozdd.BasicState{Counters: []int{0}}, 3, true)
+	if !errors.Is(err, gozdd.ErrInvalidConstraint) {
+		t.Fatalf("GetChild on a bare state: got %v, want ErrInvalidConstraint", err)
+	}
+
+	child, err := spec.GetChild(context.Background(), spec.InitialState(), 3, true)
+	if err != nil || child == nil {
+		t.Fatalf("GetChild on the initial state: got %v, %v", child, err)
+	}
+}
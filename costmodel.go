@@ -0,0 +1,107 @@
+package gozdd
+
+import "context"
+
+// CostModel computes the cost of a decision at a level.
+//
+// Unlike a cost vector, a model can charge for not selecting a variable and
+// can derive costs from context, such as tiered pricing or per-level
+// discounts, without materializing vectors up front. Evaluators query each
+// level once per evaluation. Levels skipped by zero suppression are charged
+// their not-selected cost. AffineCost covers the common case of fixed
+// selected and not-selected costs plus a constant, and CostMatrix the same
+// costs given per level.
+type CostModel interface {
+	// Cost returns the cost of selecting (take) or not selecting the
+	// variable at level (1-based).
+	Cost(level int, take bool) float64
+}
+
+// CostFunc adapts a function to the CostModel interface.
+type CostFunc func(level int, take bool) float64
+
+// Cost calls f(level, take)
+func (f CostFunc) Cost(level int, take bool) float64 {
+	return f(level, take)
+}
+
+// AffineCost is a CostModel for affine objectives: every variable has a cost
+// for being selected and a cost for not being selected, and every solution
+// pays a constant on top.
+//
+// A solution's cost is Constant plus Selected[v] for each selected variable v
+// plus NotSelected[v] for each other variable. This expresses penalties for
+// not picking an option, which a selection cost vector cannot. Both vectors
+// are 1-based like Costs; a nil or short vector costs 0 for the missing
+// variables.
+type AffineCost struct {
+	// Selected is the cost of selecting each variable
+	Selected []float64
+
+	// NotSelected is the cost of not selecting each variable
+	NotSelected []float64
+
+	// Constant is added to the cost of every solution
+	Constant float64
+}
+
+// Cost returns the selected or not-selected cost of the variable at level
+func (a AffineCost) Cost(level int, take bool) float64 {
+	costs := a.NotSelected
+	if take {
+		costs = a.Selected
+	}
+	if level < len(costs) {
+		return costs[level]
+	}
+	return 0
+}
+
+// Offset returns the constant term
+func (a AffineCost) Offset() float64 {
+	return a.Constant
+}
+
+// CostMatrix is a CostModel given as a level×branch table: CostMatrix[l][0]
+// is the cost of not selecting the variable at level l and CostMatrix[l][1]
+// the cost of selecting it.
+//
+// The not-selected column carries fixed setup costs and per-level offsets
+// that a selection vector cannot express. Like Costs the matrix is 1-based,
+// so row 0 is ignored, and it must have a row for every variable. Unlike
+// other models, queries with a CostMatrix are memoized by WithResultCache.
+type CostMatrix [][2]float64
+
+// Cost returns the entry for the level and branch
+func (m CostMatrix) Cost(level int, take bool) float64 {
+	if take {
+		return m[level][1]
+	}
+	return m[level][0]
+}
+
+// offsetModel is implemented by cost models with a constant term, which
+// evaluators add to the cost of every solution
+type offsetModel interface {
+	Offset() float64
+}
+
+// FindKBestWithModel finds the k best solutions with costs computed by model.
+//
+// This is a type-safe convenience method for KBestEvaluator with Model set.
+func (z *ZDD) FindKBestWithModel(ctx context.Context, k int, model CostModel) ([]*Solution, error) {
+	result, err := EvaluateZDD(ctx, z, KBestEvaluator{K: k, Model: model})
+	if err != nil {
+		return nil, err
+	}
+	return result.(KBestResult).Solutions, nil
+}
+
+// FindKBestMatrix finds the k best solutions under a level×branch cost
+// matrix.
+//
+// This is a type-safe convenience method for KBestEvaluator with a
+// CostMatrix model.
+func (z *ZDD) FindKBestMatrix(ctx context.Context, k int, costs CostMatrix) ([]*Solution, error) {
+	return z.FindKBestWithModel(ctx, k, costs)
+}
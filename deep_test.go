@@ -0,0 +1,159 @@
+package gozdd_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime/debug"
+	"testing"
+
+	"github.com/zzenonn/go-zdd"
+)
+
+// deepVars is far more levels than a goroutine stack of deepStack bytes
+// can recurse through
+const (
+	deepVars  = 100000
+	deepStack = 1 << 20
+)
+
+// singletons builds the path-like family of sets with exactly one
+// variable by filtering the power set, one node per level
+func singletons(t *testing.T, ctx context.Context) *gozdd.ZDD {
+	t.Helper()
+	spec := gozdd.NewCompositeSpec(deepVars, gozdd.BasicState{Counters: []int{0}},
+		gozdd.CountConstraint{Min: 0, Max: 1})
+	zdd, err := gozdd.PowerSet(deepVars).Filter(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return zdd
+}
+
+func TestEvaluateDeepDiagram(t *testing.T) {
+	ctx := context.Background()
+
+	// a recursive evaluator overflows the limited stack, which is fatal
+	defer debug.SetMaxStack(debug.SetMaxStack(deepStack))
+
+	zdd := singletons(t, ctx)
+	costs := make([]float64, deepVars+1)
+	intCosts := make([]int64, deepVars+1)
+	for v := 1; v <= deepVars; v++ {
+		costs[v] = float64(deepVars - v + 1)
+		intCosts[v] = int64(deepVars - v + 1)
+	}
+
+	check := func(name string, got, want interface{}, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+		}
+	}
+	vars := func(s *gozdd.Solution) []int { return s.Variables }
+
+	count, err := zdd.Count(ctx)
+	check("Count", count, deepVars, err)
+
+	best, err := zdd.FindKBest(ctx, 3, costs)
+	check("FindKBest", solutionList(best), []string{"[100000]:1", "[99999]:2", "[99998]:3"}, err)
+
+	exact, err := zdd.FindKBestInt(ctx, 2, intCosts)
+	check("FindKBestInt", len(exact.Solutions), 2, err)
+
+	lex, err := zdd.FindLexicographic(ctx, costs)
+	check("FindLexicographic", vars(lex), []int{deepVars}, err)
+
+	bottleneck, err := zdd.FindBottleneck(ctx, costs)
+	check("FindBottleneck", vars(bottleneck), []int{deepVars}, err)
+
+	largest, err := zdd.MaxCardinality(ctx)
+	check("MaxCardinality", largest.Cost, 1, err)
+
+	smallest, err := zdd.MinCardinality(ctx)
+	check("MinCardinality", smallest.Cost, 1, err)
+
+	sensitivity, err := zdd.Sensitivity(ctx, costs)
+	check("Sensitivity", []float64{sensitivity.Optimal, sensitivity.ForcedIn[1], sensitivity.ForcedOut[1]},
+		[]float64{1, deepVars, 1}, err)
+
+	marginals, err := zdd.Marginals(ctx)
+	check("Marginals", math.Abs(marginals[1]*deepVars-1) < 1e-9, true, err)
+
+	samples, err := zdd.SampleBoltzmann(ctx, 5, costs, 1, 4304)
+	check("SampleBoltzmann", len(samples), 5, err)
+
+	approx, err := zdd.ApproxCount(ctx, 10, 4304)
+	check("ApproxCount", approx.Samples, 10, err)
+
+	sizes, err := zdd.CardinalityDistribution(ctx)
+	check("CardinalityDistribution", sizes[:3], []int{0, deepVars, 0}, err)
+
+	front, err := zdd.ParetoFront(ctx, costs, costs)
+	check("ParetoFront", solutionList(front), []string{"[100000]:1"}, err)
+
+	// the cheapest solutions lie at the bottom, so the search goes deep
+	ascending := make([]float64, deepVars+1)
+	for v := range ascending {
+		ascending[v] = float64(v)
+	}
+	anytime, err := zdd.FindKBestAnytime(ctx, 2, ascending)
+	check("FindKBestAnytime", solutionList(anytime.Solutions), []string{"[1]:1", "[2]:2"}, err)
+
+	nodes := 0
+	err = zdd.Visit(ctx, gozdd.VisitorFuncs{Pre: func(gozdd.NodeID, gozdd.Node) error {
+		nodes++
+		return nil
+	}})
+	check("Visit", nodes, deepVars+2, err)
+}
+
+func TestBuildDeepDepthFirst(t *testing.T) {
+	ctx := context.Background()
+
+	// at most two of the variables, three states per level
+	spec := &SimpleSpec{vars: deepVars, maxCount: 2}
+	depthFirst := gozdd.NewZDD(deepVars)
+	breadthFirst := gozdd.NewZDD(deepVars, gozdd.WithBreadthFirst())
+
+	limit := debug.SetMaxStack(deepStack)
+	err := depthFirst.Build(ctx, spec)
+	debug.SetMaxStack(limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := breadthFirst.Build(ctx, spec); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := depthFirst.Count(ctx)
+	if want := int64(1 + deepVars + deepVars*(deepVars-1)/2); err != nil || count != want {
+		t.Fatalf("Count = %d, %v, want %d", count, err, want)
+	}
+	if depthFirst.Size() != breadthFirst.Size() || !depthFirst.Equals(breadthFirst) {
+		t.Fatalf("depth-first build has %d nodes, breadth-first %d, or their families differ",
+			depthFirst.Size(), breadthFirst.Size())
+	}
+}
+
+func TestBuildDepthFirstMatchesBreadthFirst(t *testing.T) {
+	ctx := context.Background()
+	for vars := 1; vars <= 8; vars++ {
+		for max := 0; max <= vars; max++ {
+			spec := &SimpleSpec{vars: vars, maxCount: max}
+			depthFirst := gozdd.NewZDD(vars)
+			breadthFirst := gozdd.NewZDD(vars, gozdd.WithBreadthFirst())
+			for _, z := range []*gozdd.ZDD{depthFirst, breadthFirst} {
+				if err := z.Build(ctx, spec); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !depthFirst.Equals(breadthFirst) || depthFirst.Size() != breadthFirst.Size() {
+				t.Fatalf("vars=%d max=%d: depth-first and breadth-first builds differ", vars, max)
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package gozdd
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+)
+
+// All returns an iterator over every solution of the ZDD.
+//
+// Solutions are produced lazily by a depth-first walk, so memory stays
+// proportional to Variables() however many solutions there are, and callers
+// may stop early. Solutions are yielded in the order documented at
+// WithStableOrder, each freshly allocated with Cost 0 and passed to the
+// solution decorator. Iteration ends early if ctx is cancelled; check
+// ctx.Err() afterwards to tell a cancelled walk from a complete one.
+func (z *ZDD) All(ctx context.Context) iter.Seq[*Solution] {
+	return func(yield func(*Solution) bool) {
+		z.enumerate(ctx, func(vars []int) bool {
+			solution := &Solution{Variables: vars, Metadata: make(map[string]interface{})}
+			z.decorate(solution)
+			return yield(solution)
+		})
+	}
+}
+
+// EnumerationFormat selects how EnumerateTo writes solutions.
+type EnumerationFormat int
+
+const (
+	// FormatJSON writes one JSON object per line (JSON Lines), such as
+	// {"variables":[1,3]}
+	FormatJSON EnumerationFormat = iota
+
+	// FormatCSV writes a header row x1,...,xn followed by one row of 0/1
+	// selection flags per solution
+	FormatCSV
+)
+
+// EnumerateTo streams every solution to w, one per line.
+//
+// Solutions are written in the order documented at WithStableOrder as they
+// are enumerated, through a buffered writer, so memory stays proportional to
+// Variables() however many solutions there are. Output is flushed before
+// returning. Returns ctx.Err() if ctx is cancelled, leaving the solutions
+// written so far in w, and ErrInvalidConstraint for an unknown format.
+func (z *ZDD) EnumerateTo(ctx context.Context, w io.Writer, format EnumerationFormat) error {
+	var write func(vars []int) error
+	var flush func() error
+
+	switch format {
+	case FormatJSON:
+		bw := bufio.NewWriter(w)
+		flush = bw.Flush
+		enc := json.NewEncoder(bw)
+		write = func(vars []int) error {
+			return enc.Encode(struct {
+				Variables []int `json:"variables"`
+			}{vars})
+		}
+
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+		row := make([]string, z.vars)
+		for i := range row {
+			row[i] = "x" + strconv.Itoa(i+1)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+		write = func(vars []int) error {
+			for i := range row {
+				row[i] = "0"
+			}
+			for _, v := range vars {
+				row[v-1] = "1"
+			}
+			return cw.Write(row)
+		}
+
+	default:
+		return fmt.Errorf("%w: unknown enumeration format %d", ErrInvalidConstraint, format)
+	}
+
+	var err error
+	z.enumerate(ctx, func(vars []int) bool {
+		err = write(vars)
+		return err == nil
+	})
+	if err != nil {
+		return fmt.Errorf("enumeration failed: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		flush()
+		return err
+	}
+	return flush()
+}
@@ -15,7 +15,8 @@ var (
 	// ErrInvalidVariable indicates a variable index is out of bounds.
 	ErrInvalidVariable = errors.New("invalid variable index")
 	
-	// ErrInvalidLevel indicates a level parameter is invalid (< 0 or > max).
+	// ErrInvalidLevel indicates a level parameter is invalid (< 0 or > max),
+	// or a spec skipped to a level that is not below the current one.
 	ErrInvalidLevel = errors.New("invalid level")
 	
 	// ErrInvalidNode indicates a node ID does not exist in the node table.
@@ -36,4 +37,24 @@ var (
 	// ErrNotReduced indicates an operation requires a reduced ZDD but the
 	// ZDD has not been reduced yet.
 	ErrNotReduced = errors.New("ZDD not reduced")
+	
+	// ErrFeasible indicates an operation that requires an infeasible problem
+	// was given constraints that admit at least one solution.
+	ErrFeasible = errors.New("constraints are feasible")
+	
+	// ErrCountOverflow indicates a solution count does not fit in an int64.
+	// Use CountBig for exact counts of any size.
+	ErrCountOverflow = errors.New("solution count overflows int64")
+	
+	// ErrCountExceeded indicates a CountConstraint counted more selections
+	// than its maximum.
+	ErrCountExceeded = errors.New("count exceeds maximum")
+	
+	// ErrSumExceeded indicates a SumConstraint summed to more than its
+	// maximum.
+	ErrSumExceeded = errors.New("sum exceeds maximum")
+	
+	// ErrPruned indicates a constraint found that a branch can no longer be
+	// satisfied.
+	ErrPruned = errors.New("branch pruned")
 )
@@ -0,0 +1,290 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// IntKBestEvaluator finds the k best solutions using exact int64 costs.
+//
+// Use it when costs are integral, such as amounts in cents, so sums never
+// suffer floating-point rounding. Ties between equal costs are broken in the
+// order documented at WithStableOrder, which makes results reproducible.
+// Sums must stay within the int64 range.
+type IntKBestEvaluator struct {
+	// K is the number of best solutions to find
+	K int
+
+	// Costs specifies the cost of selecting each variable (1-based indexing)
+	Costs []int64
+}
+
+// IntKBestResult holds k-best solutions with their exact integer costs
+type IntKBestResult struct {
+	// Solutions in ascending cost order; Cost holds the float64 value of the
+	// exact cost and Metadata["exact_cost"] the int64 itself
+	Solutions []*Solution
+
+	// Costs holds the exact cost of each solution
+	Costs []int64
+}
+
+// Evaluate finds the k best solutions with exact integer arithmetic
+func (e IntKBestEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
+	if len(e.Costs) <= zdd.vars {
+		return IntKBestResult{}, fmt.Errorf("insufficient cost data: need %d costs, got %d", zdd.vars, len(e.Costs)-1)
+	}
+
+	arith := exactArith[int64]{
+		add: func(a, b int64) int64 { return a + b },
+		cmp: func(a, b int64) int {
+			switch {
+			case a < b:
+				return -1
+			case a > b:
+				return 1
+			}
+			return 0
+		},
+	}
+
+	found, err := exactKBest(ctx, zdd, e.K, e.Costs, arith)
+	if err != nil {
+		return IntKBestResult{}, fmt.Errorf("k-best evaluation failed: %w", err)
+	}
+
+	result := IntKBestResult{Solutions: make([]*Solution, len(found)), Costs: make([]int64, len(found))}
+	for i, f := range found {
+		result.Costs[i] = f.cost
+		result.Solutions[i] = &Solution{
+			Variables: f.vars,
+			Cost:      float64(f.cost),
+			Metadata:  map[string]interface{}{"exact_cost": f.cost},
+		}
+	}
+	return result, nil
+}
+
+// RatKBestEvaluator finds the k best solutions using exact rational costs.
+//
+// Use it for fractional monetary values that must not be rounded. Ties are
+// broken as in IntKBestEvaluator. Nil entries in Costs count as zero.
+type RatKBestEvaluator struct {
+	// K is the number of best solutions to find
+	K int
+
+	// Costs specifies the cost of selecting each variable (1-based indexing)
+	Costs []*big.Rat
+}
+
+// RatKBestResult holds k-best solutions with their exact rational costs
+type RatKBestResult struct {
+	// Solutions in ascending cost order; Cost holds the nearest float64 to
+	// the exact cost and Metadata["exact_cost"] the *big.Rat itself
+	Solutions []*Solution
+
+	// Costs holds the exact cost of each solution
+	Costs []*big.Rat
+}
+
+// Evaluate finds the k best solutions with exact rational arithmetic
+func (e RatKBestEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
+	if len(e.Costs) <= zdd.vars {
+		return RatKBestResult{}, fmt.Errorf("insufficient cost data: need %d costs, got %d", zdd.vars, len(e.Costs)-1)
+	}
+
+	costs := make([]*big.Rat, len(e.Costs))
+	for i, c := range e.Costs {
+		if c == nil {
+			c = new(big.Rat)
+		}
+		costs[i] = c
+	}
+
+	arith := exactArith[*big.Rat]{
+		add:  func(a, b *big.Rat) *big.Rat { return new(big.Rat).Add(a, b) },
+		cmp:  func(a, b *big.Rat) int { return a.Cmp(b) },
+		zero: new(big.Rat),
+	}
+
+	found, err := exactKBest(ctx, zdd, e.K, costs, arith)
+	if err != nil {
+		return RatKBestResult{}, fmt.Errorf("k-best evaluation failed: %w", err)
+	}
+
+	result := RatKBestResult{Solutions: make([]*Solution, len(found)), Costs: make([]*big.Rat, len(found))}
+	for i, f := range found {
+		approx, _ := f.cost.Float64()
+		result.Costs[i] = f.cost
+		result.Solutions[i] = &Solution{
+			Variables: f.vars,
+			Cost:      approx,
+			Metadata:  map[string]interface{}{"exact_cost": f.cost},
+		}
+	}
+	return result, nil
+}
+
+// FindKBestInt finds the k best solutions with exact int64 costs.
+//
+// This is a type-safe convenience method for IntKBestEvaluator.
+func (z *ZDD) FindKBestInt(ctx context.Context, k int, costs []int64) (IntKBestResult, error) {
+	result, err := EvaluateZDD(ctx, z, IntKBestEvaluator{K: k, Costs: costs})
+	if err != nil {
+		return IntKBestResult{}, err
+	}
+	return result.(IntKBestResult), nil
+}
+
+// FindKBestRat finds the k best solutions with exact rational costs.
+//
+// This is a type-safe convenience method for RatKBestEvaluator.
+func (z *ZDD) FindKBestRat(ctx context.Context, k int, costs []*big.Rat) (RatKBestResult, error) {
+	result, err := EvaluateZDD(ctx, z, RatKBestEvaluator{K: k, Costs: costs})
+	if err != nil {
+		return RatKBestResult{}, err
+	}
+	return result.(RatKBestResult), nil
+}
+
+// exactArith supplies the arithmetic of an exact cost type
+type exactArith[T any] struct {
+	add  func(a, b T) T
+	cmp  func(a, b T) int
+	zero T
+}
+
+// exactSolution is a solution with its exact cost
+type exactSolution[T any] struct {
+	vars []int
+	cost T
+}
+
+// exactKBest runs a branch-and-bound k-best search with exact costs.
+//
+// Bounds are exact minimum completion costs. A sub-diagram is pruned only if
+// its bound is strictly worse than the current k-th solution, so solutions
+// tying with it are still found and the tie-break is exact.
+func exactKBest[T any](ctx context.Context, zdd *ZDD, k int, costs []T, arith exactArith[T]) ([]exactSolution[T], error) {
+	root := zdd.family()
+	if k <= 0 || root == ZeroNode {
+		return nil, nil
+	}
+
+	// bound holds the minimum completion cost of every node with solutions
+	bound := map[NodeID]T{OneNode: arith.zero}
+	err := zdd.bottomUp(ctx, func(id NodeID, node Node) error {
+		// quasi-reduced diagrams keep nodes whose Hi arc has no solutions
+		lo, loOK := bound[node.Lo]
+		hi, hiOK := bound[node.Hi]
+		if !hiOK {
+			bound[id] = lo
+			return nil
+		}
+		best := arith.add(hi, costs[node.Level])
+		if loOK && arith.cmp(lo, best) <= 0 {
+			best = lo
+		}
+		bound[id] = best
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	less := func(a, b exactSolution[T]) bool {
+		if c := arith.cmp(a.cost, b.cost); c != 0 {
+			return c < 0
+		}
+		return colexLess(a.vars, b.vars)
+	}
+
+	var best []exactSolution[T]
+	var path []int
+	record := func(cost T) {
+		vars := append([]int(nil), path...)
+		sort.Ints(vars)
+		s := exactSolution[T]{vars: vars, cost: cost}
+		i := sort.Search(len(best), func(i int) bool { return less(s, best[i]) })
+		if i < k {
+			best = append(best, s)
+			copy(best[i+1:], best[i:])
+			best[i] = s
+			if len(best) > k {
+				best = best[:k]
+			}
+		}
+	}
+	if root == OneNode {
+		record(arith.zero)
+		return best, nil
+	}
+
+	// depth-first search on an explicit stack; the bound of an arc is
+	// checked only once the arcs before it are searched, so it sees the
+	// k-th solution found so far
+	node, err := zdd.GetNode(root)
+	if err != nil {
+		return nil, err
+	}
+	stack := []exactFrame[T]{{node: node, cost: arith.zero}}
+	for len(stack) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		f := &stack[len(stack)-1]
+		if f.next == 2 {
+			if f.took {
+				path = path[:len(path)-1]
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		take := f.next == 1
+		f.next++
+
+		child, childCost := f.node.Lo, f.cost
+		if take {
+			child, childCost = f.node.Hi, arith.add(f.cost, costs[f.node.Level])
+		}
+		if child == ZeroNode {
+			continue
+		}
+		if len(best) == k && arith.cmp(arith.add(childCost, bound[child]), best[k-1].cost) > 0 {
+			continue
+		}
+
+		if take {
+			path = append(path, f.node.Level)
+		}
+		if child == OneNode {
+			record(childCost)
+			if take {
+				path = path[:len(path)-1]
+			}
+			continue
+		}
+		node, err := zdd.GetNode(child)
+		if err != nil {
+			return nil, err
+		}
+		stack = append(stack, exactFrame[T]{node: node, cost: childCost, took: take})
+	}
+	return best, nil
+}
+
+// exactFrame is a node on the search path of exactKBest
+type exactFrame[T any] struct {
+	node Node
+	cost T
+
+	// next is the arc to search next: 0 for Lo, 1 for Hi, 2 when both are
+	// searched
+	next int
+
+	// took records whether the node was reached by a Hi arc
+	took bool
+}
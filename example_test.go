@@ -2,8 +2,15 @@ package gozdd_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"math"
+	"math/big"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/zzenonn/go-zdd"
 )
@@ -169,3 +176,2282 @@ func (s *SimpleSpec) GetChild(ctx context.Context, state gozdd.State, level int,
 func (s *SimpleSpec) IsValid(state gozdd.State) bool {
 	return true
 }
+
+// ExampleAnalyzeConstraintImpact demonstrates measuring per-constraint impact.
+func ExampleAnalyzeConstraintImpact() {
+	forbid := func(name string, variable int) gozdd.Constraint {
+		return gozdd.CustomConstraint{
+			Name: name,
+			ValidateFunc: func(ctx context.Context, state gozdd.State, level int, take bool) error {
+				if take && level == variable {
+					return fmt.Errorf("variable %d is forbidden", variable)
+				}
+				return nil
+			},
+		}
+	}
+	
+	spec := gozdd.NewCompositeSpec(3, gozdd.BasicState{Counters: []int{0}},
+		forbid("no x2", 2),
+		forbid("no x3", 3),
+	)
+	
+	report, err := gozdd.AnalyzeConstraintImpact(context.Background(), spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+	
+	fmt.Printf("Solutions: %d\n", report.Count)
+	for _, impact := range report.Constraints {
+		fmt.Printf("%s: prunes=%d, extra solutions without it=%d\n", impact.Name, impact.Prunes, impact.CountDelta)
+	}
+	
+	// Output:
+	// Solutions: 1
+	// no x2: prunes=1, extra solutions without it=2
+	// no x3: prunes=1, extra solutions without it=2
+}
+
+// ExampleFindMinimalConflict demonstrates locating the constraints behind an empty ZDD.
+func ExampleFindMinimalConflict() {
+	require := func(name string, variable int) gozdd.Constraint {
+		return gozdd.CustomConstraint{
+			Name: name,
+			ValidateFunc: func(ctx context.Context, state gozdd.State, level int, take bool) error {
+				if !take && level == variable {
+					return fmt.Errorf("variable %d is required", variable)
+				}
+				return nil
+			},
+		}
+	}
+	forbid := func(name string, variable int) gozdd.Constraint {
+		return gozdd.CustomConstraint{
+			Name: name,
+			ValidateFunc: func(ctx context.Context, state gozdd.State, level int, take bool) error {
+				if take && level == variable {
+					return fmt.Errorf("variable %d is forbidden", variable)
+				}
+				return nil
+			},
+		}
+	}
+	
+	spec := gozdd.NewCompositeSpec(3, gozdd.BasicState{Counters: []int{0}},
+		require("need x1", 1),
+		forbid("no x3", 3),
+		forbid("no x1", 1),
+		require("need x2", 2),
+	)
+	
+	conflict, err := gozdd.FindMinimalConflict(context.Background(), spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+	
+	fmt.Printf("Conflict: %v\n", conflict.Names)
+	
+	// Output:
+	// Conflict: [need x1 no x1]
+}
+
+// ExampleWhatIfSession demonstrates interactive fixing of variables.
+func ExampleWhatIfSession() {
+	spec := &SimpleSpec{vars: 3, maxCount: 2}
+	
+	zdd := gozdd.NewZDD(3)
+	ctx := context.Background()
+	
+	if err := zdd.Build(ctx, spec); err != nil {
+		log.Fatal(err)
+	}
+	
+	session, err := gozdd.NewWhatIfSession(ctx, zdd, []float64{0, 3, 1, 2})
+	if err != nil {
+		log.Fatal(err)
+	}
+	count, err := session.Count()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("All: %d solutions\n", count)
+	
+	if err := session.Fix(ctx, 1, true); err != nil {
+		log.Fatal(err)
+	}
+	count, err = session.Count()
+	if err != nil {
+		log.Fatal(err)
+	}
+	best, _ := session.Best()
+	fmt.Printf("With x1: %d solutions, best %v\n", count, best.Variables)
+	
+	// Output:
+	// All: 7 solutions
+	// With x1: 3 solutions, best [1]
+}
+
+// ExampleZDD_DetectSymmetries demonstrates finding and collapsing interchangeable variables.
+func ExampleZDD_DetectSymmetries() {
+	spec := &SimpleSpec{vars: 3, maxCount: 1}
+	
+	zdd := gozdd.NewZDD(3)
+	ctx := context.Background()
+	
+	if err := zdd.Build(ctx, spec); err != nil {
+		log.Fatal(err)
+	}
+	
+	orbits, err := zdd.DetectSymmetries(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	
+	collapsed, err := zdd.CollapseSymmetry(ctx, orbits)
+	if err != nil {
+		log.Fatal(err)
+	}
+	count, _ := collapsed.Count(ctx)
+	
+	fmt.Printf("Orbits: %v\n", orbits)
+	fmt.Printf("Solutions up to symmetry: %d\n", count)
+	
+	// Output:
+	// Orbits: [[1 2 3]]
+	// Solutions up to symmetry: 2
+}
+
+// ExampleCompositeConstraintSpec_DeclareSymmetry demonstrates automatic symmetry breaking.
+func ExampleCompositeConstraintSpec_DeclareSymmetry() {
+	ctx := context.Background()
+	
+	// Two interchangeable bins with two item slots each: variables 1-2 and 3-4
+	spec := gozdd.NewCompositeSpec(4, gozdd.BasicState{Counters: []int{0}})
+	
+	full := gozdd.NewZDD(4)
+	if err := full.Build(ctx, spec); err != nil {
+		log.Fatal(err)
+	}
+	
+	if err := spec.DeclareSymmetry(gozdd.ContiguousBlocks("bins", 1, 2, 2)); err != nil {
+		log.Fatal(err)
+	}
+	
+	broken := gozdd.NewZDD(4)
+	if err := broken.Build(ctx, spec); err != nil {
+		log.Fatal(err)
+	}
+	
+	fullCount, _ := full.Count(ctx)
+	brokenCount, _ := broken.Count(ctx)
+	fmt.Printf("Without symmetry breaking: %d\n", fullCount)
+	fmt.Printf("With symmetry breaking: %d\n", brokenCount)
+	
+	// Output:
+	// Without symmetry breaking: 15
+	// With symmetry breaking: 9
+}
+
+// ExampleZDD_GroupPatterns demonstrates summarizing solutions by a variable group.
+func ExampleZDD_GroupPatterns() {
+	spec := &SimpleSpec{vars: 3, maxCount: 2}
+	
+	zdd := gozdd.NewZDD(3)
+	ctx := context.Background()
+	
+	if err := zdd.Build(ctx, spec); err != nil {
+		log.Fatal(err)
+	}
+	
+	if err := zdd.RegisterGroup("placement", []int{2, 3}); err != nil {
+		log.Fatal(err)
+	}
+	
+	patterns, err := zdd.GroupPatterns(ctx, "placement")
+	if err != nil {
+		log.Fatal(err)
+	}
+	
+	for vars := range patterns {
+		fmt.Println(vars)
+	}
+	
+	// Output:
+	// []
+	// [2]
+	// [3]
+	// [2 3]
+}
+
+// ExampleNewCompositeSpec demonstrates composing stateful built-in constraints.
+func ExampleNewCompositeSpec() {
+	ctx := context.Background()
+	
+	spec := gozdd.NewCompositeSpec(4, gozdd.NewIntState(),
+		gozdd.CountConstraint{Min: 1, Max: 2},
+		gozdd.SumConstraint{Weights: []float64{0, 2, 3, 4, 1}, Max: 5},
+	)
+	
+	zdd := gozdd.NewZDD(4)
+	if err := zdd.Build(ctx, spec); err != nil {
+		log.Fatal(err)
+	}
+	
+	count, err := zdd.Count(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	
+	fmt.Printf("Solutions: %d\n", count)
+	
+	// Output:
+	// Solutions: 8
+}
+
+// CardinalitySpec extends SimpleSpec by recognizing states whose completions are all feasible.
+type CardinalitySpec struct {
+	SimpleSpec
+}
+
+func (s *CardinalitySpec) AllCompletionsValid(state gozdd.State, level int) bool {
+	return state.(*gozdd.IntState).Values[0]+level <= s.maxCount
+}
+
+// ExampleCompletionSpec demonstrates short-circuiting construction of free suffixes.
+func ExampleCompletionSpec() {
+	ctx := context.Background()
+	
+	plain := gozdd.NewZDD(10)
+	if err := plain.Build(ctx, &SimpleSpec{vars: 10, maxCount: 8}); err != nil {
+		log.Fatal(err)
+	}
+	
+	shortcut := gozdd.NewZDD(10)
+	if err := shortcut.Build(ctx, &CardinalitySpec{SimpleSpec{vars: 10, maxCount: 8}}); err != nil {
+		log.Fatal(err)
+	}
+	
+	plainCount, _ := plain.Count(ctx)
+	shortcutCount, _ := shortcut.Count(ctx)
+	fmt.Printf("Counts match: %v (%d)\n", plainCount == shortcutCount, shortcutCount)
+	fmt.Printf("Same diagram size: %v\n", plain.Size() == shortcut.Size())
+	
+	// Output:
+	// Counts match: true (1013)
+	// Same diagram size: true
+}
+
+// GroupSpec picks at most one variable from each consecutive group of size
+// variables. Taking a variable jumps past the rest of its group.
+type GroupSpec struct {
+	vars, size int
+}
+
+func (s *GroupSpec) Variables() int {
+	return s.vars
+}
+
+func (s *GroupSpec) InitialState() gozdd.State {
+	return gozdd.NewIntState(0)
+}
+
+func (s *GroupSpec) GetChild(ctx context.Context, state gozdd.State, level int, take bool) (gozdd.State, int, error) {
+	if take {
+		// The rest of the group stays unselected
+		return state, (level - 1) / s.size * s.size, nil
+	}
+	return state, level - 1, nil
+}
+
+func (s *GroupSpec) IsValid(state gozdd.State) bool {
+	return true
+}
+
+// ExampleFromLevelSpec demonstrates skipping levels without SkipState.
+func ExampleFromLevelSpec() {
+	ctx := context.Background()
+
+	// Two groups of three: none or one of each, 4 * 4 choices
+	zdd := gozdd.NewZDD(6)
+	if err := zdd.Build(ctx, gozdd.FromLevelSpec(&GroupSpec{vars: 6, size: 3})); err != nil {
+		log.Fatal(err)
+	}
+
+	count, _ := zdd.Count(ctx)
+	fmt.Println(count)
+
+	// Output:
+	// 16
+}
+
+// RequiredSpec extends SimpleSpec with a required variable 4 and a variable 3
+// that does not count towards the limit.
+type RequiredSpec struct {
+	SimpleSpec
+}
+
+func (s *RequiredSpec) Branching(state gozdd.State, level int) gozdd.Branching {
+	switch level {
+	case 4:
+		return gozdd.BranchTakeOnly
+	case 3:
+		return gozdd.BranchDontCare
+	}
+	return gozdd.BranchBoth
+}
+
+// ExampleBranchingSpec demonstrates forced and don't-care variables.
+func ExampleBranchingSpec() {
+	ctx := context.Background()
+
+	zdd := gozdd.NewZDD(4)
+	if err := zdd.Build(ctx, &RequiredSpec{SimpleSpec{vars: 4, maxCount: 2}}); err != nil {
+		log.Fatal(err)
+	}
+
+	for s := range zdd.All(ctx) {
+		fmt.Println(s.Variables)
+	}
+
+	// Output:
+	// [4]
+	// [1 4]
+	// [2 4]
+	// [3 4]
+	// [1 3 4]
+	// [2 3 4]
+}
+
+// ExampleWithBreadthFirst demonstrates level-by-level construction.
+func ExampleWithBreadthFirst() {
+	ctx := context.Background()
+	spec := &SimpleSpec{vars: 10, maxCount: 3}
+
+	depthFirst := gozdd.NewZDD(10)
+	if err := depthFirst.Build(ctx, spec); err != nil {
+		log.Fatal(err)
+	}
+	breadthFirst := gozdd.NewZDD(10, gozdd.WithBreadthFirst())
+	if err := breadthFirst.Build(ctx, spec); err != nil {
+		log.Fatal(err)
+	}
+
+	a, _ := depthFirst.Count(ctx)
+	b, _ := breadthFirst.Count(ctx)
+	fmt.Println(a, b, depthFirst.Size() == breadthFirst.Size())
+
+	// Output:
+	// 176 176 true
+}
+
+// ExampleWithDeterministic demonstrates reproducible parallel construction.
+func ExampleWithDeterministic() {
+	spec := &SimpleSpec{vars: 10, maxCount: 3}
+
+	// Render two independent builds, as a golden-file test would
+	render := func() string {
+		zdd := gozdd.NewZDD(10, gozdd.WithParallel(4), gozdd.WithDeterministic())
+		if err := zdd.Build(context.Background(), spec); err != nil {
+			log.Fatal(err)
+		}
+		var out strings.Builder
+		if err := zdd.WriteDOT(&out); err != nil {
+			log.Fatal(err)
+		}
+		return out.String()
+	}
+
+	fmt.Println(render() == render())
+
+	// Output:
+	// true
+}
+
+// ExampleWithQuasiReduced demonstrates a diagram with a node at every level
+// of every path.
+func ExampleWithQuasiReduced() {
+	ctx := context.Background()
+	spec := &SimpleSpec{vars: 4, maxCount: 1}
+
+	for _, opts := range [][]gozdd.Option{nil, {gozdd.WithQuasiReduced()}} {
+		zdd := gozdd.NewZDD(4, opts...)
+		if err := zdd.Build(ctx, spec); err != nil {
+			log.Fatal(err)
+		}
+
+		var widths []int
+		for l := 4; l >= 1; l-- {
+			width := 0
+			for range zdd.NodesAtLevel(l) {
+				width++
+			}
+			widths = append(widths, width)
+		}
+		count, _ := zdd.Count(ctx)
+		fmt.Println("nodes at levels 4..1:", widths, "solutions:", count)
+	}
+
+	// Output:
+	// nodes at levels 4..1: [1 1 1 1] solutions: 5
+	// nodes at levels 4..1: [1 2 2 2] solutions: 5
+}
+
+// ExampleZDD_Reduce demonstrates reducing a quasi-reduced diagram.
+func ExampleZDD_Reduce() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(4, gozdd.WithQuasiReduced())
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 4, maxCount: 1}); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("reduced:", zdd.IsReduced(), "size:", zdd.Size())
+
+	if err := zdd.Reduce(ctx); err != nil {
+		log.Fatal(err)
+	}
+	count, _ := zdd.Count(ctx)
+	fmt.Println("reduced:", zdd.IsReduced(), "size:", zdd.Size(), "solutions:", count)
+
+	// Output:
+	// reduced: false size: 9
+	// reduced: true size: 6 solutions: 5
+}
+
+// ExampleWithProgress demonstrates watching a long build.
+func ExampleWithProgress() {
+	spec := &SimpleSpec{vars: 10, maxCount: 3}
+
+	var last gozdd.ProgressInfo
+	zdd := gozdd.NewZDD(10, gozdd.WithProgress(func(info gozdd.ProgressInfo) {
+		// Long builds report here about ten times per second
+		last = info
+	}))
+	if err := zdd.Build(context.Background(), spec); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(last.Done, last.Nodes, last.States)
+
+	// Output:
+	// true 24 34
+}
+
+// ExampleWithLogger demonstrates structured debug logging of a build.
+func ExampleWithLogger() {
+	spec := &SimpleSpec{vars: 3, maxCount: 1}
+
+	// Drop timings so the output is reproducible
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "elapsed" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+
+	zdd := gozdd.NewZDD(3, gozdd.WithLogger(slog.New(handler)))
+	if err := zdd.Build(context.Background(), spec); err != nil {
+		log.Fatal(err)
+	}
+
+	// Output:
+	// level=DEBUG msg="build started" vars=3 strategy=depth-first workers=1
+	// level=DEBUG msg="build finished" root=5 nodes=5 states=5 state_hit_rate=0.16666666666666666 prunes=2 skips=0
+}
+
+// ExampleZDD_BuildWithStats demonstrates measuring the work of a build.
+func ExampleZDD_BuildWithStats() {
+	spec := &SimpleSpec{vars: 10, maxCount: 3}
+
+	zdd := gozdd.NewZDD(10)
+	stats, err := zdd.BuildWithStats(context.Background(), spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("nodes per level:", stats.NodesPerLevel[1:])
+	fmt.Println("GetChild calls:", stats.GetChildCalls)
+	fmt.Println("prunes:", stats.Prunes)
+	fmt.Printf("states: %d distinct, %d shared\n", stats.StateMisses, stats.StateHits)
+
+	// Output:
+	// nodes per level: [1 2 3 3 3 3 3 3 2 1]
+	// GetChild calls: 68
+	// prunes: map[too many selections:7]
+	// states: 34 distinct, 21 shared
+}
+
+// ExampleWithTrace demonstrates logging spec transitions while debugging.
+func ExampleWithTrace() {
+	spec := &SimpleSpec{vars: 2, maxCount: 1}
+
+	zdd := gozdd.NewZDD(2, gozdd.WithTrace(os.Stdout))
+	if err := zdd.Build(context.Background(), spec); err != nil {
+		log.Fatal(err)
+	}
+
+	// Output:
+	// level=2 take=false state={Values:[0]} -> {Values:[0]}
+	// level=1 take=false state={Values:[0]} -> {Values:[0]}
+	// terminal state={Values:[0]} -> valid=true
+	// level=1 take=true state={Values:[0]} -> {Values:[1]}
+	// terminal state={Values:[1]} -> valid=true
+	// level=2 take=true state={Values:[0]} -> {Values:[1]}
+	// level=1 take=false state={Values:[1]} -> {Values:[1]}
+	// terminal state={Values:[1]} -> valid=true
+	// level=1 take=true state={Values:[1]} -> pruned: too many selections
+}
+
+// ExampleProduct demonstrates combining independently modeled subsystems.
+func ExampleProduct() {
+	ctx := context.Background()
+
+	storage := gozdd.NewZDD(2)
+	if err := storage.Build(ctx, &SimpleSpec{vars: 2, maxCount: 1}); err != nil {
+		log.Fatal(err)
+	}
+
+	network := gozdd.NewZDD(3)
+	if err := network.Build(ctx, &SimpleSpec{vars: 3, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	joint, err := gozdd.Product(storage, network)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	count, _ := joint.Count(ctx)
+	fmt.Printf("Variables: %d, Solutions: %d\n", joint.Variables(), count)
+
+	// Output:
+	// Variables: 5, Solutions: 21
+}
+
+// ExampleZDD_Compose demonstrates expanding a macro decision into detailed ones.
+func ExampleZDD_Compose() {
+	ctx := context.Background()
+
+	// Variable 4 is a macro decision over variables 1..3
+	plan := gozdd.NewZDD(4)
+	if err := plan.Build(ctx, &SimpleSpec{vars: 4, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	// The macro expands into any selection of variables 1 and 2
+	detail := gozdd.NewZDD(2)
+	if err := detail.Build(ctx, &SimpleSpec{vars: 2, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	expanded, err := plan.Compose(ctx, 4, detail)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	before, _ := plan.Count(ctx)
+	after, _ := expanded.Count(ctx)
+	fmt.Printf("Before: %d, After: %d\n", before, after)
+
+	// Output:
+	// Before: 11, After: 8
+}
+
+// ExampleManager demonstrates releasing diagrams and reclaiming their nodes.
+func ExampleManager() {
+	ctx := context.Background()
+	m := gozdd.NewManager()
+
+	keep := gozdd.NewZDD(4)
+	scratch := gozdd.NewZDD(4)
+	for _, z := range []*gozdd.ZDD{keep, scratch} {
+		if err := m.Register(z); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if err := keep.Build(ctx, &SimpleSpec{vars: 4, maxCount: 1}); err != nil {
+		log.Fatal(err)
+	}
+	if err := scratch.Build(ctx, &SimpleSpec{vars: 4, maxCount: 3}); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Live: %d, Nodes: %d\n", m.Live(), m.Size())
+
+	scratch.Release()
+	freed := m.GC()
+
+	count, _ := keep.Count(ctx)
+	fmt.Printf("Live: %d, Nodes: %d, Freed: %d, Count: %d\n", m.Live(), m.Size(), freed, count)
+
+	// Output:
+	// Live: 2, Nodes: 10
+	// Live: 1, Nodes: 6, Freed: 4, Count: 5
+}
+
+// ExampleManager_Sharing demonstrates structure shared between related builds.
+func ExampleManager_Sharing() {
+	ctx := context.Background()
+	m := gozdd.NewManager()
+
+	for _, limit := range []int{2, 3, 4} {
+		z := m.NewZDD(6)
+		if err := z.Build(ctx, &SimpleSpec{vars: 6, maxCount: limit}); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	report := m.Sharing()
+	fmt.Printf("Roots: %d\n", report.Roots)
+	fmt.Printf("Separate: %d, Shared table: %d, Saved: %d\n",
+		report.SeparateNodes, report.LiveNodes, report.SavedNodes)
+
+	// Output:
+	// Roots: 3
+	// Separate: 40, Shared table: 19, Saved: 21
+}
+
+// LabeledSpec extends SimpleSpec by recording how many variables were selected above each node.
+type LabeledSpec struct {
+	SimpleSpec
+}
+
+func (s *LabeledSpec) AnnotateNode(state gozdd.State, level int) map[string]string {
+	return map[string]string{"selected": fmt.Sprint(state.(*gozdd.IntState).Values[0])}
+}
+
+// ExampleZDD_WriteDOT demonstrates exporting an annotated diagram for Graphviz.
+func ExampleZDD_WriteDOT() {
+	zdd := gozdd.NewZDD(2)
+	if err := zdd.Build(context.Background(), &LabeledSpec{SimpleSpec{vars: 2, maxCount: 1}}); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := zdd.Annotate(zdd.Root(), "note", "root decision"); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := zdd.WriteDOT(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+
+	// Output:
+	// digraph zdd {
+	//   node [shape=circle];
+	//   n1 [label="0", shape=box];
+	//   n2 [label="1", shape=box];
+	//   n4 [label="x2\nnote: root decision\nselected: 0"];
+	//   n4 -> n3 [style=dashed];
+	//   n4 -> n2;
+	//   n3 [label="x1\nselected: 0"];
+	//   n3 -> n2 [style=dashed];
+	//   n3 -> n2;
+	// }
+}
+
+// ExampleZDD_Visit demonstrates a custom analysis measuring diagram width per level.
+func ExampleZDD_Visit() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(4)
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 4, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	width := make(map[int]int)
+	hiArcs := 0
+	err := zdd.Visit(ctx, gozdd.VisitorFuncs{
+		Pre: func(id gozdd.NodeID, n gozdd.Node) error {
+			if !n.IsTerminal() {
+				width[n.Level]++
+			}
+			return nil
+		},
+		OnArc: func(from, to gozdd.NodeID, take bool) error {
+			if take {
+				hiArcs++
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for level := 4; level >= 1; level-- {
+		fmt.Printf("Level %d: %d nodes\n", level, width[level])
+	}
+	fmt.Printf("Hi arcs: %d\n", hiArcs)
+
+	// Output:
+	// Level 4: 1 nodes
+	// Level 3: 2 nodes
+	// Level 2: 2 nodes
+	// Level 1: 1 nodes
+	// Hi arcs: 6
+}
+
+// ExampleZDD_NodesAtLevel demonstrates a layer-by-layer pass over the diagram.
+func ExampleZDD_NodesAtLevel() {
+	zdd := gozdd.NewZDD(4)
+	if err := zdd.Build(context.Background(), &SimpleSpec{vars: 4, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	for level := zdd.Variables(); level >= 1; level-- {
+		width, toOne := 0, 0
+		for id := range zdd.NodesAtLevel(level) {
+			width++
+			if hi, _ := zdd.Hi(id); hi == gozdd.OneNode {
+				toOne++
+			}
+		}
+		fmt.Printf("Level %d: width %d, hi arcs to 1: %d\n", level, width, toOne)
+	}
+
+	// Output:
+	// Level 4: width 1, hi arcs to 1: 0
+	// Level 3: width 2, hi arcs to 1: 1
+	// Level 2: width 2, hi arcs to 1: 1
+	// Level 1: width 1, hi arcs to 1: 1
+}
+
+// ExampleZDD_PathsTo demonstrates explaining which partial assignments lead to a node.
+func ExampleZDD_PathsTo() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(3)
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 3, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	// Pick the node deciding variable 1
+	var target gozdd.NodeID
+	for id := range zdd.NodesAtLevel(1) {
+		target = id
+	}
+
+	for path := range zdd.PathsTo(ctx, target) {
+		steps := make([]string, len(path))
+		for i, step := range path {
+			steps[i] = fmt.Sprintf("x%d=%t", step.Level, step.Take)
+		}
+		fmt.Println(strings.Join(steps, " "))
+	}
+
+	// Output:
+	// x3=false x2=false
+	// x3=false x2=true
+	// x3=true x2=false
+}
+
+// ExampleZDD_Subgraph demonstrates zooming into the suffix family below a node.
+func ExampleZDD_Subgraph() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(4)
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 4, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	for id := range zdd.NodesAtLevel(2) {
+		sub, err := zdd.Subgraph(id)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		count, _ := sub.Count(ctx)
+		fmt.Printf("Node at level 2: %d variables, %d completions\n", sub.Variables(), count)
+	}
+
+	// Output:
+	// Node at level 2: 2 variables, 4 completions
+	// Node at level 2: 2 variables, 3 completions
+}
+
+// ExampleZDD_Slice demonstrates extracting a band of levels together with
+// the nodes where paths enter and leave it.
+func ExampleZDD_Slice() {
+	ctx := context.Background()
+	zdd, err := gozdd.FromSets(4, [][]int{{4, 2}, {3, 2, 1}, {1}, {4, 3}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// variables 3 and 2 become variables 2 and 1 of the slice
+	slice, err := zdd.Slice(ctx, 3, 2)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print("partial assignments: ")
+	printSets(slice.Family)
+
+	levels := func(ids []gozdd.NodeID) []int {
+		var out []int
+		for _, id := range ids {
+			node, _ := zdd.GetNode(id)
+			out = append(out, node.Level)
+		}
+		return out
+	}
+	fmt.Println("entry levels:", levels(slice.Entries), "exit levels:", levels(slice.Exits), "offset:", slice.Offset)
+
+	if _, err := zdd.Slice(ctx, 2, 3); errors.Is(err, gozdd.ErrInvalidLevel) {
+		fmt.Println("slice 2..3:", err)
+	}
+
+	// Output:
+	// partial assignments: [] [1] [2] [1 2]
+	// entry levels: [3 3] exit levels: [0 1] offset: 1
+	// slice 2..3: invalid level: slice 2..3 of 4 variables
+}
+
+// ExampleZDD_CountAnytime demonstrates interim bounds while counting.
+func ExampleZDD_CountAnytime() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(4)
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 4, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	result, err := zdd.CountAnytime(ctx, func(p gozdd.CountProgress) {
+		fmt.Printf("%d/%d levels: %d..%d\n", p.LevelsDone, p.Levels, p.LowerBound, p.UpperBound)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Count: %d (exact: %t)\n", result.LowerBound, result.Exact)
+
+	// Output:
+	// 1/4 levels: 0..16
+	// 2/4 levels: 1..13
+	// 3/4 levels: 3..11
+	// 4/4 levels: 11..11
+	// Count: 11 (exact: true)
+}
+
+// ExampleZDD_FindKBestAnytime demonstrates latency-bounded optimization.
+func ExampleZDD_FindKBestAnytime() {
+	zdd := gozdd.NewZDD(4)
+	if err := zdd.Build(context.Background(), &SimpleSpec{vars: 4, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	costs := []float64{0, -3, 2, -1, 4}
+	result, err := zdd.FindKBestAnytime(ctx, 3, costs)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, s := range result.Solutions {
+		fmt.Printf("%v cost %.0f\n", s.Variables, s.Cost)
+	}
+	fmt.Printf("Optimal: %t\n", result.Optimal)
+
+	// Output:
+	// [1 3] cost -4
+	// [1] cost -3
+	// [3] cost -1
+	// Optimal: true
+}
+
+// ExampleWithResultCache demonstrates answering repeated queries from the cache.
+func ExampleWithResultCache() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(4, gozdd.WithResultCache())
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 4, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	costs := []float64{0, 1, -2, 3, -1}
+	for i := 0; i < 3; i++ {
+		if _, err := zdd.FindKBest(ctx, 2, costs); err != nil {
+			log.Fatal(err)
+		}
+	}
+	count, _ := zdd.Count(ctx)
+
+	hits, misses := zdd.ResultCacheStats()
+	fmt.Printf("Count: %d, hits: %d, misses: %d\n", count, hits, misses)
+
+	zdd.InvalidateResults()
+	zdd.Count(ctx)
+	hits, misses = zdd.ResultCacheStats()
+	fmt.Printf("After invalidation: hits: %d, misses: %d\n", hits, misses)
+
+	// Output:
+	// Count: 11, hits: 2, misses: 2
+	// After invalidation: hits: 2, misses: 3
+}
+
+// ExampleZDD_Freeze demonstrates serving a snapshot from many goroutines.
+func ExampleZDD_Freeze() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(4)
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 4, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	frozen, err := zdd.Freeze()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	counts := make([]int64, 4)
+	done := make(chan struct{})
+	for i := range counts {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			counts[i], _ = frozen.Count(ctx)
+		}()
+	}
+	for range counts {
+		<-done
+	}
+
+	best, _ := frozen.FindKBest(ctx, 1, []float64{0, 2, -1, -3, 1})
+	fmt.Printf("Counts: %v\n", counts)
+	fmt.Printf("Best: %v cost %.0f\n", best[0].Variables, best[0].Cost)
+
+	// Output:
+	// Counts: [11 11 11 11]
+	// Best: [2 3] cost -4
+}
+
+// ExampleZDD_ApproxCount demonstrates estimating the count with a confidence interval.
+func ExampleZDD_ApproxCount() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(20)
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 20, maxCount: 5}); err != nil {
+		log.Fatal(err)
+	}
+
+	approx, err := zdd.ApproxCount(ctx, 20000, 1)
+	if err != nil {
+		log.Fatal(err)
+	}
+	exact, _ := zdd.Count(ctx)
+
+	fmt.Printf("Approximate: %t, confidence: %.2f\n", approx.Approximate, approx.Confidence)
+	fmt.Printf("Exact count inside interval: %t\n", approx.Lower <= float64(exact) && float64(exact) <= approx.Upper)
+
+	// Output:
+	// Approximate: true, confidence: 0.95
+	// Exact count inside interval: true
+}
+
+// ExampleZDD_FindKBestSparse demonstrates costs given only for a few variables.
+func ExampleZDD_FindKBestSparse() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(200)
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 200, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	// Every other variable costs 0
+	costs := map[int]float64{17: -5, 150: -2, 199: 3}
+
+	best, err := zdd.FindKBestSparse(ctx, 1, costs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Best: %v cost %.0f\n", best[0].Variables, best[0].Cost)
+
+	// Output:
+	// Best: [17 150] cost -7
+}
+
+// ExampleCostFunc demonstrates context-dependent costs without precomputed vectors.
+func ExampleCostFunc() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(4)
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 4, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	// Selecting variable l costs l; leaving variable 4 out incurs a penalty of 10
+	model := gozdd.CostFunc(func(level int, take bool) float64 {
+		if take {
+			return float64(level)
+		}
+		if level == 4 {
+			return 10
+		}
+		return 0
+	})
+
+	best, err := zdd.FindKBestWithModel(ctx, 2, model)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, s := range best {
+		fmt.Printf("%v cost %.0f\n", s.Variables, s.Cost)
+	}
+
+	// Output:
+	// [4] cost 4
+	// [1 4] cost 5
+}
+
+// ExampleZDD_FindKBestRat demonstrates exact optimization over monetary values.
+func ExampleZDD_FindKBestRat() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(3)
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 3, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	// 0.1 + 0.2 equals 0.3 exactly, so the two plans tie and the
+	// one without the highest differing variable comes first
+	costs := []*big.Rat{nil, big.NewRat(1, 10), big.NewRat(2, 10), big.NewRat(3, 10)}
+	result, err := zdd.FindKBestRat(ctx, 4, costs)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for i, s := range result.Solutions {
+		fmt.Printf("%v cost %s\n", s.Variables, result.Costs[i].FloatString(2))
+	}
+
+	// Output:
+	// [] cost 0.00
+	// [1] cost 0.10
+	// [2] cost 0.20
+	// [1 2] cost 0.30
+}
+
+// ExampleWithSolutionDecorator demonstrates attaching item names to every
+// returned solution in one place.
+func ExampleWithSolutionDecorator() {
+	ctx := context.Background()
+	names := []string{"", "tent", "stove", "lamp"}
+
+	zdd := gozdd.NewZDD(3, gozdd.WithSolutionDecorator(func(s *gozdd.Solution) {
+		items := make([]string, len(s.Variables))
+		for i, v := range s.Variables {
+			items[i] = names[v]
+		}
+		s.Metadata["items"] = items
+	}))
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 3, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	best, err := zdd.FindKBest(ctx, 2, []float64{0, -3, -1, -2})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, s := range best {
+		fmt.Printf("%v cost %.0f\n", s.Metadata["items"], s.Cost)
+	}
+
+	// Output:
+	// [tent lamp] cost -5
+	// [tent stove] cost -4
+}
+
+// ExampleWithStableOrder demonstrates reproducible tie-breaking between
+// solutions of equal cost.
+func ExampleWithStableOrder() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(3, gozdd.WithStableOrder())
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 3, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	// Every variable costs 1, so all sets of the same size tie
+	result, err := zdd.FindKBestAnytime(ctx, 4, []float64{0, 1, 1, 1})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, s := range result.Solutions {
+		fmt.Printf("%v cost %.0f\n", s.Variables, s.Cost)
+	}
+
+	// Output:
+	// [] cost 0
+	// [1] cost 1
+	// [2] cost 1
+	// [3] cost 1
+}
+
+// buildSets builds the family of the given sets, for use in examples
+func buildSets(vars int, sets ...[]int) *gozdd.ZDD {
+	zdd, err := gozdd.FromSets(vars, sets)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return zdd
+}
+
+// printSets prints the solutions of a ZDD in the stable order
+func printSets(zdd *gozdd.ZDD) {
+	frozen, err := zdd.Freeze()
+	if err != nil {
+		log.Fatal(err)
+	}
+	var out []string
+	for set := range frozen.Sets(context.Background()) {
+		out = append(out, fmt.Sprint(set))
+	}
+	fmt.Println(strings.Join(out, " "))
+}
+
+// ExampleZDD_Union demonstrates merging the solutions of two scenarios.
+func ExampleZDD_Union() {
+	ctx := context.Background()
+	peak := buildSets(3, []int{1, 2}, []int{1, 3})
+	offPeak := buildSets(3, []int{1}, []int{1, 3})
+
+	either, err := peak.Union(ctx, offPeak)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(either)
+
+	// Output:
+	// [1] [1 2] [1 3]
+}
+
+// ExampleZDD_Intersect demonstrates combining independently built constraints.
+func ExampleZDD_Intersect() {
+	ctx := context.Background()
+
+	// At most two of four items, built separately from the assignment rules
+	capacity := gozdd.NewZDD(4)
+	if err := capacity.Build(ctx, &SimpleSpec{vars: 4, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+	assignment := buildSets(4, []int{1, 2}, []int{1, 2, 3}, []int{3, 4}, []int{2, 3, 4})
+
+	both, err := capacity.Intersect(ctx, assignment)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(both)
+
+	// Output:
+	// [1 2] [3 4]
+}
+
+// ExampleZDD_Difference demonstrates finding the solutions a new constraint eliminates.
+func ExampleZDD_Difference() {
+	ctx := context.Background()
+
+	before := gozdd.NewZDD(3)
+	if err := before.Build(ctx, &SimpleSpec{vars: 3, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+	after := gozdd.NewZDD(3)
+	if err := after.Build(ctx, &SimpleSpec{vars: 3, maxCount: 1}); err != nil {
+		log.Fatal(err)
+	}
+
+	eliminated, err := before.Difference(ctx, after)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(eliminated)
+
+	// Output:
+	// [1 2] [1 3] [2 3]
+}
+
+// ExampleZDD_SymmetricDifference demonstrates comparing two versions of a model.
+func ExampleZDD_SymmetricDifference() {
+	ctx := context.Background()
+	v1 := buildSets(3, []int{1}, []int{2}, []int{1, 2})
+	v2 := buildSets(3, []int{1}, []int{2}, []int{2, 3})
+
+	changed, err := v1.SymmetricDifference(ctx, v2)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(changed)
+
+	// Output:
+	// [1 2] [2 3]
+}
+
+// ExampleZDD_Join demonstrates composing the solutions of two sub-problems.
+func ExampleZDD_Join() {
+	ctx := context.Background()
+
+	// Variables 1-2 choose a server, variables 3-4 choose a network link
+	servers := buildSets(2, []int{1}, []int{2})
+	links := buildSets(4, []int{3}, []int{4})
+
+	plans, err := servers.Join(ctx, links)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Variables:", plans.Variables())
+	printSets(plans)
+
+	// Output:
+	// Variables: 4
+	// [1 3] [2 3] [1 4] [2 4]
+}
+
+// ExampleZDD_Meet demonstrates the pairwise intersections of two families.
+func ExampleZDD_Meet() {
+	ctx := context.Background()
+	a := buildSets(3, []int{1, 2}, []int{2, 3})
+	b := buildSets(3, []int{1, 2, 3}, []int{3})
+
+	common, err := a.Meet(ctx, b)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(common)
+
+	// Output:
+	// [] [1 2] [3] [2 3]
+}
+
+// ExampleZDD_Divide demonstrates factoring a common sub-structure out of a family.
+func ExampleZDD_Divide() {
+	ctx := context.Background()
+
+	// {1,2} {1,3} {2,4} {3,4} {5} = ({1} or {4}) joined with ({2} or {3}), plus {5}
+	family := buildSets(5, []int{1, 2}, []int{1, 3}, []int{2, 4}, []int{3, 4}, []int{5})
+	divisor := buildSets(5, []int{2}, []int{3})
+
+	quotient, err := family.Divide(ctx, divisor)
+	if err != nil {
+		log.Fatal(err)
+	}
+	remainder, err := family.Remainder(ctx, divisor)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(quotient)
+	printSets(remainder)
+
+	// Output:
+	// [1] [4]
+	// [5]
+}
+
+// ExampleZDD_Maximal demonstrates extracting maximal and minimal members on
+// the path graph 1-2-3.
+func ExampleZDD_Maximal() {
+	ctx := context.Background()
+	independent := buildSets(3, []int{}, []int{1}, []int{2}, []int{3}, []int{1, 3})
+	covers := buildSets(3, []int{2}, []int{1, 2}, []int{2, 3}, []int{1, 3}, []int{1, 2, 3})
+
+	maximal, err := independent.Maximal(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	minimal, err := covers.Minimal(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(maximal)
+	printSets(minimal)
+
+	// Output:
+	// [2] [1 3]
+	// [2] [1 3]
+}
+
+// ExampleZDD_NonSupersets demonstrates excluding solutions that contain a
+// forbidden combination.
+func ExampleZDD_NonSupersets() {
+	ctx := context.Background()
+	plans := gozdd.NewZDD(3)
+	if err := plans.Build(ctx, &SimpleSpec{vars: 3, maxCount: 3}); err != nil {
+		log.Fatal(err)
+	}
+
+	// Tasks 1 and 3 conflict
+	conflicts := buildSets(3, []int{1, 3})
+
+	allowed, err := plans.NonSupersets(ctx, conflicts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(allowed)
+
+	// Output:
+	// [] [1] [2] [1 2] [3] [2 3]
+}
+
+// ExampleZDD_Subset1 demonstrates the per-variable cofactor operations.
+func ExampleZDD_Subset1() {
+	ctx := context.Background()
+	family := buildSets(3, []int{1}, []int{1, 2}, []int{2, 3})
+
+	without, err := family.Subset0(ctx, 2)
+	if err != nil {
+		log.Fatal(err)
+	}
+	with, err := family.Subset1(ctx, 2)
+	if err != nil {
+		log.Fatal(err)
+	}
+	toggled, err := family.Change(ctx, 2)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(without)
+	printSets(with)
+	printSets(toggled)
+
+	// Output:
+	// [1]
+	// [1] [3]
+	// [1] [1 2] [3]
+}
+
+// ExampleManager_OpCacheStats demonstrates reuse of operation results between
+// family operations on managed diagrams.
+func ExampleManager_OpCacheStats() {
+	ctx := context.Background()
+	m := gozdd.NewManager()
+
+	small := m.NewZDD(8)
+	if err := small.Build(ctx, &SimpleSpec{vars: 8, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+	large := m.NewZDD(8)
+	if err := large.Build(ctx, &SimpleSpec{vars: 8, maxCount: 5}); err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := small.Union(ctx, large); err != nil {
+		log.Fatal(err)
+	}
+	_, before, _ := m.OpCacheStats()
+
+	// The same union again is answered from the cache
+	again, err := small.Union(ctx, large)
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, after, _ := m.OpCacheStats()
+
+	count, _ := again.Count(ctx)
+	fmt.Println("Count:", count)
+	fmt.Println("New cache misses:", after-before)
+
+	// Output:
+	// Count: 219
+	// New cache misses: 0
+}
+
+// ExampleZDD_OnSet demonstrates conditional analysis on one variable.
+func ExampleZDD_OnSet() {
+	ctx := context.Background()
+	plans := gozdd.NewZDD(3)
+	if err := plans.Build(ctx, &SimpleSpec{vars: 3, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+
+	withServer3, err := plans.OnSet(ctx, 3)
+	if err != nil {
+		log.Fatal(err)
+	}
+	withoutServer3, err := plans.OffSet(ctx, 3)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(withServer3)
+	printSets(withoutServer3)
+
+	// Output:
+	// [3] [1 3] [2 3]
+	// [] [1] [2] [1 2]
+}
+
+// ExampleZDD_Relabel demonstrates reusing a sub-problem diagram at another
+// variable offset.
+func ExampleZDD_Relabel() {
+	ctx := context.Background()
+
+	// Pick exactly one of two replicas
+	replica := buildSets(2, []int{1}, []int{2})
+
+	// The same choice for the second site uses variables 3 and 4
+	site2, err := replica.Relabel(ctx, map[int]int{1: 3, 2: 4})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Variables:", site2.Variables())
+	printSets(site2)
+
+	// Output:
+	// Variables: 4
+	// [3] [4]
+}
+
+// ExampleZDD_Project demonstrates analyzing one part of the solutions
+// independently of the rest.
+func ExampleZDD_Project() {
+	ctx := context.Background()
+
+	// Variables 1-2 select servers, variables 3-4 assign tasks
+	plans := buildSets(4, []int{1, 3}, []int{1, 4}, []int{1, 2, 3}, []int{2, 4})
+
+	servers, err := plans.Project(ctx, []int{1, 2})
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(servers)
+
+	// Output:
+	// [1] [2] [1 2]
+}
+
+// ExampleCombine demonstrates assembling independently built blocks of a
+// decomposed problem.
+func ExampleCombine() {
+	ctx := context.Background()
+
+	// Block one decides variables 1-2, block two variables 3-4
+	first := buildSets(2, []int{1}, []int{2})
+	second := buildSets(4, []int{3}, []int{4}, []int{3, 4})
+
+	combined, err := gozdd.Combine(ctx, first, second)
+	if err != nil {
+		log.Fatal(err)
+	}
+	count, _ := combined.Count(ctx)
+	fmt.Println("Count:", count)
+	printSets(combined)
+
+	// Output:
+	// Count: 6
+	// [1 3] [2 3] [1 4] [2 4] [1 3 4] [2 3 4]
+}
+
+// ExamplePowerSet demonstrates assembling a family from elementary families.
+func ExamplePowerSet() {
+	ctx := context.Background()
+
+	// Every subset of three tasks that does not contain both 1 and 2
+	conflict, err := gozdd.Single(3, 1)
+	if err != nil {
+		log.Fatal(err)
+	}
+	task2, err := gozdd.Single(3, 2)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if conflict, err = conflict.Join(ctx, task2); err != nil {
+		log.Fatal(err)
+	}
+	allowed, err := gozdd.PowerSet(3).NonSupersets(ctx, conflict)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(allowed)
+
+	// The empty family and {∅} are the identities of Union and Join
+	empty, base := gozdd.Empty(3), gozdd.Base(3)
+	same, _ := allowed.Union(ctx, empty)
+	again, _ := same.Join(ctx, base)
+	printSets(again)
+
+	// Output:
+	// [] [1] [2] [3] [1 3] [2 3]
+	// [] [1] [2] [3] [1 3] [2 3]
+}
+
+// ExampleFromSets demonstrates importing enumerated solutions.
+func ExampleFromSets() {
+	ctx := context.Background()
+
+	// Solutions reported by another solver, in no particular order
+	zdd, err := gozdd.FromSets(4, [][]int{{2, 1}, {4}, {1, 2}, {3, 1}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	count, _ := zdd.Count(ctx)
+	fmt.Println("Count:", count)
+	printSets(zdd)
+
+	// Output:
+	// Count: 3
+	// [1 2] [1 3] [4]
+}
+
+// ExampleCardinalityRange demonstrates restricting a family to solutions of
+// a given size.
+func ExampleCardinalityRange() {
+	ctx := context.Background()
+	plans := buildSets(4, []int{1}, []int{1, 2}, []int{2, 3, 4}, []int{1, 2, 3, 4})
+
+	sizes, err := gozdd.CardinalityRange(4, 2, 3)
+	if err != nil {
+		log.Fatal(err)
+	}
+	medium, err := plans.Intersect(ctx, sizes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(medium)
+
+	// Output:
+	// [1 2] [2 3 4]
+}
+
+// ExampleZDD_Contains demonstrates point queries against a family far too
+// large to enumerate.
+func ExampleZDD_Contains() {
+	// every way to pick 20 of 40 items: over 137 billion solutions
+	halves, err := gozdd.CardinalityRange(40, 20, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var evens, firsts []int
+	for v := 1; v <= 20; v++ {
+		evens = append(evens, 2*v)
+		firsts = append(firsts, v)
+	}
+	fmt.Println(halves.Contains(evens))
+	fmt.Println(halves.Contains(firsts[:19]))
+
+	// Output:
+	// true
+	// false
+}
+
+// ExampleZDD_IsSubfamilyOf demonstrates comparing families without counting
+// or enumerating them.
+func ExampleZDD_IsSubfamilyOf() {
+	ctx := context.Background()
+	all := gozdd.PowerSet(30)
+	pairs, err := gozdd.CardinalityRange(30, 2, 2)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(pairs.IsSubfamilyOf(all), all.IsSubfamilyOf(pairs))
+
+	// removing the pairs from all subsets and adding them back changes nothing
+	rest, err := all.Difference(ctx, pairs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	again, err := rest.Union(ctx, pairs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(again.Equals(all))
+
+	none, err := rest.Intersect(ctx, pairs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(none.IsEmpty())
+
+	// Output:
+	// true false
+	// true
+	// true
+}
+
+// ExampleZDD_Filter demonstrates layering an extra constraint onto a built
+// diagram without rebuilding it.
+func ExampleZDD_Filter() {
+	ctx := context.Background()
+	plans := buildSets(4, []int{1}, []int{1, 2}, []int{2, 3, 4}, []int{1, 3}, []int{1, 2, 3, 4})
+
+	// keep only the plans selecting at most two variables
+	small, err := plans.Filter(ctx, &SimpleSpec{vars: 4, maxCount: 2})
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(small)
+
+	// Output:
+	// [1] [1 2] [1 3]
+}
+
+// ExampleZDD_MapSolutions demonstrates expanding each selected variable into a
+// group of detailed variables.
+func ExampleZDD_MapSolutions() {
+	ctx := context.Background()
+	// two macro choices, each standing for a pair of detailed variables
+	macro := buildSets(2, []int{1}, []int{1, 2})
+
+	detailed, err := macro.MapSolutions(ctx, func(v int) []int {
+		return []int{2*v - 1, 2 * v}
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(detailed.Variables())
+	printSets(detailed)
+
+	// Output:
+	// 4
+	// [1 2] [1 2 3 4]
+}
+
+// ExampleZDD_SwapAdjacent demonstrates exchanging two neighbouring variables.
+func ExampleZDD_SwapAdjacent() {
+	ctx := context.Background()
+	plans := buildSets(3, []int{1}, []int{2, 3}, []int{1, 2})
+
+	swapped, err := plans.SwapAdjacent(ctx, 1)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(swapped)
+
+	// Output:
+	// [2] [1 2] [1 3]
+}
+
+// ExampleZDD_InsertFree demonstrates aligning two ZDDs over overlapping
+// variables before intersecting them.
+func ExampleZDD_InsertFree() {
+	ctx := context.Background()
+	// servers uses variables 1 and 3 of the full model, which has 3 variables
+	servers := buildSets(2, []int{1}, []int{2})
+	full := buildSets(3, []int{1, 2}, []int{2, 3}, []int{1, 2, 3})
+
+	aligned, err := servers.InsertFree(ctx, []int{2})
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(aligned)
+
+	both, err := aligned.Intersect(ctx, full)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(both)
+
+	// Output:
+	// [1] [1 2] [3] [2 3]
+	// [1 2] [2 3]
+}
+
+// ExampleZDD_ToBDD demonstrates Boolean function checks on a solution family.
+func ExampleZDD_ToBDD() {
+	ctx := context.Background()
+	all := gozdd.PowerSet(20)
+	pairs, err := gozdd.CardinalityRange(20, 2, 2)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	allFn, err := all.ToBDD(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pairsFn, err := pairs.ToBDD(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// every variable is a don't-care, so the power set collapses to true
+	fmt.Println(allFn.IsTautology(), allFn.Size())
+	fmt.Println(pairsFn.Implies(allFn), allFn.Implies(pairsFn))
+
+	back, err := pairsFn.ToZDD(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(back.Equals(pairs))
+
+	// Output:
+	// true 2
+	// true false
+	// true
+}
+
+// ExampleZDD_DownwardClosure demonstrates the subset and superset closures.
+func ExampleZDD_DownwardClosure() {
+	ctx := context.Background()
+	plans := buildSets(3, []int{1, 2}, []int{3})
+
+	down, err := plans.DownwardClosure(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(down)
+
+	up, err := plans.UpwardClosure(ctx, 3)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSets(up)
+
+	// Output:
+	// [] [1] [2] [1 2] [3]
+	// [1 2] [3] [1 3] [2 3] [1 2 3]
+}
+
+// ExampleZDD_CountBig demonstrates exact counting beyond the int64 range.
+func ExampleZDD_CountBig() {
+	ctx := context.Background()
+	all := gozdd.PowerSet(100)
+
+	_, err := all.Count(ctx)
+	fmt.Println(errors.Is(err, gozdd.ErrCountOverflow))
+
+	count, err := all.CountBig(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(count)
+
+	// Output:
+	// true
+	// 1267650600228229401496703205376
+}
+
+// ExampleZDD_LogCount demonstrates counting a family too large for float64.
+func ExampleZDD_LogCount() {
+	ctx := context.Background()
+	halves, err := gozdd.CardinalityRange(1200, 600, 600)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	count, err := halves.LogCount(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("about 10^%.2f solutions\n", count.Log10())
+	fmt.Println(math.IsInf(count.Float64(), 1), count.RelativeError < 1e-12)
+
+	// Output:
+	// about 10^359.60 solutions
+	// true true
+}
+
+// ExampleZDD_ExpectedCost demonstrates the average cost over all solutions.
+func ExampleZDD_ExpectedCost() {
+	ctx := context.Background()
+	plans := buildSets(3, []int{1}, []int{2, 3}, []int{1, 2, 3})
+	costs := []float64{0, 3, 1, 2}
+
+	mean, found, err := plans.ExpectedCost(ctx, costs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(mean, found)
+
+	// Output:
+	// 4 true
+}
+
+// ExampleZDD_CostMoments demonstrates the mean and variance of the cost.
+func ExampleZDD_CostMoments() {
+	ctx := context.Background()
+	plans := buildSets(3, []int{1}, []int{2, 3}, []int{1, 2, 3})
+	costs := []float64{0, 3, 1, 2}
+
+	moments, err := plans.CostMoments(ctx, costs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("mean %.0f, standard deviation %.2f\n", moments.Mean, math.Sqrt(moments.Variance))
+
+	// Output:
+	// mean 4, standard deviation 1.41
+}
+
+// ExampleZDD_ExpectedCostWithProbabilities demonstrates the expected cost
+// of a random selection that turns out feasible.
+func ExampleZDD_ExpectedCostWithProbabilities() {
+	ctx := context.Background()
+	plans := buildSets(3, []int{1}, []int{2, 3}, []int{1, 2, 3})
+	costs := []float64{0, 3, 1, 2}
+	probabilities := []float64{0, 0.8, 0.5, 0.5}
+
+	result, err := plans.ExpectedCostWithProbabilities(ctx, costs, probabilities)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("expected cost %.2f, feasible with probability %.2f\n", result.Mean, result.Probability)
+
+	// Output:
+	// expected cost 4.33, feasible with probability 0.45
+}
+
+// ExampleZDD_CardinalityDistribution demonstrates a histogram of solution
+// sizes.
+func ExampleZDD_CardinalityDistribution() {
+	ctx := context.Background()
+	plans := buildSets(4, []int{1}, []int{2}, []int{1, 3}, []int{2, 3, 4}, []int{1, 2, 4})
+
+	counts, err := plans.CardinalityDistribution(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for k, c := range counts {
+		fmt.Printf("%d selected: %s\n", k, c)
+	}
+
+	// Output:
+	// 0 selected: 0
+	// 1 selected: 2
+	// 2 selected: 1
+	// 3 selected: 2
+	// 4 selected: 0
+}
+
+// ExampleZDD_CountWithCardinality demonstrates counting the solutions of one
+// size.
+func ExampleZDD_CountWithCardinality() {
+	ctx := context.Background()
+	plans := buildSets(4, []int{1}, []int{2}, []int{1, 3}, []int{2, 3, 4}, []int{1, 2, 4})
+
+	count, err := plans.CountWithCardinality(ctx, 3)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(count, "plans of three")
+
+	// Output:
+	// 2 plans of three
+}
+
+// ExampleZDD_MinCardinality demonstrates finding the smallest and largest
+// solutions.
+func ExampleZDD_MinCardinality() {
+	ctx := context.Background()
+	plans := buildSets(4, []int{1, 2}, []int{3}, []int{1, 3, 4}, []int{2, 3, 4})
+
+	smallest, err := plans.MinCardinality(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	largest, err := plans.MaxCardinality(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(smallest.Variables, largest.Variables)
+
+	// Output:
+	// [3] [1 3 4]
+}
+
+// ExampleZDD_FindKBestMax demonstrates maximizing total value directly.
+func ExampleZDD_FindKBestMax() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(4)
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 4, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+	values := []float64{0, 10, 20, 15, 30}
+
+	best, err := zdd.FindKBestMax(ctx, 2, values)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, s := range best {
+		fmt.Println(s.Variables, s.Cost)
+	}
+
+	// Output:
+	// [2 4] 50
+	// [3 4] 45
+}
+
+// ExampleAffineCost demonstrates penalties for not selecting a variable.
+func ExampleAffineCost() {
+	ctx := context.Background()
+	plans := buildSets(3, []int{1}, []int{2}, []int{1, 3})
+
+	model := gozdd.AffineCost{
+		Selected:    []float64{0, 5, 8, 3},
+		NotSelected: []float64{0, 0, 10, 0}, // leaving variable 2 out costs 10
+		Constant:    100,
+	}
+	best, err := plans.FindKBestWithModel(ctx, 3, model)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, s := range best {
+		fmt.Println(s.Variables, s.Cost)
+	}
+
+	// Output:
+	// [2] 108
+	// [1] 115
+	// [1 3] 118
+}
+
+// ExampleZDD_ParetoFront demonstrates trading off price against duration.
+func ExampleZDD_ParetoFront() {
+	ctx := context.Background()
+	routes := buildSets(3, []int{1}, []int{2}, []int{3}, []int{1, 2})
+	prices := []float64{0, 1, 2, 3}
+	durations := []float64{0, 3, 2, 1}
+
+	front, err := routes.ParetoFront(ctx, prices, durations)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, s := range front {
+		fmt.Println(s.Variables, s.Metadata["costs"])
+	}
+
+	// Output:
+	// [1] [1 3]
+	// [2] [2 2]
+	// [3] [3 1]
+}
+
+// ExampleZDD_FindLexicographic demonstrates breaking cost ties by makespan.
+func ExampleZDD_FindLexicographic() {
+	ctx := context.Background()
+	plans := buildSets(3, []int{1}, []int{2}, []int{3})
+	cost := []float64{0, 5, 4, 4}
+	makespan := []float64{0, 1, 9, 6}
+
+	best, err := plans.FindLexicographic(ctx, cost, makespan)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(best.Variables, best.Metadata["costs"])
+
+	// Output:
+	// [3] [4 6]
+}
+
+// ExampleZDD_FindBottleneck demonstrates keeping the heaviest load low.
+func ExampleZDD_FindBottleneck() {
+	ctx := context.Background()
+	// each solution is a set of servers that can carry the workload
+	assignments := buildSets(4, []int{1}, []int{2, 3}, []int{3, 4})
+	loads := []float64{0, 9, 5, 4, 6}
+
+	best, err := assignments.FindBottleneck(ctx, loads)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(best.Variables, best.Cost)
+
+	// Output:
+	// [2 3] 5
+}
+
+// ExampleZDD_Marginals demonstrates how often each variable is selected.
+func ExampleZDD_Marginals() {
+	ctx := context.Background()
+	zdd := buildSets(3, []int{1, 2}, []int{1, 3}, []int{1}, []int{2})
+
+	marginals, err := zdd.Marginals(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for v := 1; v <= zdd.Variables(); v++ {
+		fmt.Printf("variable %d: %.2f\n", v, marginals[v])
+	}
+
+	// Output:
+	// variable 1: 0.75
+	// variable 2: 0.50
+	// variable 3: 0.25
+}
+
+// ExampleZDD_SampleBoltzmann demonstrates how the temperature concentrates
+// samples on cheap solutions.
+func ExampleZDD_SampleBoltzmann() {
+	ctx := context.Background()
+	zdd := buildSets(3, []int{1}, []int{2}, []int{3})
+	costs := []float64{0, 1, 2, 3}
+
+	for _, temperature := range []float64{0.01, 1000} {
+		samples, err := zdd.SampleBoltzmann(ctx, 1000, costs, temperature, 42)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cheapest := 0
+		for _, s := range samples {
+			if s.Cost == 1 {
+				cheapest++
+			}
+		}
+		fmt.Printf("T=%g: cheapest in %.1f of the samples\n", temperature, float64(cheapest)/1000)
+	}
+
+	// Output:
+	// T=0.01: cheapest in 1.0 of the samples
+	// T=1000: cheapest in 0.3 of the samples
+}
+
+// ExampleZDD_All demonstrates iterating over solutions lazily.
+func ExampleZDD_All() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(3)
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 3, maxCount: 1}); err != nil {
+		log.Fatal(err)
+	}
+
+	for s := range zdd.All(ctx) {
+		fmt.Println(s.Variables)
+	}
+
+	// Output:
+	// []
+	// [1]
+	// [2]
+	// [3]
+}
+
+// ExampleZDD_EnumerateTo demonstrates streaming solutions as CSV.
+func ExampleZDD_EnumerateTo() {
+	ctx := context.Background()
+	zdd := buildSets(3, []int{1, 2}, []int{3})
+
+	if err := zdd.EnumerateTo(ctx, os.Stdout, gozdd.FormatCSV); err != nil {
+		log.Fatal(err)
+	}
+	if err := zdd.EnumerateTo(ctx, os.Stdout, gozdd.FormatJSON); err != nil {
+		log.Fatal(err)
+	}
+
+	// Output:
+	// x1,x2,x3
+	// 1,1,0
+	// 0,0,1
+	// {"variables":[1,2]}
+	// {"variables":[3]}
+}
+
+// ExampleZDD_FindKBestDistinct demonstrates listing cost levels instead of
+// tied solutions.
+func ExampleZDD_FindKBestDistinct() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(3)
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 3, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+	costs := []float64{0, 1, 1, 1}
+
+	levels, err := zdd.FindKBestDistinct(ctx, 3, costs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, s := range levels {
+		fmt.Printf("%v cost %.0f\n", s.Variables, s.Cost)
+	}
+
+	// Output:
+	// [] cost 0
+	// [1] cost 1
+	// [1 2] cost 2
+}
+
+// ExampleZDD_FindKBestFixed demonstrates asking for the best plans that must
+// include a given variable.
+func ExampleZDD_FindKBestFixed() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(3)
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 3, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+	costs := []float64{0, 1, 5, 2}
+
+	// variable 2 must be selected and variable 3 must not
+	best, err := zdd.FindKBestFixed(ctx, 3, costs, map[int]bool{2: true, 3: false})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, s := range best {
+		fmt.Printf("%v cost %.0f\n", s.Variables, s.Cost)
+	}
+
+	// Output:
+	// [2] cost 5
+	// [1 2] cost 6
+}
+
+// ExampleZDD_Sensitivity demonstrates how much forcing each decision costs.
+func ExampleZDD_Sensitivity() {
+	ctx := context.Background()
+	zdd := buildSets(3, []int{1}, []int{2, 3}, []int{1, 3})
+	costs := []float64{0, 4, 2, 1}
+
+	result, err := zdd.Sensitivity(ctx, costs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("optimal:", result.Optimal)
+	for v := 1; v <= zdd.Variables(); v++ {
+		fmt.Printf("x%d in: %v, out: %v\n", v, result.ForcedIn[v], result.ForcedOut[v])
+	}
+
+	// Output:
+	// optimal: 3
+	// x1 in: 4, out: 3
+	// x2 in: 3, out: 4
+	// x3 in: 3, out: 4
+}
+
+// ExampleEvaluateBottomUp demonstrates a custom evaluator that computes the
+// average solution size without enumerating solutions.
+func ExampleEvaluateBottomUp() {
+	ctx := context.Background()
+	zdd := buildSets(3, []int{1}, []int{2, 3}, []int{1, 2, 3})
+
+	// number of solutions and their total size below each node
+	type sizes struct{ count, total int }
+	result, err := gozdd.EvaluateBottomUp(ctx, zdd,
+		func(one bool) sizes {
+			if one {
+				return sizes{count: 1}
+			}
+			return sizes{}
+		},
+		func(level int, lo, hi sizes) sizes {
+			return sizes{count: lo.count + hi.count, total: lo.total + hi.total + hi.count}
+		})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("average size %.0f\n", float64(result.total)/float64(result.count))
+
+	// Output:
+	// average size 2
+}
+
+// ExampleZDD_PathProfile demonstrates counting the paths into and out of
+// every node.
+func ExampleZDD_PathProfile() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(3)
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 3, maxCount: 1}); err != nil {
+		log.Fatal(err)
+	}
+
+	profile, err := zdd.PathProfile(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, p := range profile {
+		fmt.Printf("level %d: %s paths in, %s solutions below\n", p.Level, p.Paths, p.Solutions)
+	}
+
+	// Output:
+	// level 3: 1 paths in, 4 solutions below
+	// level 2: 1 paths in, 3 solutions below
+	// level 1: 1 paths in, 2 solutions below
+}
+
+// ExampleZDD_FindBestBefore demonstrates getting a feasible answer with a
+// quality bound even when the deadline has already passed.
+func ExampleZDD_FindBestBefore() {
+	ctx := context.Background()
+	zdd := gozdd.NewZDD(3)
+	if err := zdd.Build(ctx, &SimpleSpec{vars: 3, maxCount: 2}); err != nil {
+		log.Fatal(err)
+	}
+	costs := []float64{0, -1, -2, -3}
+
+	for _, deadline := range []time.Time{time.Now().Add(-time.Second), time.Now().Add(time.Minute)} {
+		result, err := zdd.FindBestBefore(ctx, deadline, costs)
+		if err != nil {
+			log.Fatal(err)
+		}
+		best := result.Solutions[0]
+		fmt.Printf("%v cost %.0f, gap %.0f, optimal %v\n", best.Variables, best.Cost, result.Gap, result.Optimal)
+	}
+
+	// Output:
+	// [2 3] cost -5, gap 1, optimal false
+	// [2 3] cost -5, gap 0, optimal true
+}
+
+// ExampleCostMatrix demonstrates costs given per level and branch.
+func ExampleCostMatrix() {
+	ctx := context.Background()
+	zdd := buildSets(2, []int{1}, []int{2}, []int{1, 2})
+
+	// row l holds {not selected, selected}; skipping variable 2 costs 6
+	costs := gozdd.CostMatrix{
+		{0, 0},
+		{0, 4},
+		{6, 3},
+	}
+	best, err := zdd.FindKBestMatrix(ctx, 3, costs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, s := range best {
+		fmt.Println(s.Variables, s.Cost)
+	}
+
+	// Output:
+	// [2] 3
+	// [1 2] 7
+	// [1] 10
+}
+
+// ExampleZDD_Nearest demonstrates repairing an infeasible selection.
+func ExampleZDD_Nearest() {
+	ctx := context.Background()
+	// plans using at most two of four variables
+	zdd := buildSets(4, []int{1, 2}, []int{2, 3}, []int{3, 4}, []int{4})
+
+	// {1, 2, 3} is infeasible; the nearest plans drop one variable
+	nearest, err := zdd.NearestK(ctx, 3, []int{1, 2, 3})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, s := range nearest {
+		fmt.Println(s.Variables, "distance", s.Cost)
+	}
+
+	// Output:
+	// [1 2] distance 1
+	// [2 3] distance 1
+	// [3 4] distance 3
+}
+
+// ExampleCountEvaluator_skippedFree demonstrates counting skipped variables
+// as unconstrained.
+func ExampleCountEvaluator_skippedFree() {
+	ctx := context.Background()
+	// {1} and {3}: each solution skips the other two variables
+	zdd := buildSets(3, []int{1}, []int{3})
+
+	for _, skipped := range []gozdd.SkipSemantics{gozdd.SkippedUnselected, gozdd.SkippedFree} {
+		count, err := gozdd.EvaluateZDD(ctx, zdd, gozdd.CountEvaluator{Skipped: skipped})
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(count)
+	}
+
+	// Output:
+	// 2
+	// 6
+}
+
+// ExampleZDD_Shapley demonstrates power indices of a weighted vote.
+func ExampleZDD_Shapley() {
+	ctx := context.Background()
+	// voters with 3, 2 and 1 votes; 4 votes carry the motion
+	winning := buildSets(3, []int{1, 2}, []int{1, 3}, []int{1, 2, 3})
+
+	shapley, err := winning.Shapley(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	banzhaf, err := winning.Banzhaf(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for v := 1; v <= 3; v++ {
+		fmt.Printf("voter %d: Shapley %.2f, Banzhaf %.2f\n", v, shapley[v], banzhaf[v])
+	}
+
+	// Output:
+	// voter 1: Shapley 0.67, Banzhaf 0.75
+	// voter 2: Shapley 0.17, Banzhaf 0.25
+	// voter 3: Shapley 0.17, Banzhaf 0.25
+}
@@ -38,10 +38,6 @@ type ServerTaskSpec struct {
 	// Variable mapping: first N variables are server selection,
 	// remaining variables are task assignments
 	serverVars int
-	
-	// Skip tracking
-	SkipCount int
-	SkippedVariables int
 }
 
 func NewServerTaskSpec(servers []Server, tasks []Task) *ServerTaskSpec {
@@ -117,9 +113,6 @@ func (sts *ServerTaskSpec) GetChild(ctx context.Context, state gozdd.State, leve
 				}
 				
 				if nextLevel > 0 && nextLevel < level {
-					skippedCount := level - nextLevel
-					sts.SkipCount++
-					sts.SkippedVariables += skippedCount
 					return gozdd.NewSkipState(newState, nextLevel), nil
 				}
 			}
@@ -239,15 +232,15 @@ func runBenchmark(name string, spec gozdd.ConstraintSpec) (BenchmarkResult, erro
 	fmt.Printf("Variables: %d\n", spec.Variables())
 	
 	zdd := gozdd.NewZDD(spec.Variables())
-	
-	start := time.Now()
 	ctx := context.Background()
 	
-	if err := zdd.Build(ctx, spec); err != nil {
+	// The builder counts skips itself, so the spec needs no bookkeeping
+	stats, err := zdd.BuildWithStats(ctx, spec)
+	if err != nil {
 		return BenchmarkResult{}, fmt.Errorf("build failed: %v", err)
 	}
 	
-	buildTime := time.Since(start)
+	buildTime := stats.Elapsed
 	
 	count, err := zdd.Count(ctx)
 	if err != nil {
@@ -255,20 +248,17 @@ func runBenchmark(name string, spec gozdd.ConstraintSpec) (BenchmarkResult, erro
 	}
 	
 	result := BenchmarkResult{
-		BuildTime: buildTime,
-		Nodes:     zdd.Size(),
-		Solutions: int(count),
-	}
-	
-	// Extract skip statistics if available
-	if skipSpec, ok := spec.(*ServerTaskSpec); ok {
-		result.SkipCount = skipSpec.SkipCount
-		result.SkippedVars = skipSpec.SkippedVariables
+		BuildTime:   buildTime,
+		Nodes:       zdd.Size(),
+		Solutions:   int(count),
+		SkipCount:   int(stats.Skips),
+		SkippedVars: int(stats.SkippedLevels),
 	}
 	
 	fmt.Printf("Build time: %v\n", buildTime)
 	fmt.Printf("ZDD nodes: %d\n", zdd.Size())
 	fmt.Printf("Solutions: %d\n", count)
+	fmt.Printf("GetChild calls: %d\n", stats.GetChildCalls)
 	if result.SkipCount > 0 {
 		fmt.Printf("Skip operations: %d\n", result.SkipCount)
 		fmt.Printf("Variables skipped: %d\n", result.SkippedVars)
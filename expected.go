@@ -0,0 +1,227 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// ExpectedCostEvaluator computes the average cost of the solutions, which is
+// the expected cost of a uniformly random solution, and the variance of the
+// cost.
+//
+// Both are computed in one bottom-up pass without enumerating solutions:
+// each node stores the log of its solutions' total weight together with
+// their weighted mean cost and variance, so families with astronomically
+// many solutions are handled. The moments characterize the feasible region
+// before optimizing over it. Costs are given as for CostEvaluator. The
+// result is an ExpectedCostResult.
+//
+// With Probabilities set, each variable is instead selected independently
+// with its own probability, and the result is the expected cost given that
+// the selection is a solution, together with the probability that it is
+// one. This supports stochastic planning, such as the expected spend of
+// the feasible plans when each request arrives with a known probability.
+type ExpectedCostEvaluator struct {
+	// Costs specifies the cost of selecting each variable (1-based indexing)
+	Costs []float64
+
+	// SparseCosts maps variables to their selection cost; variables without
+	// an entry cost 0
+	SparseCosts map[int]float64
+
+	// Model computes costs per level and branch
+	Model CostModel
+
+	// Probabilities gives the probability of selecting each variable
+	// (1-based indexing), independently of the others. Nil selects every
+	// variable with probability 1/2, which weighs all solutions equally.
+	Probabilities []float64
+}
+
+// ExpectedCostResult is the average cost over all solutions.
+type ExpectedCostResult struct {
+	// Mean is the average cost; 0 when there are no solutions
+	Mean float64
+
+	// Variance is the variance of the cost around Mean; 0 when there are
+	// no solutions
+	Variance float64
+
+	// Found is false when the family has no solutions, or none with a
+	// positive probability
+	Found bool
+
+	// Probability is the probability that a random selection is a solution.
+	// Without Probabilities it is the fraction of all 2^n selections that
+	// are solutions.
+	Probability float64
+}
+
+// meanCost is the log weight and the weighted mean and variance of the cost
+// of the solutions below a node
+type meanCost struct {
+	logWeight float64
+	mean      float64
+	variance  float64
+}
+
+// Evaluate computes the average solution cost
+func (e ExpectedCostEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
+	costs, base, err := resolveCosts(zdd.vars, e.Costs, e.SparseCosts, e.Model)
+	if err != nil {
+		return ExpectedCostResult{}, err
+	}
+	skip, err := e.skipWeights(zdd.vars)
+	if err != nil {
+		return ExpectedCostResult{}, err
+	}
+
+	memo := map[NodeID]meanCost{
+		ZeroNode: {logWeight: math.Inf(-1)},
+		OneNode:  {},
+	}
+	// weight moves the weight of child up to just below level, with the
+	// levels in between unselected
+	weight := func(child NodeID, level int) float64 {
+		return memo[child].logWeight + skip.between(levelOf(zdd, child), level)
+	}
+	err = zdd.bottomUp(ctx, func(id NodeID, node Node) error {
+		lo := weight(node.Lo, node.Level) + skip.unselected(node.Level)
+		hi := weight(node.Hi, node.Level) + skip.selected(node.Level)
+		total := logAddExp(lo, hi)
+		if math.IsInf(total, -1) {
+			memo[id] = meanCost{logWeight: total}
+			return nil
+		}
+
+		// weigh both branches by their share of the weight; the variance
+		// gains the spread between the branch means
+		w := math.Exp(hi - total)
+		l, h := memo[node.Lo], memo[node.Hi]
+		h.mean += costs[node.Level]
+		memo[id] = meanCost{
+			logWeight: total,
+			mean:      (1-w)*l.mean + w*h.mean,
+			variance:  (1-w)*l.variance + w*h.variance + w*(1-w)*(h.mean-l.mean)*(h.mean-l.mean),
+		}
+		return nil
+	})
+	if err != nil {
+		return ExpectedCostResult{}, fmt.Errorf("expected cost evaluation failed: %w", err)
+	}
+
+	root := zdd.family()
+	total := weight(root, zdd.vars+1)
+	if math.IsInf(total, -1) {
+		return ExpectedCostResult{}, nil
+	}
+	return ExpectedCostResult{
+		Mean:        memo[root].mean + base,
+		Variance:    memo[root].variance,
+		Found:       true,
+		Probability: math.Exp(total),
+	}, nil
+}
+
+// skipWeights holds prefix sums of the log probabilities of leaving each
+// variable unselected, so the weight of a skipped run of levels is one
+// subtraction. Certain selections are counted apart, as their log is -Inf.
+type skipWeights struct {
+	logSelected []float64
+	sums        []float64
+	certain     []int
+}
+
+// skipWeights validates the probabilities and builds their prefix sums
+func (e ExpectedCostEvaluator) skipWeights(vars int) (skipWeights, error) {
+	p := e.Probabilities
+	if p != nil && len(p) <= vars {
+		return skipWeights{}, fmt.Errorf("insufficient probability data: need %d probabilities, got %d", vars, len(p)-1)
+	}
+
+	s := skipWeights{
+		logSelected: make([]float64, vars+1),
+		sums:        make([]float64, vars+1),
+		certain:     make([]int, vars+1),
+	}
+	for v := 1; v <= vars; v++ {
+		prob := 0.5
+		if p != nil {
+			prob = p[v]
+		}
+		if !(prob >= 0 && prob <= 1) {
+			return skipWeights{}, fmt.Errorf("%w: probability of variable %d must be in [0, 1], got %g", ErrInvalidConstraint, v, prob)
+		}
+		s.logSelected[v] = math.Log(prob)
+		s.sums[v], s.certain[v] = s.sums[v-1], s.certain[v-1]
+		if prob == 1 {
+			s.certain[v]++
+		} else {
+			s.sums[v] += math.Log1p(-prob)
+		}
+	}
+	return s, nil
+}
+
+// selected returns the log probability of selecting the variable at level
+func (s skipWeights) selected(level int) float64 {
+	return s.logSelected[level]
+}
+
+// unselected returns the log probability of leaving the variable at level
+// unselected
+func (s skipWeights) unselected(level int) float64 {
+	return s.between(level-1, level+1)
+}
+
+// between returns the log probability of leaving every variable strictly
+// between the levels unselected
+func (s skipWeights) between(low, high int) float64 {
+	if high-1 <= low {
+		return 0
+	}
+	if s.certain[high-1] > s.certain[low] {
+		return math.Inf(-1)
+	}
+	return s.sums[high-1] - s.sums[low]
+}
+
+// ExpectedCost returns the average cost over all solutions.
+//
+// This is a type-safe convenience method for ExpectedCostEvaluator. The
+// second result is false if the ZDD has no solutions.
+func (z *ZDD) ExpectedCost(ctx context.Context, costs []float64) (float64, bool, error) {
+	result, err := EvaluateZDD(ctx, z, ExpectedCostEvaluator{Costs: costs})
+	if err != nil {
+		return 0, false, err
+	}
+	r := result.(ExpectedCostResult)
+	return r.Mean, r.Found, nil
+}
+
+// CostMoments returns the mean and variance of the cost over all solutions.
+//
+// This is a type-safe convenience method for ExpectedCostEvaluator. Found
+// is false if the ZDD has no solutions.
+func (z *ZDD) CostMoments(ctx context.Context, costs []float64) (ExpectedCostResult, error) {
+	result, err := EvaluateZDD(ctx, z, ExpectedCostEvaluator{Costs: costs})
+	if err != nil {
+		return ExpectedCostResult{}, err
+	}
+	return result.(ExpectedCostResult), nil
+}
+
+// ExpectedCostWithProbabilities returns the expected cost of a solution when
+// each variable is selected independently with the given probability, and
+// the probability that the selection is a solution.
+//
+// This is a type-safe convenience method for ExpectedCostEvaluator with
+// Probabilities set.
+func (z *ZDD) ExpectedCostWithProbabilities(ctx context.Context, costs, probabilities []float64) (ExpectedCostResult, error) {
+	result, err := EvaluateZDD(ctx, z, ExpectedCostEvaluator{Costs: costs, Probabilities: probabilities})
+	if err != nil {
+		return ExpectedCostResult{}, err
+	}
+	return result.(ExpectedCostResult), nil
+}
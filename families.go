@@ -0,0 +1,135 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Empty returns a ZDD over vars variables holding no solutions.
+//
+// Together with Base, Single and PowerSet it provides the elementary
+// families from which others can be assembled with the family operations,
+// without writing a ConstraintSpec.
+func Empty(vars int, opts ...Option) *ZDD {
+	z := NewZDD(vars, opts...)
+	z.root = ZeroNode
+	return z
+}
+
+// Base returns a ZDD over vars variables whose only solution is the empty
+// set.
+func Base(vars int, opts ...Option) *ZDD {
+	z := NewZDD(vars, opts...)
+	z.root = OneNode
+	return z
+}
+
+// Single returns a ZDD over vars variables whose only solution is {v}.
+//
+// Returns ErrInvalidVariable if v is outside 1..vars.
+func Single(vars, v int, opts ...Option) (*ZDD, error) {
+	z := NewZDD(vars, opts...)
+	if err := z.checkVariable(v); err != nil {
+		return nil, err
+	}
+	z.root = z.nodes.AddNode(v, ZeroNode, OneNode)
+	return z, nil
+}
+
+// PowerSet returns a ZDD over vars variables holding every subset of them.
+//
+// The diagram has one node per variable, so it stays small even though it
+// represents 2^vars solutions. It is the identity of Intersect, and
+// NonSupersets on it yields every set avoiding given forbidden combinations.
+func PowerSet(vars int, opts ...Option) *ZDD {
+	z := NewZDD(vars, opts...)
+	z.root = z.nodes.powerSet(vars)
+	return z
+}
+
+// FromSets returns a ZDD over vars variables holding exactly the given sets.
+//
+// Each set lists selected variables in any order; repeated variables and
+// repeated sets are merged. This imports enumerated solutions, for example
+// from another solver or a test fixture, without writing a ConstraintSpec.
+//
+// Returns ErrInvalidVariable if a set contains a variable outside 1..vars.
+func FromSets(vars int, sets [][]int, opts ...Option) (*ZDD, error) {
+	z := NewZDD(vars, opts...)
+	ops := newFamilyOps(context.Background(), z.nodes)
+
+	root := ZeroNode
+	for _, set := range sets {
+		sorted := append([]int(nil), set...)
+		sort.Ints(sorted)
+
+		// chain the variables bottom-up into the single-set diagram
+		chain := OneNode
+		for i, v := range sorted {
+			if err := z.checkVariable(v); err != nil {
+				return nil, err
+			}
+			if i > 0 && v == sorted[i-1] {
+				continue
+			}
+			chain = z.nodes.AddNode(v, ZeroNode, chain)
+		}
+
+		var err error
+		if root, err = ops.union(root, chain); err != nil {
+			return nil, err
+		}
+	}
+
+	// the intermediate unions are of no further use
+	z.nodes.ops.clear()
+
+	z.root = root
+	return z, nil
+}
+
+// CardinalityRange returns a ZDD over vars variables holding every subset
+// with between kMin and kMax elements, inclusive.
+//
+// The diagram has O(vars·kMax) nodes: one per variable and number of
+// variables selected so far. Intersecting it with another family restricts
+// that family to solutions of the given sizes.
+//
+// Returns ErrInvalidConstraint if kMin is negative or exceeds kMax.
+func CardinalityRange(vars, kMin, kMax int, opts ...Option) (*ZDD, error) {
+	if kMin < 0 || kMin > kMax {
+		return nil, fmt.Errorf("%w: cardinality range [%d, %d]", ErrInvalidConstraint, kMin, kMax)
+	}
+	kMax = min(kMax, vars)
+
+	z := NewZDD(vars, opts...)
+
+	// below[j] is the family over the variables below the current level
+	// when j variables have been selected above it
+	below := make([]NodeID, kMax+1)
+	for j := range below {
+		below[j] = ZeroNode
+		if j >= kMin {
+			below[j] = OneNode
+		}
+	}
+
+	for l := 1; l <= vars; l++ {
+		next := make([]NodeID, kMax+1)
+		for j := 0; j <= kMax; j++ {
+			hi := ZeroNode
+			if j < kMax {
+				hi = below[j+1]
+			}
+			next[j] = z.nodes.AddNode(l, below[j], hi)
+		}
+		below = next
+	}
+
+	z.root = ZeroNode
+	if len(below) > 0 {
+		z.root = below[0]
+	}
+	return z, nil
+}
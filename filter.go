@@ -0,0 +1,186 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+)
+
+// Filter returns the solutions of z that also satisfy spec.
+//
+// Instead of building spec from scratch and intersecting, Filter walks the
+// existing diagram and the states of spec together, so only assignments that
+// are already solutions of z are ever passed to spec. Constraints can thus be
+// layered onto a diagram one at a time without rebuilding it. spec is used
+// exactly as by Build, including SkipState transitions and CompletionSpec,
+// and the configured timeout, trace and validation cache apply. The result
+// uses the configuration of z; z is not modified.
+//
+// Returns an error if spec.Variables() differs from Variables() or if the
+// context is cancelled.
+func (z *ZDD) Filter(ctx context.Context, spec ConstraintSpec) (*ZDD, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("%w: spec is nil", ErrInvalidConstraint)
+	}
+	if spec.Variables() != z.vars {
+		return nil, fmt.Errorf("%w: spec variables (%d) != ZDD variables (%d)", ErrInvalidVariable, spec.Variables(), z.vars)
+	}
+
+	if z.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, z.config.Timeout)
+		defer cancel()
+	}
+
+	ops, roots, err := workspace(ctx, z)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &filter{ops: ops, spec: z.wrapSpec(spec), states: make(map[NodeID]*buildStates)}
+	root, err := f.run(roots[0], spec.InitialState(), z.vars)
+	if err != nil {
+		return nil, fmt.Errorf("filter failed: %w", err)
+	}
+
+	return z.derive(z.vars, ops.nt, root)
+}
+
+// filter holds the traversal state of one Filter call
+type filter struct {
+	ops  *familyOps
+	spec ConstraintSpec
+
+	// states memoizes results per node of the filtered diagram
+	states map[NodeID]*buildStates
+}
+
+// run returns the solutions of family f, over variables level down to 1,
+// that spec accepts from state.
+//
+// The walk is depth-first on an explicit stack of frames, as in
+// buildDepthFirst, so deep diagrams need no recursion.
+func (fl *filter) run(f NodeID, state State, level int) (NodeID, error) {
+	root, pending, err := fl.enter(f, state, level)
+	if err != nil || pending == nil {
+		return root, err
+	}
+
+	stack := []filterFrame{*pending}
+	for {
+		fr := &stack[len(stack)-1]
+
+		// the Hi arc of a don't-care variable follows the transition of the
+		// Lo arc into the Hi family
+		if fr.next <= 1 {
+			take := fr.next == 1
+			fr.next++
+			var child NodeID
+			var pending *filterFrame
+			switch {
+			case !take && fr.branch.explores(false):
+				child, pending, err = fl.child(fr.fLo, fr.state, fr.level, false)
+			case take && fr.fHi != ZeroNode && fr.branch.explores(true):
+				child, pending, err = fl.child(fr.fHi, fr.state, fr.level, fr.branch != BranchDontCare)
+			default:
+				fr.set(take, ZeroNode)
+				continue
+			}
+			if err != nil {
+				return NullNode, err
+			}
+			if pending != nil {
+				stack = append(stack, *pending)
+			} else {
+				fr.set(take, child)
+			}
+			continue
+		}
+
+		node := fl.ops.nt.AddNode(fr.level, fr.lo, fr.hi)
+		fr.memo.store(fr.state, fr.level, node)
+
+		// return the node to the arc of the parent that is waiting for it
+		stack = stack[:len(stack)-1]
+		if len(stack) == 0 {
+			return node, nil
+		}
+		parent := &stack[len(stack)-1]
+		parent.set(parent.next == 2, node)
+	}
+}
+
+// filterFrame is a node of the filtered diagram whose arcs are being built
+type filterFrame struct {
+	buildFrame
+
+	// fLo and fHi are the families of z below the two arcs
+	fLo, fHi NodeID
+
+	memo *buildStates
+}
+
+// enter returns the solutions of family f that spec accepts from state at
+// level if they are known without expanding the state, or a frame to
+// expand it
+func (fl *filter) enter(f NodeID, state State, level int) (NodeID, *filterFrame, error) {
+	if f == ZeroNode {
+		return ZeroNode, nil, nil
+	}
+	if level == 0 {
+		if f == OneNode && fl.spec.IsValid(state) {
+			return OneNode, nil, nil
+		}
+		return ZeroNode, nil, nil
+	}
+	if err := fl.ops.checkCancel(); err != nil {
+		return NullNode, nil, err
+	}
+
+	// every completion is accepted, so f is kept as it is
+	if cs, ok := unwrapSpec(fl.spec).(CompletionSpec); ok && cs.AllCompletionsValid(state, level) {
+		return f, nil, nil
+	}
+
+	memo := fl.states[f]
+	if memo == nil {
+		memo = newBuildStates()
+		fl.states[f] = memo
+	}
+	if existing := memo.lookup(state, level); existing != NullNode {
+		return existing, nil, nil
+	}
+
+	// f does not select the current variable unless its top node is at it
+	fLo, fHi := f, ZeroNode
+	if n := fl.ops.node(f); n.Level == level {
+		fLo, fHi = n.Lo, n.Hi
+	}
+
+	return NullNode, &filterFrame{
+		buildFrame: buildFrame{state: state, level: level, branch: branchingOf(fl.spec, state, level)},
+		fLo:        fLo,
+		fHi:        fHi,
+		memo:       memo,
+	}, nil
+}
+
+// child follows one arc of spec from state at level into family f
+func (fl *filter) child(f NodeID, state State, level int, take bool) (NodeID, *filterFrame, error) {
+	next, err := fl.spec.GetChild(fl.ops.ctx, state, level, take)
+	if err != nil {
+		// constraint violation prunes the branch
+		return ZeroNode, nil, nil
+	}
+
+	next, target, err := childLevel(next, level)
+	if err != nil {
+		return NullNode, nil, err
+	}
+
+	// skipped variables are not selected, so drop the solutions selecting them
+	for fl.ops.level(f) > target {
+		f = fl.ops.node(f).Lo
+	}
+
+	return fl.enter(f, next, target)
+}
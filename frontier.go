@@ -0,0 +1,361 @@
+package gozdd
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// frontierShards is the number of independently locked parts of a level's
+// state table when several workers insert into it
+const frontierShards = 64
+
+// frontierChunk is the number of states a worker expands per claim
+const frontierChunk = 16
+
+// frontierBuilder constructs a ZDD level by level.
+//
+// A top-down pass expands the distinct states of each level, from the
+// highest down, into the states of lower levels; equal states reached from
+// different parents are merged as soon as they are inserted. A bottom-up
+// pass then creates the nodes, lowest level first. The states of a level are
+// expanded independently of each other, so workers share them out, and no
+// call recurses, so the depth of the diagram does not matter.
+//
+// Workers insert the states they reach as they go, so the order of a level's
+// states, and with it the node IDs, depends on scheduling. A deterministic
+// builder lets the workers only compute the transitions and inserts their
+// targets afterwards on one goroutine, in the order of the parent states.
+type frontierBuilder struct {
+	z             *ZDD
+	spec          ConstraintSpec
+	workers       int
+	deterministic bool
+
+	// complete is the spec's CompletionSpec, if it implements one
+	complete CompletionSpec
+
+	// annotator is the spec's NodeAnnotator, which needs states kept until
+	// their nodes exist
+	annotator NodeAnnotator
+
+	// levels holds the frontier of each level, 1..vars
+	levels []frontier
+}
+
+// frontier holds the distinct states reached at one level
+type frontier struct {
+	once   sync.Once
+	shards []frontierShard
+}
+
+// frontierShard is a part of a frontier guarded by its own lock
+type frontierShard struct {
+	mu      sync.Mutex
+	byHash  map[uint64][]*frontierEntry
+	entries []*frontierEntry
+}
+
+// frontierEntry is one distinct state of a level and, once expanded, its arcs
+type frontierEntry struct {
+	state  State
+	lo, hi frontierArc
+
+	// complete marks states whose every completion is feasible
+	complete bool
+
+	// dontCare marks states whose Hi arc is their Lo arc
+	dontCare bool
+
+	// node is the node built for the state in the bottom-up pass
+	node NodeID
+}
+
+// frontierTarget is a state reached by an arc, waiting to be inserted
+type frontierTarget struct {
+	state State
+	level int
+}
+
+// frontierArc leads to a state of a lower level or to a terminal
+type frontierArc struct {
+	entry    *frontierEntry
+	terminal NodeID
+}
+
+// target returns the node the arc leads to; lower levels are built first
+func (a frontierArc) target() NodeID {
+	if a.entry != nil {
+		return a.entry.node
+	}
+	return a.terminal
+}
+
+// buildFrontier constructs the diagram of spec level by level with the
+// configured number of workers and returns its root
+func (z *ZDD) buildFrontier(ctx context.Context, spec ConstraintSpec) (NodeID, error) {
+	if z.vars == 0 {
+		if spec.IsValid(spec.InitialState()) {
+			return OneNode, nil
+		}
+		return ZeroNode, nil
+	}
+
+	b := &frontierBuilder{
+		z:             z,
+		spec:          spec,
+		workers:       max(z.config.Workers, 1),
+		deterministic: z.config.Deterministic,
+		levels:        make([]frontier, z.vars+1),
+	}
+	b.complete, _ = unwrapSpec(spec).(CompletionSpec)
+	b.annotator, _ = unwrapSpec(spec).(NodeAnnotator)
+
+	root := b.insert(spec.InitialState(), z.vars)
+	for l := z.vars; l >= 1; l-- {
+		if err := b.expand(ctx, l); err != nil {
+			return NullNode, err
+		}
+	}
+	for l := 1; l <= z.vars; l++ {
+		if err := b.build(ctx, l); err != nil {
+			return NullNode, err
+		}
+	}
+	return root.node, nil
+}
+
+// shardsOf returns the shards of the frontier at level, creating them on
+// first use so that levels no state reaches cost nothing. Only concurrent
+// inserts need more than one shard.
+func (b *frontierBuilder) shardsOf(level int) []frontierShard {
+	f := &b.levels[level]
+	f.once.Do(func() {
+		n := 1
+		if b.workers > 1 && !b.deterministic {
+			n = frontierShards
+		}
+		f.shards = make([]frontierShard, n)
+		for i := range f.shards {
+			f.shards[i].byHash = make(map[uint64][]*frontierEntry)
+		}
+	})
+	return f.shards
+}
+
+// insert returns the entry of state at level, adding it if no equal state
+// is present
+func (b *frontierBuilder) insert(state State, level int) *frontierEntry {
+	h := state.Hash()
+	shards := b.shardsOf(level)
+	s := &shards[h%uint64(len(shards))]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.byHash[h] {
+		if e.state.Equal(state) {
+			b.z.counters.stateFound(true)
+			return e
+		}
+	}
+	b.z.counters.stateFound(false)
+	e := &frontierEntry{state: state}
+	s.byHash[h] = append(s.byHash[h], e)
+	s.entries = append(s.entries, e)
+	b.z.progress.state()
+	return e
+}
+
+// entries returns the states of level in shard order
+func (b *frontierBuilder) entries(level int) []*frontierEntry {
+	var all []*frontierEntry
+	for i := range b.levels[level].shards {
+		all = append(all, b.levels[level].shards[i].entries...)
+	}
+	return all
+}
+
+// expand computes the arcs of every state at level, inserting their targets
+// into the frontiers below
+func (b *frontierBuilder) expand(ctx context.Context, level int) error {
+	entries := b.entries(level)
+	var targets [][2]frontierTarget
+	if b.deterministic {
+		targets = make([][2]frontierTarget, len(entries))
+	}
+	err := b.parallel(ctx, len(entries), func(i int) error {
+		var next *[2]frontierTarget
+		if targets != nil {
+			next = &targets[i]
+		}
+		return b.expandEntry(ctx, entries[i], level, next)
+	})
+	if err != nil {
+		return err
+	}
+
+	// insert deferred targets in the order of their parents, Lo before Hi
+	for i, next := range targets {
+		e := entries[i]
+		for take, arc := range []*frontierArc{&e.lo, &e.hi} {
+			if t := next[take]; t.state != nil {
+				arc.entry = b.insert(t.state, t.level)
+			}
+		}
+	}
+
+	// no state is inserted at this level any more
+	for i := range b.levels[level].shards {
+		b.levels[level].shards[i].byHash = nil
+	}
+	if b.annotator == nil {
+		for _, e := range entries {
+			e.state = nil
+		}
+	}
+	b.z.log.levelExpanded(level, len(entries))
+	return nil
+}
+
+// expandEntry computes both arcs of one state. If next is not nil, the
+// states the arcs reach are stored there, Lo first, instead of inserted.
+func (b *frontierBuilder) expandEntry(ctx context.Context, e *frontierEntry, level int, next *[2]frontierTarget) error {
+	b.z.progress.step(level)
+	if b.complete != nil && b.complete.AllCompletionsValid(e.state, level) {
+		e.complete = true
+		return nil
+	}
+
+	// branches the spec rules out lead to the 0-terminal, and the Hi arc
+	// of a don't-care state is its Lo arc
+	branch := branchingOf(b.spec, e.state, level)
+	e.dontCare = branch == BranchDontCare
+	e.lo, e.hi = frontierArc{terminal: ZeroNode}, frontierArc{terminal: ZeroNode}
+
+	var lo, hi frontierTarget
+	var err error
+	if branch.explores(false) {
+		if e.lo, lo, err = b.arc(ctx, e.state, level, false); err != nil {
+			return err
+		}
+	}
+	if branch.explores(true) && !e.dontCare {
+		if e.hi, hi, err = b.arc(ctx, e.state, level, true); err != nil {
+			return err
+		}
+	}
+
+	if next != nil {
+		*next = [2]frontierTarget{lo, hi}
+		return nil
+	}
+	if lo.state != nil {
+		e.lo.entry = b.insert(lo.state, lo.level)
+	}
+	if hi.state != nil {
+		e.hi.entry = b.insert(hi.state, hi.level)
+	}
+	return nil
+}
+
+// arc follows one branch of state at level. It returns the arc if the branch
+// ends in a terminal, or else the state it reaches, which is still to be
+// inserted.
+func (b *frontierBuilder) arc(ctx context.Context, state State, level int, take bool) (frontierArc, frontierTarget, error) {
+	next, err := b.spec.GetChild(ctx, state, level, take)
+	if err != nil {
+		// constraint violation prunes the branch
+		return frontierArc{terminal: ZeroNode}, frontierTarget{}, nil
+	}
+
+	next, target, err := childLevel(next, level)
+	if err != nil {
+		return frontierArc{}, frontierTarget{}, err
+	}
+
+	if target == 0 {
+		if b.spec.IsValid(next) {
+			return frontierArc{terminal: OneNode}, frontierTarget{}, nil
+		}
+		return frontierArc{terminal: ZeroNode}, frontierTarget{}, nil
+	}
+	return frontierArc{}, frontierTarget{state: next, level: target}, nil
+}
+
+// build creates the nodes of every state at level; the levels below are
+// already built
+func (b *frontierBuilder) build(ctx context.Context, level int) error {
+	before := b.z.nodes.Size()
+	for _, e := range b.entries(level) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b.z.progress.step(level)
+		if e.complete {
+			e.node = b.z.nodes.powerSet(level)
+		} else if e.dontCare {
+			e.node = b.z.addNode(level, e.lo.target(), e.lo.target())
+		} else {
+			e.node = b.z.addNode(level, e.lo.target(), e.hi.target())
+		}
+		if b.annotator != nil {
+			b.z.annotateBuilt(b.annotator, e.state, level, e.node)
+			e.state = nil
+		}
+	}
+	b.z.log.levelBuilt(level, b.z.nodes.Size()-before)
+	return nil
+}
+
+// parallel calls fn for 0..n-1 on the configured number of workers and
+// returns the first error. Small batches run on the calling goroutine.
+func (b *frontierBuilder) parallel(ctx context.Context, n int, fn func(i int) error) error {
+	if b.workers == 1 || n <= frontierChunk {
+		for i := 0; i < n; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		next     atomic.Int64
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for w := 0; w < b.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				start := int(next.Add(frontierChunk)) - frontierChunk
+				if start >= n {
+					return
+				}
+				for i := start; i < min(start+frontierChunk, n); i++ {
+					err := ctx.Err()
+					if err == nil {
+						err = fn(i)
+					}
+					if err != nil {
+						errOnce.Do(func() {
+							firstErr = err
+							cancel()
+						})
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
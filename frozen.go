@@ -0,0 +1,242 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"math"
+	"math/big"
+)
+
+// FrozenZDD is an immutable snapshot of a ZDD for concurrent reads.
+//
+// A FrozenZDD stores the nodes reachable from the root in a private slice
+// that is never modified after Freeze returns, so any number of goroutines
+// may evaluate and enumerate it simultaneously without locks. Node IDs are
+// renumbered so that every node's children have smaller IDs; the terminal
+// IDs ZeroNode and OneNode keep their meaning.
+//
+// Each query allocates its own working memory, so queries never interfere.
+// Use ZDD for construction and family operations, then Freeze the result
+// for serving.
+type FrozenZDD struct {
+	// nodes is indexed by NodeID; children always precede their parents
+	nodes []Node
+
+	root NodeID
+	vars int
+
+	// decorator and stable carry SolutionDecorator and StableOrder of the frozen ZDD
+	decorator func(*Solution)
+	stable    bool
+}
+
+// Freeze returns an immutable snapshot of the ZDD.
+//
+// The snapshot is independent of z: later builds, releases or garbage
+// collections of z do not affect it. An unbuilt ZDD freezes to the empty
+// family. The snapshot keeps the SolutionDecorator and StableOrder of z.
+func (z *ZDD) Freeze() (*FrozenZDD, error) {
+	f := &FrozenZDD{
+		nodes:     []Node{{}, {}, {}},
+		vars:      z.vars,
+		decorator: z.config.SolutionDecorator,
+		stable:    z.config.StableOrder,
+	}
+
+	ids := map[NodeID]NodeID{ZeroNode: ZeroNode, OneNode: OneNode}
+
+	var copyNode func(id NodeID) (NodeID, error)
+	copyNode = func(id NodeID) (NodeID, error) {
+		if mapped, ok := ids[id]; ok {
+			return mapped, nil
+		}
+
+		node, err := z.GetNode(id)
+		if err != nil {
+			return NullNode, err
+		}
+		lo, err := copyNode(node.Lo)
+		if err != nil {
+			return NullNode, err
+		}
+		hi, err := copyNode(node.Hi)
+		if err != nil {
+			return NullNode, err
+		}
+
+		mapped := NodeID(len(f.nodes))
+		f.nodes = append(f.nodes, Node{Level: node.Level, Lo: lo, Hi: hi})
+		ids[id] = mapped
+		return mapped, nil
+	}
+
+	root, err := copyNode(z.family())
+	if err != nil {
+		return nil, fmt.Errorf("freeze failed: %w", err)
+	}
+
+	f.root = root
+	return f, nil
+}
+
+// Root returns the root node ID of the snapshot.
+func (f *FrozenZDD) Root() NodeID {
+	return f.root
+}
+
+// Variables returns the number of decision variables.
+func (f *FrozenZDD) Variables() int {
+	return f.vars
+}
+
+// Size returns the number of nodes, including both terminals.
+func (f *FrozenZDD) Size() int {
+	return len(f.nodes) - 1
+}
+
+// GetNode returns the node with the given ID.
+//
+// Returns ErrInvalidNode if id is NullNode or out of range.
+func (f *FrozenZDD) GetNode(id NodeID) (Node, error) {
+	if id == NullNode || int(id) >= len(f.nodes) {
+		return Node{}, fmt.Errorf("%w: node ID %d", ErrInvalidNode, id)
+	}
+	return f.nodes[id], nil
+}
+
+// Count returns the number of solutions.
+//
+// Returns ErrCountOverflow if the count exceeds the int64 range.
+func (f *FrozenZDD) Count(ctx context.Context) (int64, error) {
+	counts := make([]int64, len(f.nodes))
+	counts[OneNode] = 1
+
+	for id := OneNode + 1; int(id) < len(f.nodes); id++ {
+		if id%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+		node := f.nodes[id]
+		if counts[node.Lo] > math.MaxInt64-counts[node.Hi] {
+			return 0, fmt.Errorf("%w: at node %d", ErrCountOverflow, id)
+		}
+		counts[id] = counts[node.Lo] + counts[node.Hi]
+	}
+
+	return counts[f.root], nil
+}
+
+// CountBig returns the exact number of solutions, however large.
+func (f *FrozenZDD) CountBig(ctx context.Context) (*big.Int, error) {
+	counts := make([]*big.Int, len(f.nodes))
+	counts[ZeroNode] = big.NewInt(0)
+	counts[OneNode] = big.NewInt(1)
+
+	for id := OneNode + 1; int(id) < len(f.nodes); id++ {
+		if id%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		node := f.nodes[id]
+		counts[id] = new(big.Int).Add(counts[node.Lo], counts[node.Hi])
+	}
+
+	return counts[f.root], nil
+}
+
+// FindKBest finds the k solutions with the lowest costs.
+//
+// Costs use the same 1-based layout as ZDD.FindKBest. Solutions are returned
+// in ascending cost order.
+func (f *FrozenZDD) FindKBest(ctx context.Context, k int, costs []float64) ([]*Solution, error) {
+	if k <= 0 {
+		return []*Solution{}, nil
+	}
+	if len(costs) <= f.vars {
+		return nil, fmt.Errorf("insufficient cost data: need %d costs, got %d", f.vars, len(costs)-1)
+	}
+
+	bound := make([]float64, len(f.nodes))
+	bound[ZeroNode] = math.Inf(1)
+	for id := OneNode + 1; int(id) < len(f.nodes); id++ {
+		node := f.nodes[id]
+		bound[id] = math.Min(bound[node.Lo], bound[node.Hi]+costs[node.Level])
+	}
+
+	s := &kBestSearch{
+		ctx:    ctx,
+		node:   f.GetNode,
+		bound:  func(id NodeID) float64 { return bound[id] },
+		costs:  costs,
+		k:      k,
+		stable: f.stable,
+	}
+	if err := s.search(f.root, 0); err != nil {
+		return nil, fmt.Errorf("k-best evaluation failed: %w", err)
+	}
+
+	solutions := s.sorted()
+	if f.decorator != nil {
+		for _, sol := range solutions {
+			f.decorator(sol)
+		}
+	}
+	return solutions, nil
+}
+
+// Sets returns an iterator over all solutions, each given as its selected
+// variables in ascending order. Solutions are yielded in the order documented
+// at WithStableOrder.
+//
+// Iteration stops early if ctx is cancelled.
+func (f *FrozenZDD) Sets(ctx context.Context) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		var path []int
+
+		var walk func(id NodeID) bool
+		walk = func(id NodeID) bool {
+			if ctx.Err() != nil {
+				return false
+			}
+			switch id {
+			case ZeroNode:
+				return true
+			case OneNode:
+				vars := make([]int, len(path))
+				for i, v := range path {
+					vars[len(path)-1-i] = v
+				}
+				return yield(vars)
+			}
+
+			node := f.nodes[id]
+			if !walk(node.Lo) {
+				return false
+			}
+			path = append(path, node.Level)
+			more := walk(node.Hi)
+			path = path[:len(path)-1]
+			return more
+		}
+
+		walk(f.root)
+	}
+}
+
+// Thaw returns a mutable ZDD holding the same family, for use with the
+// construction and family-operation APIs.
+func (f *FrozenZDD) Thaw(opts ...Option) *ZDD {
+	z := NewZDD(f.vars, opts...)
+
+	for id := OneNode + 1; int(id) < len(f.nodes); id++ {
+		node := f.nodes[id]
+		// children precede parents, so IDs line up with the fresh table
+		z.nodes.AddNode(node.Level, node.Lo, node.Hi)
+	}
+
+	z.root = f.root
+	return z
+}
@@ -0,0 +1,192 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sort"
+)
+
+// RegisterGroup names a set of variables so solutions can be summarized by it.
+//
+// Groups typically mirror the structure of the model, for example "placement"
+// and "routing" variables. Registering a name again replaces the previous group.
+// Groups are carried over to ZDDs derived over the same variables.
+//
+// Returns ErrInvalidVariable if any variable is outside 1..Variables().
+func (z *ZDD) RegisterGroup(name string, vars []int) error {
+	members := make([]int, 0, len(vars))
+	seen := make(map[int]bool, len(vars))
+	for _, v := range vars {
+		if v < 1 || v > z.vars {
+			return fmt.Errorf("%w: group %q variable %d", ErrInvalidVariable, name, v)
+		}
+		if !seen[v] {
+			seen[v] = true
+			members = append(members, v)
+		}
+	}
+	sort.Ints(members)
+
+	groups := make(map[string][]int, len(z.groups)+1)
+	for k, g := range z.groups {
+		groups[k] = g
+	}
+	groups[name] = members
+	z.groups = groups
+
+	return nil
+}
+
+// Group returns the variables registered under name.
+func (z *ZDD) Group(name string) ([]int, bool) {
+	members, ok := z.groups[name]
+	if !ok {
+		return nil, false
+	}
+	vars := make([]int, len(members))
+	copy(vars, members)
+	return vars, true
+}
+
+// ProjectGroup returns the distinct restrictions of all solutions to a group.
+//
+// The result is a ZDD over the same variables whose members are S ∩ group for
+// every solution S, with duplicates merged. For example, projecting onto the
+// placement variables yields the distinct placement patterns regardless of
+// routing.
+//
+// Returns ErrInvalidVariable if no group is registered under name.
+func (z *ZDD) ProjectGroup(ctx context.Context, name string) (*ZDD, error) {
+	members, ok := z.groups[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown group %q", ErrInvalidVariable, name)
+	}
+	return z.Project(ctx, members)
+}
+
+// Project returns the distinct restrictions of all solutions to vars.
+//
+// The result is a ZDD over the same variables whose members are S ∩ vars for
+// every solution S, with duplicates merged; the other variables are
+// existentially quantified away. For example, projecting onto the server
+// selection variables yields the distinct server choices regardless of task
+// assignment. ProjectGroup does the same for a registered group.
+//
+// Returns ErrInvalidVariable if any variable is outside 1..Variables().
+func (z *ZDD) Project(ctx context.Context, vars []int) (*ZDD, error) {
+	keep := make([]bool, z.vars+1)
+	for _, v := range vars {
+		if err := z.checkVariable(v); err != nil {
+			return nil, err
+		}
+		keep[v] = true
+	}
+
+	ops, roots, err := workspace(ctx, z)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := ops.project(roots[0], keep, make(map[NodeID]NodeID))
+	if err != nil {
+		return nil, fmt.Errorf("projection failed: %w", err)
+	}
+
+	return z.derive(z.vars, ops.nt, root)
+}
+
+// GroupPatterns iterates over the distinct restrictions of solutions to a group.
+//
+// Each yielded slice holds the selected group variables in ascending order.
+// Patterns are yielded in the order documented at WithStableOrder.
+// Iteration stops early if ctx is cancelled.
+//
+// Returns ErrInvalidVariable if no group is registered under name.
+func (z *ZDD) GroupPatterns(ctx context.Context, name string) (iter.Seq[[]int], error) {
+	projected, err := z.ProjectGroup(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func([]int) bool) {
+		projected.enumerate(ctx, func(vars []int) bool {
+			return yield(vars)
+		})
+	}, nil
+}
+
+// project removes every variable not marked in keep, merging the resulting sets
+func (o *familyOps) project(f NodeID, keep []bool, memo map[NodeID]NodeID) (NodeID, error) {
+	if f == ZeroNode || f == OneNode {
+		return f, nil
+	}
+	if r, ok := memo[f]; ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	node := o.node(f)
+	lo, err := o.project(node.Lo, keep, memo)
+	if err != nil {
+		return NullNode, err
+	}
+	hi, err := o.project(node.Hi, keep, memo)
+	if err != nil {
+		return NullNode, err
+	}
+
+	var r NodeID
+	if node.Level < len(keep) && keep[node.Level] {
+		r = o.nt.AddNode(node.Level, lo, hi)
+	} else if r, err = o.union(lo, hi); err != nil {
+		return NullNode, err
+	}
+
+	memo[f] = r
+	return r, nil
+}
+
+// enumerate calls yield for every set in the family, selected variables in
+// ascending order, until yield returns false or ctx is cancelled
+func (z *ZDD) enumerate(ctx context.Context, yield func(vars []int) bool) {
+	var path []int
+
+	var walk func(id NodeID) bool
+	walk = func(id NodeID) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		default:
+		}
+
+		if id == ZeroNode || id == NullNode {
+			return true
+		}
+		if id == OneNode {
+			vars := make([]int, len(path))
+			for i, v := range path {
+				vars[len(path)-1-i] = v
+			}
+			return yield(vars)
+		}
+
+		node, err := z.GetNode(id)
+		if err != nil {
+			return false
+		}
+
+		if !walk(node.Lo) {
+			return false
+		}
+
+		path = append(path, node.Level)
+		more := walk(node.Hi)
+		path = path[:len(path)-1]
+		return more
+	}
+
+	walk(z.root)
+}
@@ -0,0 +1,137 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// ConstraintImpact describes how a single constraint shapes the solution space
+// of a CompositeConstraintSpec.
+type ConstraintImpact struct {
+	// Index is the position of the constraint in the composite spec
+	Index int
+
+	// Name identifies the constraint for reporting purposes
+	Name string
+
+	// Prunes is the number of branches this constraint pruned during the full build
+	Prunes int64
+
+	// NodesWithout is the ZDD size when this constraint is left out
+	NodesWithout int
+
+	// CountWithout is the solution count when this constraint is left out.
+	// Leaving out a constraint can admit far more than 2^63 solutions, so
+	// counts are exact big integers.
+	CountWithout *big.Int
+
+	// NodeDelta is NodesWithout minus the size of the full ZDD
+	NodeDelta int
+
+	// CountDelta is CountWithout minus the solution count of the full ZDD.
+	// A zero delta means the constraint is redundant given the others.
+	CountDelta *big.Int
+}
+
+// ImpactReport summarizes the effect of every constraint in a composite spec.
+type ImpactReport struct {
+	// Nodes is the size of the ZDD built with all constraints
+	Nodes int
+
+	// Count is the number of solutions with all constraints
+	Count *big.Int
+
+	// Constraints holds one entry per constraint, in spec order
+	Constraints []ConstraintImpact
+}
+
+// AnalyzeConstraintImpact measures which constraints of a composite spec
+// actually shape the solution space.
+//
+// The analysis builds the ZDD once with every constraint to attribute prunes,
+// then performs one leave-one-out build per constraint to measure how node count
+// and solution count change when that constraint is removed.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout handling
+//   - spec: The composite specification to analyze
+//   - opts: Configuration options applied to every build
+//
+// The prune counters of spec are reset before the full build.
+func AnalyzeConstraintImpact(ctx context.Context, spec *CompositeConstraintSpec, opts ...Option) (*ImpactReport, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("%w: spec is nil", ErrInvalidConstraint)
+	}
+
+	spec.ResetPruneCounts()
+	nodes, count, err := buildAndCount(ctx, spec, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("full build: %w", err)
+	}
+	prunes := spec.PruneCounts()
+
+	report := &ImpactReport{
+		Nodes:       nodes,
+		Count:       count,
+		Constraints: make([]ConstraintImpact, len(spec.constraints)),
+	}
+
+	for i, constraint := range spec.constraints {
+		sub := spec.withConstraints(withoutConstraint(spec.constraints, i))
+
+		subNodes, subCount, err := buildAndCount(ctx, sub, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("build without constraint %d: %w", i, err)
+		}
+
+		report.Constraints[i] = ConstraintImpact{
+			Index:        i,
+			Name:         constraintName(constraint),
+			Prunes:       prunes[i],
+			NodesWithout: subNodes,
+			CountWithout: subCount,
+			NodeDelta:    subNodes - nodes,
+			CountDelta:   new(big.Int).Sub(subCount, count),
+		}
+	}
+
+	return report, nil
+}
+
+// buildAndCount builds a fresh ZDD for spec and returns its size and solution count
+func buildAndCount(ctx context.Context, spec ConstraintSpec, opts ...Option) (int, *big.Int, error) {
+	zdd := NewZDD(spec.Variables(), opts...)
+	if err := zdd.Build(ctx, spec); err != nil {
+		return 0, nil, err
+	}
+
+	count, err := zdd.CountBig(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return zdd.Size(), count, nil
+}
+
+// withoutConstraint returns a copy of constraints with the i-th entry removed
+func withoutConstraint(constraints []Constraint, i int) []Constraint {
+	rest := make([]Constraint, 0, len(constraints)-1)
+	rest = append(rest, constraints[:i]...)
+	return append(rest, constraints[i+1:]...)
+}
+
+// constraintName returns a human-readable name for a constraint
+func constraintName(c Constraint) string {
+	switch cc := c.(type) {
+	case CustomConstraint:
+		if cc.Name != "" {
+			return cc.Name
+		}
+	case *CustomConstraint:
+		if cc.Name != "" {
+			return cc.Name
+		}
+	}
+	return fmt.Sprintf("%T", c)
+}
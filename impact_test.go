@@ -0,0 +1,48 @@
+package gozdd_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/zzenonn/go-zdd"
+)
+
+func TestConstraintImpactBeyondInt64(t *testing.T) {
+	const vars = 70
+	forbidX1 := gozdd.CustomConstraint{
+		Name: "no x1",
+		ValidateFunc: func(ctx context.Context, state gozdd.State, level int, take bool) error {
+			if take && level == 1 {
+				return fmt.Errorf("variable 1 is forbidden")
+			}
+			return nil
+		},
+	}
+	spec := gozdd.NewCompositeSpec(vars, gozdd.BasicState{Counters: []int{0}},
+		gozdd.CountConstraint{Min: 0, Max: 1}, forbidX1)
+
+	report, err := gozdd.AnalyzeConstraintImpact(context.Background(), spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the composite spec never accepts the empty set
+	pow := func(n uint) *big.Int { return new(big.Int).Lsh(big.NewInt(1), n) }
+	one := big.NewInt(1)
+	want := []*big.Int{
+		new(big.Int).Sub(pow(vars-1), one), // any set without x1
+		big.NewInt(vars),                   // any single variable
+	}
+	if report.Count.Cmp(big.NewInt(vars-1)) != 0 {
+		t.Fatalf("Count = %v, want %d", report.Count, vars-1)
+	}
+	for i, impact := range report.Constraints {
+		delta := new(big.Int).Sub(want[i], report.Count)
+		if impact.CountWithout.Cmp(want[i]) != 0 || impact.CountDelta.Cmp(delta) != 0 {
+			t.Errorf("%s: CountWithout = %v, CountDelta = %v, want %v and %v",
+				impact.Name, impact.CountWithout, impact.CountDelta, want[i], delta)
+		}
+	}
+}
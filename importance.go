@@ -0,0 +1,230 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// PowerIndex selects how ImportanceEvaluator scores variables.
+type PowerIndex int
+
+const (
+	// BanzhafIndex averages a variable's effect over all coalitions of the
+	// other variables, each equally likely
+	BanzhafIndex PowerIndex = iota
+
+	// ShapleyIndex averages a variable's effect over all orders in which
+	// the variables could join, so coalitions of every size weigh the same
+	ShapleyIndex
+)
+
+// ImportanceEvaluator scores how much each variable decides whether a
+// selection is a solution, with Banzhaf or Shapley power indices.
+//
+// The family is read as a cooperative game: a set of variables wins if it is
+// a solution. A variable's effect on a set of other variables is +1 if
+// adding it turns the set into a solution, -1 if it turns a solution into a
+// non-solution, and 0 otherwise; the index averages this effect. For
+// monotone families, such as plans that meet a coverage requirement, these
+// are the classic voting power indices: variables the constraints hinge on
+// score high and irrelevant ones score 0. The Shapley values of all
+// variables sum to 1 if the full set is a solution, minus 1 if the empty set
+// is one.
+//
+// Both indices are linear in the game, so no pairs of sets are compared.
+// The Banzhaf index comes from the marginal counts in O(Size()) time. The
+// Shapley index needs the solutions counted by size through every node,
+// kept in log space so huge families do not overflow, which takes
+// O(Size()·n²) time for n variables.
+//
+// The result is a []float64 of length Variables()+1 whose index v holds the
+// score of variable v; index 0 is unused. Attributing an optimal cost needs
+// no evaluator: for costs given per variable the cost of the optimum is
+// already the sum of its selected variables' costs.
+type ImportanceEvaluator struct {
+	// Index selects the power index
+	Index PowerIndex
+}
+
+// Evaluate computes the power index of every variable
+func (e ImportanceEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
+	var scores []float64
+	var err error
+	switch e.Index {
+	case BanzhafIndex:
+		scores, err = banzhaf(ctx, zdd)
+	case ShapleyIndex:
+		scores, err = shapley(ctx, zdd)
+	default:
+		return nil, fmt.Errorf("%w: unknown power index %d", ErrInvalidConstraint, e.Index)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("importance evaluation failed: %w", err)
+	}
+	return scores, nil
+}
+
+// banzhaf computes (2·N_v - N) / 2^(n-1) for every variable v, where N_v
+// solutions of N select v
+func banzhaf(ctx context.Context, zdd *ZDD) ([]float64, error) {
+	scores := make([]float64, zdd.vars+1)
+	if zdd.vars == 0 {
+		return scores, nil
+	}
+
+	counts, err := zdd.scaledCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	marginals, err := MarginalEvaluator{}.Evaluate(ctx, zdd)
+	if err != nil {
+		return nil, err
+	}
+
+	count := counts[zdd.family()]
+	scale := math.Ldexp(count.m, count.e-(zdd.vars-1))
+	for v, m := range marginals.([]float64) {
+		if v > 0 {
+			scores[v] = scale * (2*m - 1)
+		}
+	}
+	return scores, nil
+}
+
+// shapley computes the Shapley value of every variable as
+//
+//	1/n · Σ_s (A_v(s+1) + A_v(s) - N(s)) / C(n-1, s)
+//
+// where N(s) solutions have size s and A_v(s) of them select v
+func shapley(ctx context.Context, zdd *ZDD) ([]float64, error) {
+	n := zdd.vars
+	scores := make([]float64, n+1)
+	if n == 0 {
+		return scores, nil
+	}
+
+	// below[id][k] is the log count of solutions below id selecting k
+	// variables; missing sizes have no solutions
+	below := map[NodeID][]float64{
+		ZeroNode: nil,
+		OneNode:  {0},
+	}
+	err := zdd.bottomUp(ctx, func(id NodeID, node Node) error {
+		lo, hi := below[node.Lo], below[node.Hi]
+		poly := logPoly(max(len(lo), len(hi)+1))
+		for k := range poly {
+			if k < len(lo) {
+				poly[k] = logAddExp(poly[k], lo[k])
+			}
+			if k > 0 && k <= len(hi) {
+				poly[k] = logAddExp(poly[k], hi[k-1])
+			}
+		}
+		below[id] = poly
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// above[id][k] is the log count of root paths to id selecting k
+	// variables; selecting v at a node joins them with the Hi solutions
+	root := zdd.family()
+	above := map[NodeID][]float64{root: {0}}
+	selecting := make([][]float64, n+1)
+	for l := n; l >= 1; l-- {
+		selecting[l] = logPoly(n + 1)
+		for _, id := range zdd.layer(l) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			node, err := zdd.GetNode(id)
+			if err != nil {
+				return nil, err
+			}
+			paths := above[id]
+			for a, pa := range paths {
+				for b, pb := range below[node.Hi] {
+					selecting[l][a+b+1] = logAddExp(selecting[l][a+b+1], pa+pb)
+				}
+			}
+			above[node.Lo] = addLogPoly(above[node.Lo], paths, 0)
+			above[node.Hi] = addLogPoly(above[node.Hi], paths, 1)
+			delete(above, id)
+		}
+	}
+
+	sizes := below[root]
+	at := func(poly []float64, s int) float64 {
+		if s < len(poly) {
+			return poly[s]
+		}
+		return math.Inf(-1)
+	}
+	for v := 1; v <= n; v++ {
+		var sum float64
+		for s := 0; s < n; s++ {
+			coalitions := logBinomial(n-1, s)
+			sum += math.Exp(selecting[v][s+1]-coalitions) +
+				math.Exp(selecting[v][s]-coalitions) -
+				math.Exp(at(sizes, s)-coalitions)
+		}
+		scores[v] = sum / float64(n)
+	}
+	return scores, nil
+}
+
+// logPoly returns a log-space polynomial of length n with all coefficients 0
+func logPoly(n int) []float64 {
+	poly := make([]float64, n)
+	for k := range poly {
+		poly[k] = math.Inf(-1)
+	}
+	return poly
+}
+
+// addLogPoly adds src shifted up by shift to dst in log space, growing dst
+// as needed
+func addLogPoly(dst, src []float64, shift int) []float64 {
+	if need := len(src) + shift; len(dst) < need {
+		dst = append(dst, logPoly(need-len(dst))...)
+	}
+	for k, c := range src {
+		dst[k+shift] = logAddExp(dst[k+shift], c)
+	}
+	return dst
+}
+
+// logBinomial returns the natural log of n choose k
+func logBinomial(n, k int) float64 {
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
+
+// Banzhaf returns the Banzhaf index of each variable v at index v.
+//
+// This is a type-safe convenience method for ImportanceEvaluator with
+// BanzhafIndex.
+func (z *ZDD) Banzhaf(ctx context.Context) ([]float64, error) {
+	return z.importance(ctx, BanzhafIndex)
+}
+
+// Shapley returns the Shapley value of each variable v at index v.
+//
+// This is a type-safe convenience method for ImportanceEvaluator with
+// ShapleyIndex.
+func (z *ZDD) Shapley(ctx context.Context) ([]float64, error) {
+	return z.importance(ctx, ShapleyIndex)
+}
+
+// importance evaluates the power index of every variable
+func (z *ZDD) importance(ctx context.Context, index PowerIndex) ([]float64, error) {
+	result, err := EvaluateZDD(ctx, z, ImportanceEvaluator{Index: index})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]float64), nil
+}
@@ -0,0 +1,186 @@
+package gozdd_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/zzenonn/go-zdd"
+)
+
+// colexLess orders ascending variable lists by their highest differing
+// variable, as documented at WithStableOrder
+func colexLess(a, b []int) bool {
+	i, j := len(a)-1, len(b)-1
+	for ; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if a[i] != b[j] {
+			return a[i] < b[j]
+		}
+	}
+	return i < j
+}
+
+// enumerateKBest is the reference: cost every set, sort, filter and cut
+func enumerateKBest(sets [][]int, e gozdd.KBestEvaluator) []*gozdd.Solution {
+	var all []*gozdd.Solution
+	for _, set := range sets {
+		ok := true
+		for v, in := range e.Fixed {
+			if contains(set, v) != in {
+				ok = false
+			}
+		}
+		if !ok {
+			continue
+		}
+		cost := 0.0
+		for _, v := range set {
+			cost += e.Costs[v]
+		}
+		all = append(all, &gozdd.Solution{Variables: sortedSet(set), Cost: cost})
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+		if a.Cost != b.Cost {
+			return (a.Cost < b.Cost) != e.Maximize
+		}
+		return colexLess(a.Variables, b.Variables)
+	})
+
+	var best []*gozdd.Solution
+	for _, s := range all {
+		if len(best) == e.K {
+			break
+		}
+		if e.Distinct && len(best) > 0 && best[len(best)-1].Cost == s.Cost {
+			continue
+		}
+		best = append(best, s)
+	}
+	return best
+}
+
+// randomSets returns distinct random subsets of 1..vars
+func randomSets(r *rand.Rand, vars int) [][]int {
+	var sets [][]int
+	for mask := 0; mask < 1<<vars; mask++ {
+		if r.Intn(3) != 0 {
+			continue
+		}
+		set := []int{}
+		for v := 1; v <= vars; v++ {
+			if mask&(1<<(v-1)) != 0 {
+				set = append(set, v)
+			}
+		}
+		sets = append(sets, set)
+	}
+	return sets
+}
+
+func TestKBestMatchesEnumeration(t *testing.T) {
+	ctx := context.Background()
+	r := rand.New(rand.NewSource(4290))
+
+	for it := 0; it < 300; it++ {
+		vars := 1 + r.Intn(7)
+		sets := randomSets(r, vars)
+		zdd, err := gozdd.FromSets(vars, sets)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// small integer costs give many ties and exact sums
+		costs := make([]float64, vars+1)
+		for v := 1; v <= vars; v++ {
+			costs[v] = float64(r.Intn(5) - 2)
+		}
+		e := gozdd.KBestEvaluator{
+			K:        1 + r.Intn(len(sets)+3),
+			Costs:    costs,
+			Maximize: r.Intn(3) == 0,
+			Distinct: r.Intn(3) == 0,
+		}
+		if r.Intn(2) == 0 {
+			e.Fixed = map[int]bool{}
+			for n := 1 + r.Intn(2); n > 0; n-- {
+				e.Fixed[1+r.Intn(vars)] = r.Intn(2) == 0
+			}
+		}
+
+		result, err := gozdd.EvaluateZDD(ctx, zdd, e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := result.(gozdd.KBestResult)
+		want := enumerateKBest(sets, e)
+
+		if got.Count != len(sets) {
+			t.Fatalf("%+v: count %d, want %d", e, got.Count, len(sets))
+		}
+		if fmt.Sprint(solutionList(got.Solutions)) != fmt.Sprint(solutionList(want)) {
+			t.Fatalf("sets %v, %+v:\n got %v\nwant %v", sets, e, solutionList(got.Solutions), solutionList(want))
+		}
+	}
+}
+
+// solutionList formats solutions as variables:cost for comparison
+func solutionList(sols []*gozdd.Solution) []string {
+	out := make([]string, len(sols))
+	for i, s := range sols {
+		out[i] = fmt.Sprintf("%v:%g", s.Variables, s.Cost)
+	}
+	return out
+}
+
+func TestKBestManySolutions(t *testing.T) {
+	ctx := context.Background()
+	const vars = 200
+
+	// 2^200 solutions; selecting variable v costs v
+	zdd := gozdd.PowerSet(vars)
+	costs := make([]float64, vars+1)
+	for v := 1; v <= vars; v++ {
+		costs[v] = float64(v)
+	}
+
+	tests := []struct {
+		name string
+		e    gozdd.KBestEvaluator
+		want string
+	}{
+		{"cheapest", gozdd.KBestEvaluator{K: 5, Costs: costs},
+			"[[]:0 [1]:1 [2]:2 [1 2]:3 [3]:3]"},
+		{"distinct", gozdd.KBestEvaluator{K: 5, Costs: costs, Distinct: true},
+			"[[]:0 [1]:1 [2]:2 [1 2]:3 [1 3]:4]"},
+		{"fixed", gozdd.KBestEvaluator{K: 3, Costs: costs, Fixed: map[int]bool{200: true, 1: false}},
+			"[[200]:200 [2 200]:202 [3 200]:203]"},
+	}
+	for _, tt := range tests {
+		result, err := gozdd.EvaluateZDD(ctx, zdd, tt.e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := result.(gozdd.KBestResult)
+		if fmt.Sprint(solutionList(got.Solutions)) != tt.want {
+			t.Errorf("%s: got %v, want %s", tt.name, solutionList(got.Solutions), tt.want)
+		}
+		if got.Count != math.MaxInt {
+			t.Errorf("%s: count %d, want it saturated at math.MaxInt", tt.name, got.Count)
+		}
+	}
+
+	// the most expensive: everything, then everything but the cheapest
+	best, err := zdd.FindKBestMax(ctx, 2, costs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(best) != 2 || len(best[0].Variables) != vars || best[0].Cost != vars*(vars+1)/2 ||
+		len(best[1].Variables) != vars-1 || best[1].Variables[0] != 2 {
+		t.Errorf("FindKBestMax: got %v", solutionList(best))
+	}
+}
@@ -0,0 +1,131 @@
+package gozdd
+
+import (
+	"fmt"
+	"iter"
+	"sort"
+	"sync"
+)
+
+// levelIndex caches the reachable nodes of a ZDD grouped by level
+type levelIndex struct {
+	mu sync.Mutex
+
+	// root and nodes identify the diagram the index was computed for
+	root  NodeID
+	nodes *NodeTable
+
+	// byLevel[l] lists the reachable nodes at level l in ascending ID order
+	byLevel [][]NodeID
+}
+
+// NodesAtLevel returns the nodes reachable from the root at level l, in
+// ascending NodeID order.
+//
+// Level 0 yields the reachable terminals. Levels outside 0..Variables() and
+// unbuilt ZDDs yield nothing. The per-level index is computed once per
+// diagram and reused, so layer-by-layer algorithms can call NodesAtLevel for
+// every level at linear total cost.
+//
+// Example:
+//
+//	// Compute the width profile of the diagram
+//	for l := zdd.Variables(); l >= 1; l-- {
+//	    width := 0
+//	    for range zdd.NodesAtLevel(l) {
+//	        width++
+//	    }
+//	    fmt.Println(l, width)
+//	}
+func (z *ZDD) NodesAtLevel(l int) iter.Seq[NodeID] {
+	layer := z.layer(l)
+	return func(yield func(NodeID) bool) {
+		for _, id := range layer {
+			if !yield(id) {
+				return
+			}
+		}
+	}
+}
+
+// Lo returns the target of the Lo arc of a non-terminal node.
+func (z *ZDD) Lo(id NodeID) (NodeID, error) {
+	node, err := z.arcNode(id)
+	if err != nil {
+		return NullNode, err
+	}
+	return node.Lo, nil
+}
+
+// Hi returns the target of the Hi arc of a non-terminal node.
+func (z *ZDD) Hi(id NodeID) (NodeID, error) {
+	node, err := z.arcNode(id)
+	if err != nil {
+		return NullNode, err
+	}
+	return node.Hi, nil
+}
+
+// arcNode returns id's node, rejecting terminals, which have no arcs
+func (z *ZDD) arcNode(id NodeID) (Node, error) {
+	node, err := z.GetNode(id)
+	if err != nil {
+		return Node{}, err
+	}
+	if node.IsTerminal() {
+		return Node{}, fmt.Errorf("%w: terminal %d has no arcs", ErrInvalidNode, id)
+	}
+	return node, nil
+}
+
+// layer returns the cached reachable nodes at level l
+func (z *ZDD) layer(l int) []NodeID {
+	idx := &z.levels
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.byLevel == nil || idx.root != z.root || idx.nodes != z.nodes {
+		idx.byLevel = z.groupByLevel()
+		idx.root = z.root
+		idx.nodes = z.nodes
+	}
+
+	if l < 0 || l >= len(idx.byLevel) {
+		return nil
+	}
+	return idx.byLevel[l]
+}
+
+// groupByLevel collects the nodes reachable from the root, grouped by level
+func (z *ZDD) groupByLevel() [][]NodeID {
+	byLevel := make([][]NodeID, z.vars+1)
+	if z.root == NullNode {
+		return byLevel
+	}
+
+	seen := make(map[NodeID]bool)
+	stack := []NodeID{z.root}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		node, err := z.nodes.GetNode(id)
+		if err != nil || node.Level > z.vars {
+			continue
+		}
+		byLevel[node.Level] = append(byLevel[node.Level], id)
+		if !node.IsTerminal() {
+			stack = append(stack, node.Lo, node.Hi)
+		}
+	}
+
+	for _, layer := range byLevel {
+		sort.Slice(layer, func(i, j int) bool { return layer[i] < layer[j] })
+	}
+	return byLevel
+}
@@ -0,0 +1,98 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+)
+
+// LevelSpec is a constraint specification whose transitions choose the level
+// at which construction continues, as in TdZdd.
+//
+// It replaces SkipState: instead of wrapping the child state, GetChild
+// returns the child state and its level side by side. The state is never
+// wrapped, so its Hash and Equal are used as they are, and a level that
+// does not lie below the current one fails Build with ErrInvalidLevel
+// rather than being followed. Pass a LevelSpec to Build, Filter or any
+// other function taking a ConstraintSpec through FromLevelSpec.
+//
+// A LevelSpec may also implement CompletionSpec and NodeAnnotator.
+type LevelSpec interface {
+	// Variables returns the total number of decision variables.
+	Variables() int
+
+	// InitialState returns the state before any variable is assigned.
+	InitialState() State
+
+	// GetChild computes the state after assigning the variable at level and
+	// the level of the next variable to decide. Returning level-1 decides
+	// the variables one by one; a lower level leaves the variables in
+	// between unselected, and 0 or less ends the assignment so that IsValid
+	// judges the state. Returning an error prunes the branch.
+	GetChild(ctx context.Context, state State, level int, take bool) (State, int, error)
+
+	// IsValid reports whether a state that has ended its assignment is a
+	// feasible solution.
+	IsValid(state State) bool
+}
+
+// FromLevelSpec returns a ConstraintSpec building the diagram of spec.
+//
+// Variables skipped by a transition are unselected in every solution built
+// through them, exactly as with SkipState, so evaluators treat them as any
+// other unselected variable; CountEvaluator with SkippedFree counts them as
+// free instead.
+func FromLevelSpec(spec LevelSpec) ConstraintSpec {
+	return levelSpec{spec: spec}
+}
+
+// levelSpec adapts a LevelSpec to ConstraintSpec, expressing lower levels
+// as SkipState so that wrappers and builders handle them uniformly
+type levelSpec struct {
+	spec LevelSpec
+}
+
+// unwrap returns the adapted spec
+func (s levelSpec) unwrap() interface{} {
+	return s.spec
+}
+
+// Variables delegates to the spec
+func (s levelSpec) Variables() int {
+	return s.spec.Variables()
+}
+
+// InitialState delegates to the spec
+func (s levelSpec) InitialState() State {
+	return s.spec.InitialState()
+}
+
+// GetChild delegates to the spec and skips to the level it returns. Levels
+// that are not below level are passed on as well, for the builder to reject.
+func (s levelSpec) GetChild(ctx context.Context, state State, level int, take bool) (State, error) {
+	child, next, err := s.spec.GetChild(ctx, state, level, take)
+	if err != nil {
+		return nil, err
+	}
+	if next == level-1 {
+		return child, nil
+	}
+	return NewSkipState(child, next), nil
+}
+
+// IsValid delegates to the spec
+func (s levelSpec) IsValid(state State) bool {
+	return s.spec.IsValid(state)
+}
+
+// childLevel returns the state a transition from level leads to and its
+// level, 0 for the terminals. Skips must lead below level.
+func childLevel(child State, level int) (State, int, error) {
+	skip, ok := child.(*SkipState)
+	if !ok {
+		return child, level - 1, nil
+	}
+	if skip.SkipTo >= level {
+		return nil, 0, fmt.Errorf("%w: skip from level %d to level %d", ErrInvalidLevel, level, skip.SkipTo)
+	}
+	return skip.State, max(skip.SkipTo, 0), nil
+}
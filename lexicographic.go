@@ -0,0 +1,121 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+)
+
+// LexicographicEvaluator finds the best solution under several objectives
+// ranked by priority.
+//
+// Solutions are compared on the first objective, ties are broken by the
+// second, and so on, as in minimizing cost and then makespan. A single
+// bottom-up pass keeps the best cost vector below every node, so no solutions
+// are enumerated. Among solutions with equal cost vectors the first in the
+// order documented at WithStableOrder is returned.
+//
+// The result is an OptimalResult whose Cost is the first objective;
+// Metadata["costs"] holds the whole vector.
+type LexicographicEvaluator struct {
+	// Objectives holds one cost vector per objective in priority order, each
+	// laid out like CostEvaluator.Costs (1-based indexing)
+	Objectives [][]float64
+}
+
+// lexEntry is the best completion of a node: its cost vector and whether it
+// takes the Hi arc
+type lexEntry struct {
+	costs []float64
+	take  bool
+}
+
+// Evaluate finds the lexicographically smallest solution
+func (e LexicographicEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
+	if err := checkObjectives(zdd.vars, e.Objectives); err != nil {
+		return OptimalResult{Found: false}, err
+	}
+	root := zdd.family()
+	if root == ZeroNode {
+		return OptimalResult{Found: false}, nil
+	}
+
+	best := map[NodeID]lexEntry{OneNode: {costs: make([]float64, len(e.Objectives))}}
+	err := zdd.bottomUp(ctx, func(id NodeID, node Node) error {
+		// quasi-reduced diagrams keep nodes whose Hi arc has no solutions
+		hi, ok := best[node.Hi]
+		if !ok {
+			best[id] = lexEntry{costs: best[node.Lo].costs}
+			return nil
+		}
+		entry := lexEntry{costs: make([]float64, len(hi.costs)), take: true}
+		for i := range entry.costs {
+			entry.costs[i] = hi.costs[i] + e.Objectives[i][node.Level]
+		}
+		// Lo wins ties, which keeps the stable order
+		if lo, ok := best[node.Lo]; ok && !lexLess(entry.costs, lo.costs) {
+			entry = lexEntry{costs: lo.costs}
+		}
+		best[id] = entry
+		return nil
+	})
+	if err != nil {
+		return OptimalResult{Found: false}, fmt.Errorf("lexicographic evaluation failed: %w", err)
+	}
+
+	vars := []int{}
+	for id := root; id != OneNode; {
+		node, err := zdd.GetNode(id)
+		if err != nil {
+			return OptimalResult{Found: false}, err
+		}
+		if best[id].take {
+			vars = append(vars, node.Level)
+			id = node.Hi
+		} else {
+			id = node.Lo
+		}
+	}
+	for i, j := 0, len(vars)-1; i < j; i, j = i+1, j-1 {
+		vars[i], vars[j] = vars[j], vars[i]
+	}
+
+	costs := append([]float64(nil), best[root].costs...)
+	solution := &Solution{
+		Variables: vars,
+		Cost:      costs[0],
+		Metadata:  map[string]interface{}{"costs": costs},
+	}
+	return OptimalResult{Solution: solution, Cost: costs[0], Found: true}, nil
+}
+
+// lexLess reports whether cost vector a precedes b lexicographically
+func lexLess(a, b []float64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+// checkObjectives validates the cost vectors of a multi-objective query
+func checkObjectives(vars int, objectives [][]float64) error {
+	if len(objectives) == 0 {
+		return fmt.Errorf("%w: no objectives", ErrInvalidConstraint)
+	}
+	for i, costs := range objectives {
+		if len(costs) <= vars {
+			return fmt.Errorf("insufficient cost data for objective %d: need %d costs, got %d", i, vars, len(costs)-1)
+		}
+	}
+	return nil
+}
+
+// FindLexicographic returns the best solution under objectives ranked by
+// priority, breaking ties in each objective by the next.
+//
+// This is a type-safe convenience method for LexicographicEvaluator.
+// Returns ErrInfeasible if the ZDD has no solutions.
+func (z *ZDD) FindLexicographic(ctx context.Context, objectives ...[]float64) (*Solution, error) {
+	return z.extremeSolution(ctx, LexicographicEvaluator{Objectives: objectives})
+}
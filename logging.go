@@ -0,0 +1,138 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// LevelTrace is the level of the events WithLogger emits for single spec
+// transitions, such as a pruned branch. It lies below slog.LevelDebug, so
+// handlers drop these events unless configured with this level.
+const LevelTrace = slog.LevelDebug - 4
+
+// buildLog logs the events of one Build. A nil *buildLog logs nothing, so
+// builders call it unconditionally.
+type buildLog struct {
+	logger *slog.Logger
+	ctx    context.Context
+	start  time.Time
+
+	// trace is set when single transitions are logged too
+	trace bool
+}
+
+// newBuildLog returns a log writing to logger, or nil if logger is nil or
+// discards debug events
+func newBuildLog(ctx context.Context, logger *slog.Logger) *buildLog {
+	if logger == nil || !logger.Enabled(ctx, slog.LevelDebug) {
+		return nil
+	}
+	return &buildLog{
+		logger: logger,
+		ctx:    ctx,
+		start:  time.Now(),
+		trace:  logger.Enabled(ctx, LevelTrace),
+	}
+}
+
+// started logs the construction strategy
+func (b *buildLog) started(vars, workers int, breadthFirst bool) {
+	if b == nil {
+		return
+	}
+	strategy := "depth-first"
+	if breadthFirst {
+		strategy = "breadth-first"
+	}
+	b.logger.LogAttrs(b.ctx, slog.LevelDebug, "build started",
+		slog.Int("vars", vars),
+		slog.String("strategy", strategy),
+		slog.Int("workers", workers))
+}
+
+// tracing reports whether single transitions are logged
+func (b *buildLog) tracing() bool {
+	return b != nil && b.trace
+}
+
+// levelExpanded logs that the states of level have been expanded
+func (b *buildLog) levelExpanded(level, states int) {
+	if b == nil {
+		return
+	}
+	b.logger.LogAttrs(b.ctx, slog.LevelDebug, "level expanded",
+		slog.Int("level", level),
+		slog.Int("states", states),
+		slog.Duration("elapsed", time.Since(b.start)))
+}
+
+// levelBuilt logs that the nodes of level have been created
+func (b *buildLog) levelBuilt(level, nodes int) {
+	if b == nil {
+		return
+	}
+	b.logger.LogAttrs(b.ctx, slog.LevelDebug, "level built",
+		slog.Int("level", level),
+		slog.Int("nodes", nodes),
+		slog.Duration("elapsed", time.Since(b.start)))
+}
+
+// finished logs the outcome of the build with its cache hit rates
+func (b *buildLog) finished(spec ConstraintSpec, counters *buildCounters, root NodeID, nodes int, err error) {
+	if b == nil {
+		return
+	}
+	if err != nil {
+		b.logger.LogAttrs(b.ctx, slog.LevelDebug, "build failed",
+			slog.Any("error", err),
+			slog.Duration("elapsed", time.Since(b.start)))
+		return
+	}
+
+	hits, misses := counters.stateHits.Load(), counters.stateMisses.Load()
+	attrs := []slog.Attr{
+		slog.Any("root", root),
+		slog.Int("nodes", nodes),
+		slog.Int64("states", misses),
+		slog.Float64("state_hit_rate", hitRate(hits, misses)),
+		slog.Int64("prunes", counters.totalPrunes()),
+		slog.Int64("skips", counters.skips.Load()),
+	}
+	if cs, ok := spec.(cachedSpec); ok {
+		hits, misses := cs.cache.Stats()
+		attrs = append(attrs, slog.Float64("validation_hit_rate", hitRate(hits, misses)))
+	}
+	attrs = append(attrs, slog.Duration("elapsed", time.Since(b.start)))
+	b.logger.LogAttrs(b.ctx, slog.LevelDebug, "build finished", attrs...)
+}
+
+// logEvaluation logs one EvaluateZDD call; cached is set if the result came
+// from the result cache
+func (z *ZDD) logEvaluation(ctx context.Context, evaluator Evaluator, start time.Time, cached bool, err error) {
+	logger := z.config.Logger
+	if logger == nil || !logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs := []slog.Attr{slog.String("evaluator", fmt.Sprintf("%T", evaluator))}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	} else if z.config.ResultCache {
+		hits, misses := z.ResultCacheStats()
+		attrs = append(attrs,
+			slog.Bool("cached", cached),
+			slog.Float64("cache_hit_rate", hitRate(hits, misses)))
+	}
+	attrs = append(attrs, slog.Duration("elapsed", time.Since(start)))
+	logger.LogAttrs(ctx, slog.LevelDebug, "evaluated", attrs...)
+}
+
+// hitRate returns the fraction of lookups that hit, or 0 without lookups
+func hitRate(hits, misses int64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
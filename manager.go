@@ -0,0 +1,246 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Manager owns a node table shared by several ZDDs.
+//
+// ZDDs registered with a manager keep their nodes in its table, so identical
+// sub-diagrams are stored once. The manager tracks which ZDDs are live;
+// releasing a ZDD makes its nodes collectable, and GC compacts the table down
+// to the nodes reachable from live roots. Long-running services that create
+// and discard many intermediate diagrams call GC periodically to keep memory
+// flat.
+//
+// Family operations whose operands all belong to the manager, such as Union,
+// Intersect or Compose, run directly in the shared table and return ZDDs
+// registered with the manager. Their intermediate results are memoized in the
+// table's operation cache and reused by later operations; see OpCacheStats.
+//
+// Registration, release and collection are safe for concurrent use. GC
+// renumbers nodes, so it must not run while managed ZDDs are being built,
+// evaluated or traversed, and NodeIDs obtained before a collection are
+// invalid afterwards.
+type Manager struct {
+	mu sync.Mutex
+
+	// nodes is the shared table holding every managed diagram
+	nodes *NodeTable
+
+	// live holds the registered ZDDs whose roots keep nodes alive
+	live map[*ZDD]struct{}
+}
+
+// NewManager creates a manager with an empty shared node table.
+func NewManager() *Manager {
+	return &Manager{
+		nodes: NewNodeTable(),
+		live:  make(map[*ZDD]struct{}),
+	}
+}
+
+// NewZDD creates an empty ZDD registered with the manager.
+//
+// Building it stores its nodes directly in the shared table, where they are
+// shared with every other diagram of the manager.
+func (m *Manager) NewZDD(vars int, opts ...Option) *ZDD {
+	z := NewZDD(vars, opts...)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	z.nodes = m.nodes
+	z.manager = m
+	m.live[z] = struct{}{}
+	return z
+}
+
+// table returns the current shared node table
+func (m *Manager) table() *NodeTable {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nodes
+}
+
+// Register adds z to the manager as a live root.
+//
+// The diagram of z is moved into the shared node table node for node, so a
+// quasi-reduced diagram stays quasi-reduced, after which z's root refers to
+// shared nodes. Registering a ZDD that is already registered with m
+// has no effect; a ZDD can belong to at most one manager. Building a
+// registered ZDD stores the result in the shared table as well.
+func (m *Manager) Register(z *ZDD) error {
+	if z == nil {
+		return fmt.Errorf("%w: ZDD is nil", ErrInvalidNode)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if z.manager == m {
+		return nil
+	}
+	if z.manager != nil {
+		return fmt.Errorf("%w: ZDD is registered with another manager", ErrInvalidNode)
+	}
+
+	root, err := m.adopt(z.nodes, z.root)
+	if err != nil {
+		return fmt.Errorf("register failed: %w", err)
+	}
+
+	z.root = root
+	z.nodes = m.nodes
+	z.manager = m
+	m.live[z] = struct{}{}
+	return nil
+}
+
+// adopt copies the diagram rooted at root from src into the shared table.
+// The caller must hold m.mu.
+func (m *Manager) adopt(src *NodeTable, root NodeID) (NodeID, error) {
+	if root == NullNode || src == m.nodes {
+		return root, nil
+	}
+
+	ops := newFamilyOps(context.Background(), m.nodes)
+	return ops.keepNode(src, root, make(map[NodeID]NodeID))
+}
+
+// release removes z from the live roots
+func (m *Manager) release(z *ZDD) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.live, z)
+}
+
+// GC removes every node that is not reachable from a live root.
+//
+// Live diagrams are copied node for node into a fresh table, which replaces
+// the shared table; registered ZDDs are updated to their new roots and keep
+// their form, reduced or quasi-reduced. Returns the number of
+// nodes that were freed.
+func (m *Manager) GC() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old := m.nodes
+	fresh := NewNodeTable()
+	ops := newFamilyOps(context.Background(), fresh)
+
+	// a shared memo keeps sub-diagrams shared between roots shared
+	memo := make(map[NodeID]NodeID)
+	for z := range m.live {
+		if z.root != NullNode {
+			// every node reachable from a live root is valid, so the copy cannot fail
+			z.root, _ = ops.keepNode(old, z.root, memo)
+		}
+		z.nodes = fresh
+	}
+
+	// annotations follow their nodes; those of collected nodes are dropped
+	for z := range m.live {
+		z.notes.remap(memo)
+	}
+
+	m.nodes = fresh
+	return old.Size() - fresh.Size()
+}
+
+// Size returns the number of nodes in the shared table, including nodes of
+// released diagrams that have not been collected yet.
+func (m *Manager) Size() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nodes.Size()
+}
+
+// Live returns the number of registered ZDDs that have not been released.
+func (m *Manager) Live() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.live)
+}
+
+// Release discards the diagram of z.
+//
+// A managed ZDD is removed from its manager's live roots, so the next GC can
+// reclaim its nodes. An unmanaged ZDD drops its private node table. In both
+// cases z is left empty, as if newly created, and may be built again.
+func (z *ZDD) Release() {
+	if z.manager != nil {
+		z.manager.release(z)
+		z.manager = nil
+	}
+
+	z.root = NullNode
+	z.nodes = NewNodeTable()
+	z.notes = annotations{}
+}
+
+// SharingReport describes how much structure the live diagrams of a manager share.
+type SharingReport struct {
+	// Roots is the number of live ZDDs
+	Roots int
+
+	// TableNodes is the size of the shared table, including uncollected nodes
+	TableNodes int
+
+	// LiveNodes is the number of distinct nodes reachable from live roots,
+	// including terminals
+	LiveNodes int
+
+	// SeparateNodes is the total size the live ZDDs would have in private tables
+	SeparateNodes int
+
+	// SharedNodes is the number of non-terminal nodes reachable from more than one root
+	SharedNodes int
+
+	// SavedNodes is SeparateNodes minus LiveNodes
+	SavedNodes int
+}
+
+// Sharing reports how much structure the live diagrams of m share.
+func (m *Manager) Sharing() SharingReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// owners counts the live roots each non-terminal node is reachable from
+	owners := make(map[NodeID]int)
+	report := SharingReport{Roots: len(m.live), TableNodes: m.nodes.Size()}
+
+	for z := range m.live {
+		seen := make(map[NodeID]bool)
+		stack := []NodeID{z.root}
+		for len(stack) > 0 {
+			id := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if id == NullNode || id == ZeroNode || id == OneNode || seen[id] {
+				continue
+			}
+			seen[id] = true
+			owners[id]++
+
+			node, err := m.nodes.GetNode(id)
+			if err != nil {
+				continue
+			}
+			stack = append(stack, node.Lo, node.Hi)
+		}
+		report.SeparateNodes += len(seen) + 2
+	}
+
+	for _, n := range owners {
+		if n > 1 {
+			report.SharedNodes++
+		}
+	}
+
+	report.LiveNodes = len(owners) + 2
+	report.SavedNodes = report.SeparateNodes - report.LiveNodes
+	return report
+}
@@ -0,0 +1,107 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// MapSolutions returns the family obtained by replacing every selected
+// variable by a set of variables.
+//
+// image is called once for each variable v in 1..Variables() and returns the
+// variables that v stands for. Each solution S becomes the union of the
+// images of its members, so a variable mapped to a group of variables expands
+// into the whole group, a variable mapped to nil is dropped, and images that
+// overlap merge. Solutions with equal images merge as well. The diagram is
+// rewritten node by node, so the cost depends on its size, not on the number
+// of solutions.
+//
+// The result has enough variables for the largest image and at least
+// Variables(). Registered groups are not carried over. Returns
+// ErrInvalidVariable if an image contains a variable below 1.
+func (z *ZDD) MapSolutions(ctx context.Context, image func(v int) []int) (*ZDD, error) {
+	if image == nil {
+		return nil, fmt.Errorf("%w: image function is nil", ErrInvalidConstraint)
+	}
+
+	images := make([][]int, z.vars+1)
+	vars := z.vars
+	for v := 1; v <= z.vars; v++ {
+		img := append([]int(nil), image(v)...)
+		sort.Ints(img)
+		for _, to := range img {
+			if to < 1 {
+				return nil, fmt.Errorf("%w: variable %d mapped to %d", ErrInvalidVariable, v, to)
+			}
+			vars = max(vars, to)
+		}
+		images[v] = img
+	}
+
+	ops, roots, err := workspace(ctx, z)
+	if err != nil {
+		return nil, err
+	}
+
+	// the image of each variable as a single-set family
+	chains := make([]NodeID, z.vars+1)
+	for v := 1; v <= z.vars; v++ {
+		chain := OneNode
+		for i, to := range images[v] {
+			if i > 0 && to == images[v][i-1] {
+				continue
+			}
+			chain = ops.nt.AddNode(to, ZeroNode, chain)
+		}
+		chains[v] = chain
+	}
+
+	root, err := ops.mapSolutions(roots[0], chains, make(map[NodeID]NodeID))
+	if err != nil {
+		return nil, fmt.Errorf("map solutions failed: %w", err)
+	}
+
+	result, err := z.derive(vars, ops.nt, root)
+	if err != nil {
+		return nil, err
+	}
+	result.groups = nil
+	return result, nil
+}
+
+// mapSolutions replaces each variable l of f by the single set chains[l].
+//
+// Each node becomes the union of its mapped Lo family and its mapped Hi
+// family joined with the image of its variable.
+func (o *familyOps) mapSolutions(f NodeID, chains []NodeID, memo map[NodeID]NodeID) (NodeID, error) {
+	if f == ZeroNode || f == OneNode {
+		return f, nil
+	}
+	if r, ok := memo[f]; ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	node := o.node(f)
+	lo, err := o.mapSolutions(node.Lo, chains, memo)
+	if err != nil {
+		return NullNode, err
+	}
+	hi, err := o.mapSolutions(node.Hi, chains, memo)
+	if err != nil {
+		return NullNode, err
+	}
+	if hi, err = o.join(hi, chains[node.Level]); err != nil {
+		return NullNode, err
+	}
+
+	r, err := o.union(lo, hi)
+	if err != nil {
+		return NullNode, err
+	}
+	memo[f] = r
+	return r, nil
+}
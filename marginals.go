@@ -0,0 +1,68 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+)
+
+// MarginalEvaluator computes, for each variable, the fraction of solutions
+// that select it.
+//
+// These marginal frequencies show which variables the constraints force in,
+// rule out or leave open, which helps explain a model. A backward pass counts
+// the solutions below every node, in the scaled floating point of
+// LogCountEvaluator, and a forward pass pushes the probability that a
+// uniformly random solution passes through each node down to its children,
+// so the evaluation takes O(Size()) time however many solutions there are.
+//
+// The result is a []float64 of length Variables()+1 whose index v holds the
+// fraction for variable v; index 0 is unused. All fractions are 0 when the
+// family is empty.
+type MarginalEvaluator struct{}
+
+// Evaluate computes the marginal frequency of every variable
+func (e MarginalEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
+	marginals := make([]float64, zdd.vars+1)
+
+	root := zdd.family()
+	counts, err := zdd.scaledCounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("marginal evaluation failed: %w", err)
+	}
+	if counts[root].m == 0 {
+		return marginals, nil
+	}
+
+	// parents have higher levels than their children, so visiting the levels
+	// from the top finishes every node's probability before its use
+	through := map[NodeID]float64{root: 1}
+	for l := zdd.vars; l >= 1; l-- {
+		for _, id := range zdd.layer(l) {
+			if err := ctx.Err(); err != nil {
+				return nil, fmt.Errorf("marginal evaluation failed: %w", err)
+			}
+			node, err := zdd.GetNode(id)
+			if err != nil {
+				return nil, err
+			}
+			p := through[id]
+			hi := p * counts[node.Hi].ratio(counts[id])
+			marginals[l] += hi
+			through[node.Hi] += hi
+			through[node.Lo] += p - hi
+		}
+	}
+	return marginals, nil
+}
+
+// Marginals returns, for each variable v, the fraction of solutions that
+// select it at index v.
+//
+// This is a type-safe convenience method for MarginalEvaluator.
+func (z *ZDD) Marginals(ctx context.Context) ([]float64, error) {
+	result, err := EvaluateZDD(ctx, z, MarginalEvaluator{})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]float64), nil
+}
@@ -0,0 +1,374 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// SoftConstraint is a constraint that may be violated at a cost.
+//
+// A soft constraint is violated by an assignment if its Validate rejects any
+// transition along the assignment, or if its CanPrune reports that it can no
+// longer be satisfied. StatefulConstraints receive their own state, as in
+// CompositeConstraintSpec; other constraints see the shared state of the hard
+// constraints after each transition.
+type SoftConstraint struct {
+	// Constraint defines when the soft constraint is satisfied
+	Constraint Constraint
+
+	// Weight is the penalty incurred when the constraint is violated
+	Weight float64
+
+	// Name identifies the constraint in reports
+	Name string
+}
+
+// MaxSATProblem describes an optimization over hard and soft constraints.
+type MaxSATProblem struct {
+	// Vars is the number of decision variables
+	Vars int
+
+	// InitialState is the starting state shared by the hard constraints
+	InitialState State
+
+	// Hard lists constraints every solution must satisfy
+	Hard []Constraint
+
+	// Soft lists weighted constraints whose violations are minimized
+	Soft []SoftConstraint
+}
+
+// MaxSATResult holds the outcome of SolveMaxSAT.
+type MaxSATResult struct {
+	// Best holds up to k assignments in order of increasing penalty.
+	// Solution.Cost is the total weight of violated soft constraints and
+	// Metadata["violated"] lists their indices as []int.
+	Best []*Solution
+
+	// Penalty is the optimal total weight of violated soft constraints
+	Penalty float64
+
+	// Optima is the family of all assignments achieving Penalty
+	Optima *ZDD
+}
+
+// SolveMaxSAT finds assignments that satisfy every hard constraint and
+// minimize the weighted sum of violated soft constraints.
+//
+// The solver builds a single ZDD over Vars+len(Soft) variables in which each
+// soft constraint gets an indicator variable below the decision variables that
+// is selected exactly when the constraint is violated. Minimizing the weights of
+// the indicators then gives the MaxSAT optimum, the k best assignments and the
+// family of all optimal assignments in one diagram.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout handling
+//   - problem: Hard and soft constraints over the decision variables
+//   - k: Number of best assignments to return in MaxSATResult.Best
+//   - opts: Configuration options applied to the build
+//
+// Returns ErrInfeasible if the hard constraints admit no solution.
+func SolveMaxSAT(ctx context.Context, problem MaxSATProblem, k int, opts ...Option) (*MaxSATResult, error) {
+	if problem.InitialState == nil {
+		return nil, fmt.Errorf("%w: initial state is nil", ErrInvalidConstraint)
+	}
+
+	spec := newMaxSATSpec(problem)
+	vars := spec.Variables()
+	soft := len(problem.Soft)
+
+	zdd := NewZDD(vars, opts...)
+	if err := zdd.Build(ctx, spec); err != nil {
+		return nil, fmt.Errorf("maxsat build failed: %w", err)
+	}
+	if zdd.family() == ZeroNode {
+		return nil, fmt.Errorf("%w: hard constraints", ErrInfeasible)
+	}
+
+	costs := make([]float64, vars+1)
+	for j, sc := range problem.Soft {
+		costs[j+1] = sc.Weight
+	}
+
+	// Evaluate directly so the decorator sees decision variables only
+	result, err := KBestEvaluator{K: k, Costs: costs}.Evaluate(ctx, zdd)
+	if err != nil {
+		return nil, err
+	}
+	best := result.(KBestResult).Solutions
+
+	for _, sol := range best {
+		decisions := make([]int, 0, len(sol.Variables))
+		violated := make([]int, 0)
+		for _, v := range sol.Variables {
+			if v <= soft {
+				violated = append(violated, v-1)
+			} else {
+				decisions = append(decisions, v-soft)
+			}
+		}
+		sol.Variables = decisions
+		sol.Metadata["violated"] = violated
+	}
+	zdd.decorate(best...)
+
+	optima, penalty, err := zdd.optimalFamily(ctx, costs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Drop the indicator variables and shift decisions back to 1..Vars
+	ops, roots, err := workspace(ctx, optima)
+	if err != nil {
+		return nil, err
+	}
+	keep := make([]bool, vars+1)
+	for l := soft + 1; l <= vars; l++ {
+		keep[l] = true
+	}
+	projected, err := ops.project(roots[0], keep, make(map[NodeID]NodeID))
+	if err != nil {
+		return nil, err
+	}
+	shifted, err := ops.shift(ops.nt, projected, -soft, OneNode, make(map[NodeID]NodeID))
+	if err != nil {
+		return nil, err
+	}
+
+	family, err := zdd.derive(problem.Vars, ops.nt, shifted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MaxSATResult{Best: best, Penalty: penalty, Optima: family}, nil
+}
+
+// minCosts returns the minimum cost of reaching the 1-terminal from every
+// reachable node, +Inf where no completion exists
+func (z *ZDD) minCosts(ctx context.Context, costs []float64) (map[NodeID]float64, error) {
+	best := map[NodeID]float64{ZeroNode: math.Inf(1), OneNode: 0}
+	err := z.bottomUp(ctx, func(id NodeID, node Node) error {
+		best[id] = math.Min(best[node.Lo], best[node.Hi]+costs[node.Level])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return best, nil
+}
+
+// optimalFamily returns the sub-family of minimum-cost sets and their cost
+func (z *ZDD) optimalFamily(ctx context.Context, costs []float64) (*ZDD, float64, error) {
+	best, err := z.minCosts(ctx, costs)
+	if err != nil {
+		return nil, 0, err
+	}
+	root := z.family()
+	optimum := best[root]
+
+	// keep the arcs through which a node attains its minimum; nodes off the
+	// optimal paths are built too, but derive copies only what root reaches
+	ops := newFamilyOps(ctx, NewNodeTable())
+	kept := map[NodeID]NodeID{ZeroNode: ZeroNode, OneNode: OneNode}
+	err = z.bottomUp(ctx, func(id NodeID, node Node) error {
+		lo, hi := ZeroNode, ZeroNode
+		if best[node.Lo] == best[id] {
+			lo = kept[node.Lo]
+		}
+		if best[node.Hi]+costs[node.Level] == best[id] {
+			hi = kept[node.Hi]
+		}
+		kept[id] = ops.nt.AddNode(node.Level, lo, hi)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	family, err := z.derive(z.vars, ops.nt, kept[root])
+	if err != nil {
+		return nil, 0, err
+	}
+	return family, optimum, nil
+}
+
+// maxsatSpec tracks hard constraints, soft constraint states and violations.
+//
+// Levels 1..len(soft) are violation indicators; levels above are the decision
+// variables shifted up by len(soft).
+type maxsatSpec struct {
+	hard *CompositeConstraintSpec
+	soft []SoftConstraint
+	vars int
+}
+
+// newMaxSATSpec creates the augmented spec for a MaxSAT problem
+func newMaxSATSpec(problem MaxSATProblem) *maxsatSpec {
+	return &maxsatSpec{
+		hard: NewCompositeSpec(problem.Vars, problem.InitialState, problem.Hard...),
+		soft: problem.Soft,
+		vars: problem.Vars,
+	}
+}
+
+// maxsatState combines the hard state with soft constraint progress
+type maxsatState struct {
+	// base is the hard constraint state, nil once it has been validated
+	base     State
+	soft     []State
+	violated []bool
+}
+
+// Clone creates a deep copy of the maxsatState
+func (s *maxsatState) Clone() State {
+	c := &maxsatState{
+		soft:     make([]State, len(s.soft)),
+		violated: make([]bool, len(s.violated)),
+	}
+	if s.base != nil {
+		c.base = s.base.Clone()
+	}
+	for i, st := range s.soft {
+		if st != nil {
+			c.soft[i] = st.Clone()
+		}
+	}
+	copy(c.violated, s.violated)
+	return c
+}
+
+// Hash combines base, soft and violation hashes
+func (s *maxsatState) Hash() uint64 {
+	hash := uint64(14695981039346656037)
+	if s.base != nil {
+		hash ^= s.base.Hash()
+	}
+	for i, st := range s.soft {
+		hash *= 1099511628211
+		if s.violated[i] {
+			hash ^= 1
+		} else if st != nil {
+			hash ^= st.Hash()
+		}
+	}
+	return hash
+}
+
+// Equal checks equality with another maxsatState
+func (s *maxsatState) Equal(other State) bool {
+	o, ok := other.(*maxsatState)
+	if !ok || len(s.violated) != len(o.violated) {
+		return false
+	}
+	if (s.base == nil) != (o.base == nil) || (s.base != nil && !s.base.Equal(o.base)) {
+		return false
+	}
+	for i := range s.violated {
+		if s.violated[i] != o.violated[i] {
+			return false
+		}
+		if s.violated[i] || s.soft[i] == nil {
+			continue
+		}
+		if o.soft[i] == nil || !s.soft[i].Equal(o.soft[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Variables returns the decision variables plus one indicator per soft constraint
+func (m *maxsatSpec) Variables() int {
+	return m.vars + len(m.soft)
+}
+
+// InitialState returns the hard initial state with all soft constraints satisfied
+func (m *maxsatSpec) InitialState() State {
+	s := &maxsatState{
+		base:     m.hard.InitialState(),
+		soft:     make([]State, len(m.soft)),
+		violated: make([]bool, len(m.soft)),
+	}
+	for i, sc := range m.soft {
+		if stateful, ok := sc.Constraint.(StatefulConstraint); ok {
+			s.soft[i] = stateful.InitialState()
+		}
+	}
+	return s
+}
+
+// GetChild advances hard and soft constraints, or checks violation indicators
+func (m *maxsatSpec) GetChild(ctx context.Context, state State, level int, take bool) (State, error) {
+	s := state.(*maxsatState)
+	indicators := len(m.soft)
+
+	if level <= indicators {
+		if take != s.violated[level-1] {
+			return nil, fmt.Errorf("indicator %d does not match violation", level-1)
+		}
+		return s, nil
+	}
+
+	decision := level - indicators
+	base, err := m.hard.GetChild(ctx, s.base, decision, take)
+	if err != nil {
+		return nil, err
+	}
+
+	next := &maxsatState{
+		base:     base,
+		soft:     make([]State, len(s.soft)),
+		violated: make([]bool, len(s.violated)),
+	}
+	copy(next.violated, s.violated)
+	shared := compositeShared(base)
+
+	for i, sc := range m.soft {
+		if next.violated[i] {
+			continue
+		}
+
+		if stateful, ok := sc.Constraint.(StatefulConstraint); ok {
+			if stateful.Validate(ctx, s.soft[i], decision, take) != nil {
+				next.violated[i] = true
+				continue
+			}
+			next.soft[i] = stateful.Apply(s.soft[i], decision, take)
+			next.violated[i] = stateful.CanPrune(next.soft[i], decision-1)
+			continue
+		}
+
+		if sc.Constraint.Validate(ctx, shared, decision, take) != nil || sc.Constraint.CanPrune(shared, decision-1) {
+			next.violated[i] = true
+		}
+	}
+
+	// Leaving the decision variables: validate the hard state and drop it
+	if decision == 1 {
+		if !m.hard.IsValid(next.base) {
+			return nil, fmt.Errorf("hard constraints not satisfied")
+		}
+		next.base = nil
+		next.soft = make([]State, len(next.soft))
+	}
+
+	return next, nil
+}
+
+// IsValid accepts states whose hard constraints were already validated
+func (m *maxsatSpec) IsValid(state State) bool {
+	s := state.(*maxsatState)
+	return s.base == nil || m.hard.IsValid(s.base)
+}
+
+// compositeShared unwraps the shared state of a composite spec state
+func compositeShared(state State) State {
+	if s, ok := state.(*symmetryState); ok {
+		state = s.inner
+	}
+	if cs, ok := state.(*compositeState); ok {
+		return cs.shared
+	}
+	return state
+}
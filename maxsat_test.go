@@ -0,0 +1,183 @@
+package gozdd_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/zzenonn/go-zdd"
+)
+
+// selects returns a constraint that is violated unless v is selected (or,
+// with want false, left unselected)
+func selects(v int, want bool) gozdd.Constraint {
+	return gozdd.CustomConstraint{
+		ValidateFunc: func(ctx context.Context, state gozdd.State, level int, take bool) error {
+			if level == v && take != want {
+				return fmt.Errorf("variable %d", v)
+			}
+			return nil
+		},
+	}
+}
+
+// maxsatInstance has ties at the optimum and among the runners-up
+func maxsatInstance() (gozdd.MaxSATProblem, []func(set []int) bool) {
+	weights := []float64{0, 2, 1, 1, 1, 1}
+	problem := gozdd.MaxSATProblem{
+		Vars:         5,
+		InitialState: gozdd.BasicState{},
+		Hard:         []gozdd.Constraint{gozdd.CountConstraint{Min: 1, Max: 3}},
+		Soft: []gozdd.SoftConstraint{
+			{Constraint: gozdd.SumConstraint{Weights: weights, Max: 4}, Weight: 2, Name: "budget"},
+			{Constraint: selects(2, true), Weight: 1, Name: "want 2"},
+			{Constraint: gozdd.CountConstraint{Min: 3, Max: 5}, Weight: 1, Name: "at least 3"},
+			{Constraint: selects(5, false), Weight: 1, Name: "avoid 5"},
+			{Constraint: selects(1, true), Weight: 1, Name: "want 1"},
+		},
+	}
+
+	// violated[j] reports whether set violates soft constraint j
+	violated := []func(set []int) bool{
+		func(set []int) bool {
+			sum := 0.0
+			for _, v := range set {
+				sum += weights[v]
+			}
+			return sum > 4
+		},
+		func(set []int) bool { return !contains(set, 2) },
+		func(set []int) bool { return len(set) < 3 },
+		func(set []int) bool { return contains(set, 5) },
+		func(set []int) bool { return !contains(set, 1) },
+	}
+	return problem, violated
+}
+
+func contains(set []int, v int) bool {
+	for _, x := range set {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// bruteMaxSAT returns the penalty of every assignment satisfying the hard
+// constraint, keyed by the assignment
+func bruteMaxSAT(problem gozdd.MaxSATProblem, violated []func([]int) bool) map[string]float64 {
+	penalties := make(map[string]float64)
+	for mask := 0; mask < 1<<problem.Vars; mask++ {
+		var set []int
+		for v := 1; v <= problem.Vars; v++ {
+			if mask&(1<<(v-1)) != 0 {
+				set = append(set, v)
+			}
+		}
+		if len(set) < 1 || len(set) > 3 {
+			continue
+		}
+		penalty := 0.0
+		for j, v := range violated {
+			if v(set) {
+				penalty += problem.Soft[j].Weight
+			}
+		}
+		penalties[fmt.Sprint(set)] = penalty
+	}
+	return penalties
+}
+
+func sortedSet(vars []int) []int {
+	set := append([]int{}, vars...)
+	sort.Ints(set)
+	return set
+}
+
+func TestSolveMaxSATMatchesBruteForce(t *testing.T) {
+	ctx := context.Background()
+	problem, violated := maxsatInstance()
+	penalties := bruteMaxSAT(problem, violated)
+
+	optimum := -1.0
+	var optimal []string
+	var all []float64
+	for set, p := range penalties {
+		all = append(all, p)
+		if optimum < 0 || p < optimum {
+			optimum, optimal = p, nil
+		}
+		if p == optimum {
+			optimal = append(optimal, set)
+		}
+	}
+	sort.Strings(optimal)
+	sort.Float64s(all)
+	if len(optimal) < 2 {
+		t.Fatalf("instance should have tied optima, has %v", optimal)
+	}
+
+	for _, k := range []int{1, 3, 10, len(penalties), len(penalties) + 5} {
+		result, err := gozdd.SolveMaxSAT(ctx, problem, k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Penalty != optimum {
+			t.Fatalf("k=%d: penalty %v, want %v", k, result.Penalty, optimum)
+		}
+
+		var optima []string
+		for s := range result.Optima.All(ctx) {
+			optima = append(optima, fmt.Sprint(sortedSet(s.Variables)))
+		}
+		sort.Strings(optima)
+		if !reflect.DeepEqual(optima, optimal) {
+			t.Fatalf("k=%d: optima %v, want %v", k, optima, optimal)
+		}
+
+		if want := min(k, len(penalties)); len(result.Best) != want {
+			t.Fatalf("k=%d: %d best assignments, want %d", k, len(result.Best), want)
+		}
+		seen := make(map[string]bool)
+		for i, s := range result.Best {
+			set := sortedSet(s.Variables)
+			key := fmt.Sprint(set)
+			if seen[key] {
+				t.Fatalf("k=%d: assignment %v returned twice", k, set)
+			}
+			seen[key] = true
+
+			penalty, ok := penalties[key]
+			if !ok {
+				t.Fatalf("k=%d: %v violates the hard constraint", k, set)
+			}
+			if s.Cost != penalty || s.Cost != all[i] {
+				t.Fatalf("k=%d: best[%d] = %v costs %v, brute force %v, want rank cost %v", k, i, set, s.Cost, penalty, all[i])
+			}
+
+			var want []int
+			for j, v := range violated {
+				if v(set) {
+					want = append(want, j)
+				}
+			}
+			got := s.Metadata["violated"].([]int)
+			if len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(sortedSet(got), want)) {
+				t.Fatalf("k=%d: %v violates %v, want %v", k, set, got, want)
+			}
+		}
+	}
+}
+
+func TestSolveMaxSATInfeasible(t *testing.T) {
+	problem, _ := maxsatInstance()
+	problem.Hard = append(problem.Hard, gozdd.CountConstraint{Min: 4, Max: 5})
+
+	_, err := gozdd.SolveMaxSAT(context.Background(), problem, 1)
+	if !errors.Is(err, gozdd.ErrInfeasible) {
+		t.Fatalf("got %v, want ErrInfeasible", err)
+	}
+}
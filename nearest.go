@@ -0,0 +1,46 @@
+package gozdd
+
+import "context"
+
+// Nearest returns a solution at minimum Hamming distance from target, the
+// solution that differs from target in the fewest variables.
+//
+// target may be any set of variables, feasible or not, so this repairs a
+// plan with as few changes as possible. Distance is a cost of 1 for every
+// variable whose decision differs from target, including variables skipped
+// by zero suppression, so the query is a single shortest-path pass over the
+// diagram. The solution's Cost is its distance; among equally near solutions
+// the first in the order documented at WithStableOrder is returned.
+//
+// Returns ErrInvalidVariable for target variables outside 1..Variables() and
+// ErrInfeasible if the ZDD has no solutions.
+func (z *ZDD) Nearest(ctx context.Context, target []int) (*Solution, error) {
+	solutions, err := z.NearestK(ctx, 1, target)
+	if err != nil {
+		return nil, err
+	}
+	if len(solutions) == 0 {
+		return nil, ErrInfeasible
+	}
+	return solutions[0], nil
+}
+
+// NearestK returns the k solutions nearest to target by Hamming distance, in
+// ascending distance.
+//
+// It is Nearest for several candidates, such as alternative repairs of an
+// infeasible plan; each solution's Cost is its distance. Returns
+// ErrInvalidVariable for target variables outside 1..Variables().
+func (z *ZDD) NearestK(ctx context.Context, k int, target []int) ([]*Solution, error) {
+	costs := make(CostMatrix, z.vars+1)
+	for v := 1; v <= z.vars; v++ {
+		costs[v] = [2]float64{0, 1}
+	}
+	for _, v := range target {
+		if err := z.checkVariable(v); err != nil {
+			return nil, err
+		}
+		costs[v] = [2]float64{1, 0}
+	}
+	return z.FindKBestMatrix(ctx, k, costs)
+}
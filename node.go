@@ -64,8 +64,11 @@ type NodeTable struct {
 	hashTable []hashEntry
 	hashMask   uint32 // Always power of 2 minus 1
 	
-	// State memoization for TdZdd-style construction
-	stateCache map[uint64]NodeID // hash(state,level) -> NodeID
+	// used counts the occupied hash table entries
+	used int
+	
+	// ops memoizes family operation results over this table
+	ops opCache
 	
 	next NodeID
 }
@@ -81,11 +84,10 @@ type hashEntry struct {
 func NewNodeTable() *NodeTable {
 	initialSize := uint32(1024) // Start with 1K entries
 	nt := &NodeTable{
-		nodes:      make([]Node, 3),
-		hashTable:  make([]hashEntry, initialSize),
-		hashMask:   initialSize - 1,
-		stateCache: make(map[uint64]NodeID),
-		next:       3,
+		nodes:     make([]Node, 3),
+		hashTable: make([]hashEntry, initialSize),
+		hashMask:  initialSize - 1,
+		next:      3,
 	}
 	
 	// Initialize terminal nodes
@@ -118,7 +120,12 @@ func (nt *NodeTable) AddNode(level int, lo, hi NodeID) NodeID {
 	if hi == ZeroNode {
 		return lo
 	}
-	
+	return nt.insert(level, lo, hi)
+}
+
+// insert returns the unique node with the given level and arcs, without
+// applying any reduction rule
+func (nt *NodeTable) insert(level int, lo, hi NodeID) NodeID {
 	node := Node{Level: level, Lo: lo, Hi: hi}
 	
 	nt.mu.Lock()
@@ -144,6 +151,17 @@ func (nt *NodeTable) AddNode(level int, lo, hi NodeID) NodeID {
 	return id
 }
 
+// powerSet returns the node representing every subset of variables 1..level.
+//
+// Each level gets a don't-care node whose Lo and Hi arcs lead to the same child.
+func (nt *NodeTable) powerSet(level int) NodeID {
+	node := OneNode
+	for l := 1; l <= level; l++ {
+		node = nt.AddNode(l, node, node)
+	}
+	return node
+}
+
 // findNode searches for an existing node using open addressing
 func (nt *NodeTable) findNode(node Node) NodeID {
 	hash := nt.hashNode(node)
@@ -165,7 +183,7 @@ func (nt *NodeTable) findNode(node Node) NodeID {
 // insertNode adds a node to the hash table, resizing if needed
 func (nt *NodeTable) insertNode(node Node, id NodeID) {
 	// Resize if load factor > 0.75
-	if nt.countUsed() > len(nt.hashTable)*3/4 {
+	if nt.used > len(nt.hashTable)*3/4 {
 		nt.resizeHashTable()
 	}
 	
@@ -178,6 +196,7 @@ func (nt *NodeTable) insertNode(node Node, id NodeID) {
 			entry.node = node
 			entry.id = id
 			entry.used = true
+			nt.used++
 			return
 		}
 	}
@@ -196,17 +215,6 @@ func (nt *NodeTable) nodesEqual(a, b Node) bool {
 	return a.Level == b.Level && a.Lo == b.Lo && a.Hi == b.Hi
 }
 
-// countUsed counts used entries in hash table
-func (nt *NodeTable) countUsed() int {
-	count := 0
-	for i := range nt.hashTable {
-		if nt.hashTable[i].used {
-			count++
-		}
-	}
-	return count
-}
-
 // resizeHashTable doubles the hash table size
 func (nt *NodeTable) resizeHashTable() {
 	oldTable := nt.hashTable
@@ -214,6 +222,7 @@ func (nt *NodeTable) resizeHashTable() {
 	
 	nt.hashTable = make([]hashEntry, newSize)
 	nt.hashMask = newSize - 1
+	nt.used = 0
 	
 	// Rehash all entries
 	for i := range oldTable {
@@ -223,34 +232,54 @@ func (nt *NodeTable) resizeHashTable() {
 	}
 }
 
-// LookupState checks if a state at a given level has been computed before.
-// Returns the cached NodeID if found, NullNode otherwise.
-func (nt *NodeTable) LookupState(state State, level int) NodeID {
-	nt.mu.RLock()
-	defer nt.mu.RUnlock()
+// buildStates memoizes the node built for each state during a single Build.
+//
+// Entries are compared with Equal, so hash collisions between different
+// states never merge their sub-diagrams, and several builds can share one
+// node table without seeing each other's states.
+type buildStates struct {
+	mu      sync.Mutex
+	entries map[stateSlot][]stateEntry
+}
+
+// stateSlot groups states by level and hash, so states that recur on many
+// levels do not share one long bucket
+type stateSlot struct {
+	level int
+	hash  uint64
+}
+
+// stateEntry records the node built for a state at a level
+type stateEntry struct {
+	state State
+	node  NodeID
+}
+
+// newBuildStates creates an empty per-build state memo
+func newBuildStates() *buildStates {
+	return &buildStates{entries: make(map[stateSlot][]stateEntry)}
+}
+
+// lookup returns the node built for state at level, or NullNode
+func (b *buildStates) lookup(state State, level int) NodeID {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	
-	key := nt.stateKey(state, level)
-	if nodeID, exists := nt.stateCache[key]; exists {
-		return nodeID
+	for _, e := range b.entries[stateSlot{level, state.Hash()}] {
+		if e.state.Equal(state) {
+			return e.node
+		}
 	}
 	return NullNode
 }
 
-// CacheState stores the result of computing a state at a given level.
-func (nt *NodeTable) CacheState(state State, level int, nodeID NodeID) {
-	nt.mu.Lock()
-	defer nt.mu.Unlock()
+// store records the node built for state at level
+func (b *buildStates) store(state State, level int, node NodeID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	
-	key := nt.stateKey(state, level)
-	nt.stateCache[key] = nodeID
-}
-
-// stateKey computes a unique key for state memoization
-func (nt *NodeTable) stateKey(state State, level int) uint64 {
-	// Combine state hash with level using bit manipulation
-	stateHash := state.Hash()
-	levelHash := uint64(level) << 32
-	return stateHash ^ levelHash
+	slot := stateSlot{level, state.Hash()}
+	b.entries[slot] = append(b.entries[slot], stateEntry{state: state, node: node})
 }
 
 // Size returns the total number of nodes in the table, excluding NullNode.
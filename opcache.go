@@ -0,0 +1,73 @@
+package gozdd
+
+import "sync"
+
+// opCache memoizes family operation results for one node table.
+//
+// Node IDs are never reused within a table, so a result stays valid for the
+// table's lifetime and can be shared by every operation on it, like the
+// computed table of a BDD package. Without it, operations on diagrams with
+// heavy sharing would revisit the same pair of sub-diagrams exponentially
+// often. The zero value is an empty cache.
+type opCache struct {
+	mu      sync.Mutex
+	entries map[opKey]NodeID
+	hits    int64
+	misses  int64
+}
+
+// lookup returns the cached result for key
+func (c *opCache) lookup(key opKey) (NodeID, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r, ok := c.entries[key]
+	if ok {
+		c.hits++
+	} else {
+		c.misses++
+	}
+	return r, ok
+}
+
+// store records the result for key
+func (c *opCache) store(key opKey, r NodeID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[opKey]NodeID)
+	}
+	c.entries[key] = r
+}
+
+// stats returns the hit and miss counts and the number of cached results
+func (c *opCache) stats() (hits, misses int64, entries int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses, len(c.entries)
+}
+
+// clear drops every cached result and resets the counters
+func (c *opCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = nil
+	c.hits, c.misses = 0, 0
+}
+
+// OpCacheStats reports the operation cache of the shared table.
+//
+// Family operations on ZDDs of this manager, such as Union or Intersect, run
+// directly in the shared table and memoize their intermediate results there,
+// so repeated or overlapping operations reuse earlier work. Returns the number
+// of cache hits and misses and the number of cached results.
+func (m *Manager) OpCacheStats() (hits, misses int64, entries int) {
+	return m.table().ops.stats()
+}
+
+// ClearOpCache drops the cached operation results of the shared table to
+// release their memory. GC clears the cache as well, since it renumbers nodes.
+func (m *Manager) ClearOpCache() {
+	m.table().ops.clear()
+}
@@ -0,0 +1,946 @@
+package gozdd
+
+import (
+	"context"
+)
+
+// opKind identifies a family operation for memoization
+type opKind uint8
+
+const (
+	opSubset0 opKind = iota
+	opSubset1
+	opIntersect
+	opUnion
+	opJoin
+	opDifference
+	opMeet
+	opDivide
+	opNonSubsets
+	opNonSupersets
+	opMaximal
+	opMinimal
+	opChange
+	opDownClosure
+	opUpClosure
+)
+
+// opKey identifies a memoized operation result
+type opKey struct {
+	op opKind
+	f  NodeID
+	g  NodeID
+	v  int
+}
+
+// familyOps evaluates family algebra operations within a single node table.
+//
+// All operand and result NodeIDs refer to nodes in nt. Operands that live in
+// other tables must be imported first. Results are memoized in the table's
+// operation cache, so they are shared with every other operation on nt.
+type familyOps struct {
+	ctx context.Context
+	nt  *NodeTable
+}
+
+// newFamilyOps creates an operation context over the given node table
+func newFamilyOps(ctx context.Context, nt *NodeTable) *familyOps {
+	return &familyOps{ctx: ctx, nt: nt}
+}
+
+// node returns the node for id, which must exist in the table
+func (o *familyOps) node(id NodeID) Node {
+	o.nt.mu.RLock()
+	defer o.nt.mu.RUnlock()
+	return o.nt.nodes[id]
+}
+
+// level returns the variable level of a node, 0 for terminals
+func (o *familyOps) level(id NodeID) int {
+	return o.node(id).Level
+}
+
+// checkCancel returns the context error if the operation was cancelled
+func (o *familyOps) checkCancel() error {
+	select {
+	case <-o.ctx.Done():
+		return o.ctx.Err()
+	default:
+		return nil
+	}
+}
+
+// importNode copies the sub-diagram rooted at id from src into this table,
+// reducing it: nodes whose Hi arc leads to the 0-terminal, which
+// quasi-reduced diagrams keep, are dropped, as the operations expect.
+func (o *familyOps) importNode(src *NodeTable, id NodeID, memo map[NodeID]NodeID) (NodeID, error) {
+	return o.copyNodes(src, id, memo, o.nt.AddNode)
+}
+
+// keepNode copies the sub-diagram rooted at id from src into this table
+// node for node, so a quasi-reduced diagram stays quasi-reduced
+func (o *familyOps) keepNode(src *NodeTable, id NodeID, memo map[NodeID]NodeID) (NodeID, error) {
+	return o.copyNodes(src, id, memo, o.nt.insert)
+}
+
+// copyNodes copies the sub-diagram rooted at id from src into this table,
+// creating each node with add.
+//
+// Nodes are copied children first from an explicit stack, so deep diagrams
+// need no recursion.
+func (o *familyOps) copyNodes(src *NodeTable, id NodeID, memo map[NodeID]NodeID, add func(level int, lo, hi NodeID) NodeID) (NodeID, error) {
+	mapped := func(id NodeID) (NodeID, bool) {
+		if id == NullNode || id == ZeroNode || id == OneNode {
+			return id, true
+		}
+		m, ok := memo[id]
+		return m, ok
+	}
+
+	stack := []NodeID{id}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		if _, ok := mapped(top); ok {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		node, err := src.GetNode(top)
+		if err != nil {
+			return NullNode, err
+		}
+		lo, loOK := mapped(node.Lo)
+		hi, hiOK := mapped(node.Hi)
+		switch {
+		case !loOK:
+			stack = append(stack, node.Lo)
+		case !hiOK:
+			stack = append(stack, node.Hi)
+		default:
+			memo[top] = add(node.Level, lo, hi)
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	root, _ := mapped(id)
+	return root, nil
+}
+
+// subset0 returns the sets of f that do not contain variable v
+func (o *familyOps) subset0(f NodeID, v int) (NodeID, error) {
+	node := o.node(f)
+	if node.Level < v {
+		return f, nil
+	}
+	if node.Level == v {
+		return node.Lo, nil
+	}
+
+	key := opKey{op: opSubset0, f: f, v: v}
+	if r, ok := o.nt.ops.lookup(key); ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	lo, err := o.subset0(node.Lo, v)
+	if err != nil {
+		return NullNode, err
+	}
+	hi, err := o.subset0(node.Hi, v)
+	if err != nil {
+		return NullNode, err
+	}
+
+	r := o.nt.AddNode(node.Level, lo, hi)
+	o.nt.ops.store(key, r)
+	return r, nil
+}
+
+// subset1 returns the sets of f that contain variable v, with v removed
+func (o *familyOps) subset1(f NodeID, v int) (NodeID, error) {
+	node := o.node(f)
+	if node.Level < v {
+		return ZeroNode, nil
+	}
+	if node.Level == v {
+		return node.Hi, nil
+	}
+
+	key := opKey{op: opSubset1, f: f, v: v}
+	if r, ok := o.nt.ops.lookup(key); ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	lo, err := o.subset1(node.Lo, v)
+	if err != nil {
+		return NullNode, err
+	}
+	hi, err := o.subset1(node.Hi, v)
+	if err != nil {
+		return NullNode, err
+	}
+
+	r := o.nt.AddNode(node.Level, lo, hi)
+	o.nt.ops.store(key, r)
+	return r, nil
+}
+
+// change toggles variable v in every set of f
+func (o *familyOps) change(f NodeID, v int) (NodeID, error) {
+	if f == ZeroNode {
+		return f, nil
+	}
+	node := o.node(f)
+	if node.Level < v {
+		return o.nt.AddNode(v, ZeroNode, f), nil
+	}
+	if node.Level == v {
+		return o.nt.AddNode(v, node.Hi, node.Lo), nil
+	}
+
+	key := opKey{op: opChange, f: f, v: v}
+	if r, ok := o.nt.ops.lookup(key); ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	lo, err := o.change(node.Lo, v)
+	if err != nil {
+		return NullNode, err
+	}
+	hi, err := o.change(node.Hi, v)
+	if err != nil {
+		return NullNode, err
+	}
+
+	r := o.nt.AddNode(node.Level, lo, hi)
+	o.nt.ops.store(key, r)
+	return r, nil
+}
+
+// intersect returns the sets contained in both f and g
+func (o *familyOps) intersect(f, g NodeID) (NodeID, error) {
+	if f == ZeroNode || g == ZeroNode {
+		return ZeroNode, nil
+	}
+	if f == g {
+		return f, nil
+	}
+	if f > g {
+		f, g = g, f
+	}
+
+	key := opKey{op: opIntersect, f: f, g: g}
+	if r, ok := o.nt.ops.lookup(key); ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	fn, gn := o.node(f), o.node(g)
+	var r NodeID
+	var err error
+
+	switch {
+	case fn.Level > gn.Level:
+		r, err = o.intersect(fn.Lo, g)
+	case fn.Level < gn.Level:
+		r, err = o.intersect(f, gn.Lo)
+	default:
+		var lo, hi NodeID
+		if lo, err = o.intersect(fn.Lo, gn.Lo); err != nil {
+			return NullNode, err
+		}
+		if hi, err = o.intersect(fn.Hi, gn.Hi); err != nil {
+			return NullNode, err
+		}
+		r = o.nt.AddNode(fn.Level, lo, hi)
+	}
+	if err != nil {
+		return NullNode, err
+	}
+
+	o.nt.ops.store(key, r)
+	return r, nil
+}
+
+// union returns the sets contained in f or g
+func (o *familyOps) union(f, g NodeID) (NodeID, error) {
+	if f == ZeroNode {
+		return g, nil
+	}
+	if g == ZeroNode || f == g {
+		return f, nil
+	}
+	if f > g {
+		f, g = g, f
+	}
+
+	key := opKey{op: opUnion, f: f, g: g}
+	if r, ok := o.nt.ops.lookup(key); ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	fn, gn := o.node(f), o.node(g)
+	var lo, hi NodeID
+	var err error
+	level := fn.Level
+
+	switch {
+	case fn.Level > gn.Level:
+		if lo, err = o.union(fn.Lo, g); err != nil {
+			return NullNode, err
+		}
+		hi = fn.Hi
+	case fn.Level < gn.Level:
+		level = gn.Level
+		if lo, err = o.union(f, gn.Lo); err != nil {
+			return NullNode, err
+		}
+		hi = gn.Hi
+	default:
+		if lo, err = o.union(fn.Lo, gn.Lo); err != nil {
+			return NullNode, err
+		}
+		if hi, err = o.union(fn.Hi, gn.Hi); err != nil {
+			return NullNode, err
+		}
+	}
+
+	r := o.nt.AddNode(level, lo, hi)
+	o.nt.ops.store(key, r)
+	return r, nil
+}
+
+// difference returns the sets contained in f but not in g
+func (o *familyOps) difference(f, g NodeID) (NodeID, error) {
+	if f == ZeroNode || f == g {
+		return ZeroNode, nil
+	}
+	if g == ZeroNode {
+		return f, nil
+	}
+
+	key := opKey{op: opDifference, f: f, g: g}
+	if r, ok := o.nt.ops.lookup(key); ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	fn, gn := o.node(f), o.node(g)
+	var r NodeID
+	var err error
+
+	switch {
+	case fn.Level > gn.Level:
+		var lo NodeID
+		if lo, err = o.difference(fn.Lo, g); err != nil {
+			return NullNode, err
+		}
+		r = o.nt.AddNode(fn.Level, lo, fn.Hi)
+	case fn.Level < gn.Level:
+		r, err = o.difference(f, gn.Lo)
+	default:
+		var lo, hi NodeID
+		if lo, err = o.difference(fn.Lo, gn.Lo); err != nil {
+			return NullNode, err
+		}
+		if hi, err = o.difference(fn.Hi, gn.Hi); err != nil {
+			return NullNode, err
+		}
+		r = o.nt.AddNode(fn.Level, lo, hi)
+	}
+	if err != nil {
+		return NullNode, err
+	}
+
+	o.nt.ops.store(key, r)
+	return r, nil
+}
+
+// join returns every union of a set of f with a set of g
+func (o *familyOps) join(f, g NodeID) (NodeID, error) {
+	if f == ZeroNode || g == ZeroNode {
+		return ZeroNode, nil
+	}
+	if f == OneNode {
+		return g, nil
+	}
+	if g == OneNode {
+		return f, nil
+	}
+	if f > g {
+		f, g = g, f
+	}
+
+	key := opKey{op: opJoin, f: f, g: g}
+	if r, ok := o.nt.ops.lookup(key); ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	fn, gn := o.node(f), o.node(g)
+	if fn.Level < gn.Level {
+		f, g = g, f
+		fn, gn = gn, fn
+	}
+
+	var lo, hi NodeID
+	var err error
+
+	if fn.Level > gn.Level {
+		if lo, err = o.join(fn.Lo, g); err != nil {
+			return NullNode, err
+		}
+		if hi, err = o.join(fn.Hi, g); err != nil {
+			return NullNode, err
+		}
+	} else {
+		if lo, err = o.join(fn.Lo, gn.Lo); err != nil {
+			return NullNode, err
+		}
+
+		// the variable is selected if either side selects it
+		parts := [3][2]NodeID{{fn.Hi, gn.Hi}, {fn.Hi, gn.Lo}, {fn.Lo, gn.Hi}}
+		hi = ZeroNode
+		for _, p := range parts {
+			part, err := o.join(p[0], p[1])
+			if err != nil {
+				return NullNode, err
+			}
+			if hi, err = o.union(hi, part); err != nil {
+				return NullNode, err
+			}
+		}
+	}
+
+	r := o.nt.AddNode(fn.Level, lo, hi)
+	o.nt.ops.store(key, r)
+	return r, nil
+}
+
+// meet returns every intersection of a set of f with a set of g
+func (o *familyOps) meet(f, g NodeID) (NodeID, error) {
+	if f == ZeroNode || g == ZeroNode {
+		return ZeroNode, nil
+	}
+	if f == OneNode || g == OneNode {
+		return OneNode, nil
+	}
+	if f > g {
+		f, g = g, f
+	}
+
+	key := opKey{op: opMeet, f: f, g: g}
+	if r, ok := o.nt.ops.lookup(key); ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	fn, gn := o.node(f), o.node(g)
+	if fn.Level < gn.Level {
+		f, g = g, f
+		fn, gn = gn, fn
+	}
+
+	var r NodeID
+	var err error
+
+	if fn.Level > gn.Level {
+		// no set of g contains the variable, so both branches of f drop it
+		var lo, hi NodeID
+		if lo, err = o.meet(fn.Lo, g); err != nil {
+			return NullNode, err
+		}
+		if hi, err = o.meet(fn.Hi, g); err != nil {
+			return NullNode, err
+		}
+		if r, err = o.union(lo, hi); err != nil {
+			return NullNode, err
+		}
+	} else {
+		var hi NodeID
+		if hi, err = o.meet(fn.Hi, gn.Hi); err != nil {
+			return NullNode, err
+		}
+
+		// the variable is dropped unless both sides select it
+		parts := [3][2]NodeID{{fn.Lo, gn.Lo}, {fn.Hi, gn.Lo}, {fn.Lo, gn.Hi}}
+		lo := ZeroNode
+		for _, p := range parts {
+			part, err := o.meet(p[0], p[1])
+			if err != nil {
+				return NullNode, err
+			}
+			if lo, err = o.union(lo, part); err != nil {
+				return NullNode, err
+			}
+		}
+		r = o.nt.AddNode(fn.Level, lo, hi)
+	}
+
+	o.nt.ops.store(key, r)
+	return r, nil
+}
+
+// divide returns the weak quotient of f by g: the largest family Q whose sets
+// are disjoint from every set of g and whose join with g is contained in f.
+// g must not be ZeroNode.
+func (o *familyOps) divide(f, g NodeID) (NodeID, error) {
+	if g == OneNode {
+		return f, nil
+	}
+	if f == ZeroNode || f == OneNode {
+		return ZeroNode, nil
+	}
+	if f == g {
+		return OneNode, nil
+	}
+
+	key := opKey{op: opDivide, f: f, g: g}
+	if r, ok := o.nt.ops.lookup(key); ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	// split both families on the top variable of g
+	gn := o.node(g)
+	v := gn.Level
+
+	with, err := o.subset1(f, v)
+	if err != nil {
+		return NullNode, err
+	}
+	r, err := o.divide(with, gn.Hi)
+	if err != nil {
+		return NullNode, err
+	}
+
+	if r != ZeroNode && gn.Lo != ZeroNode {
+		without, err := o.subset0(f, v)
+		if err != nil {
+			return NullNode, err
+		}
+		rest, err := o.divide(without, gn.Lo)
+		if err != nil {
+			return NullNode, err
+		}
+		if r, err = o.intersect(r, rest); err != nil {
+			return NullNode, err
+		}
+	}
+
+	o.nt.ops.store(key, r)
+	return r, nil
+}
+
+// nonSubsets returns the sets of f that are not a subset of any set of g
+func (o *familyOps) nonSubsets(f, g NodeID) (NodeID, error) {
+	if g == ZeroNode {
+		return f, nil
+	}
+	if f == ZeroNode || f == OneNode || f == g {
+		return ZeroNode, nil
+	}
+	if g == OneNode {
+		return o.difference(f, OneNode)
+	}
+
+	key := opKey{op: opNonSubsets, f: f, g: g}
+	if r, ok := o.nt.ops.lookup(key); ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	fn, gn := o.node(f), o.node(g)
+	var r NodeID
+	var err error
+
+	switch {
+	case fn.Level > gn.Level:
+		// sets containing the variable are never subsets of a set of g
+		var lo NodeID
+		if lo, err = o.nonSubsets(fn.Lo, g); err != nil {
+			return NullNode, err
+		}
+		r = o.nt.AddNode(fn.Level, lo, fn.Hi)
+	case fn.Level < gn.Level:
+		// f lacks the variable, so compare with the sets of g without it
+		if r, err = o.nonSubsets(f, gn.Lo); err == nil {
+			r, err = o.nonSubsets(r, gn.Hi)
+		}
+	default:
+		var lo, hi NodeID
+		if lo, err = o.nonSubsets(fn.Lo, gn.Lo); err != nil {
+			return NullNode, err
+		}
+		if lo, err = o.nonSubsets(lo, gn.Hi); err != nil {
+			return NullNode, err
+		}
+		if hi, err = o.nonSubsets(fn.Hi, gn.Hi); err != nil {
+			return NullNode, err
+		}
+		r = o.nt.AddNode(fn.Level, lo, hi)
+	}
+	if err != nil {
+		return NullNode, err
+	}
+
+	o.nt.ops.store(key, r)
+	return r, nil
+}
+
+// nonSupersets returns the sets of f that are not a superset of any set of g
+func (o *familyOps) nonSupersets(f, g NodeID) (NodeID, error) {
+	if g == ZeroNode {
+		return f, nil
+	}
+	if f == ZeroNode || g == OneNode || f == g {
+		return ZeroNode, nil
+	}
+
+	key := opKey{op: opNonSupersets, f: f, g: g}
+	if r, ok := o.nt.ops.lookup(key); ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	fn, gn := o.node(f), o.node(g)
+	var r NodeID
+	var err error
+
+	switch {
+	case fn.Level > gn.Level:
+		var lo, hi NodeID
+		if lo, err = o.nonSupersets(fn.Lo, g); err != nil {
+			return NullNode, err
+		}
+		if hi, err = o.nonSupersets(fn.Hi, g); err != nil {
+			return NullNode, err
+		}
+		r = o.nt.AddNode(fn.Level, lo, hi)
+	case fn.Level < gn.Level:
+		// sets of g containing the variable are never subsets of a set of f
+		r, err = o.nonSupersets(f, gn.Lo)
+	default:
+		var lo, hi NodeID
+		if lo, err = o.nonSupersets(fn.Lo, gn.Lo); err != nil {
+			return NullNode, err
+		}
+		if hi, err = o.nonSupersets(fn.Hi, gn.Lo); err != nil {
+			return NullNode, err
+		}
+		if hi, err = o.nonSupersets(hi, gn.Hi); err != nil {
+			return NullNode, err
+		}
+		r = o.nt.AddNode(fn.Level, lo, hi)
+	}
+	if err != nil {
+		return NullNode, err
+	}
+
+	o.nt.ops.store(key, r)
+	return r, nil
+}
+
+// maximal returns the sets of f that are not a proper subset of another set of f
+func (o *familyOps) maximal(f NodeID) (NodeID, error) {
+	if f == ZeroNode || f == OneNode {
+		return f, nil
+	}
+
+	key := opKey{op: opMaximal, f: f}
+	if r, ok := o.nt.ops.lookup(key); ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	// a set without the variable is maximal unless it is inside a set with it
+	node := o.node(f)
+	lo, err := o.maximal(node.Lo)
+	if err != nil {
+		return NullNode, err
+	}
+	hi, err := o.maximal(node.Hi)
+	if err != nil {
+		return NullNode, err
+	}
+	if lo, err = o.nonSubsets(lo, hi); err != nil {
+		return NullNode, err
+	}
+
+	r := o.nt.AddNode(node.Level, lo, hi)
+	o.nt.ops.store(key, r)
+	return r, nil
+}
+
+// downClosure returns every subset of a set of f
+func (o *familyOps) downClosure(f NodeID) (NodeID, error) {
+	if f == ZeroNode || f == OneNode {
+		return f, nil
+	}
+
+	key := opKey{op: opDownClosure, f: f}
+	if r, ok := o.nt.ops.lookup(key); ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	// dropping the variable from a set with it gives a set without it
+	node := o.node(f)
+	lo, err := o.downClosure(node.Lo)
+	if err != nil {
+		return NullNode, err
+	}
+	hi, err := o.downClosure(node.Hi)
+	if err != nil {
+		return NullNode, err
+	}
+	if lo, err = o.union(lo, hi); err != nil {
+		return NullNode, err
+	}
+
+	r := o.nt.AddNode(node.Level, lo, hi)
+	o.nt.ops.store(key, r)
+	return r, nil
+}
+
+// upClosure returns every set over variables level down to 1 that contains a
+// set of f
+func (o *familyOps) upClosure(f NodeID, level int) (NodeID, error) {
+	if f == ZeroNode || level == 0 {
+		return f, nil
+	}
+
+	key := opKey{op: opUpClosure, f: f, v: level}
+	if r, ok := o.nt.ops.lookup(key); ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	node := o.node(f)
+	var r NodeID
+	if node.Level < level {
+		// the variable is free: adding it keeps every superset
+		rest, err := o.upClosure(f, level-1)
+		if err != nil {
+			return NullNode, err
+		}
+		r = o.nt.AddNode(level, rest, rest)
+	} else {
+		lo, err := o.upClosure(node.Lo, level-1)
+		if err != nil {
+			return NullNode, err
+		}
+		hi, err := o.upClosure(node.Hi, level-1)
+		if err != nil {
+			return NullNode, err
+		}
+		// a set with the variable may extend a set without it
+		if hi, err = o.union(lo, hi); err != nil {
+			return NullNode, err
+		}
+		r = o.nt.AddNode(level, lo, hi)
+	}
+
+	o.nt.ops.store(key, r)
+	return r, nil
+}
+
+// minimal returns the sets of f that are not a proper superset of another set of f
+func (o *familyOps) minimal(f NodeID) (NodeID, error) {
+	if f == ZeroNode || f == OneNode {
+		return f, nil
+	}
+
+	key := opKey{op: opMinimal, f: f}
+	if r, ok := o.nt.ops.lookup(key); ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	// a set with the variable is minimal unless it contains a set without it
+	node := o.node(f)
+	lo, err := o.minimal(node.Lo)
+	if err != nil {
+		return NullNode, err
+	}
+	hi, err := o.minimal(node.Hi)
+	if err != nil {
+		return NullNode, err
+	}
+	if hi, err = o.nonSupersets(hi, lo); err != nil {
+		return NullNode, err
+	}
+
+	r := o.nt.AddNode(node.Level, lo, hi)
+	o.nt.ops.store(key, r)
+	return r, nil
+}
+
+// shift copies f with every level moved by delta, which must keep all
+// non-terminal levels positive, and with the One terminal replaced by one.
+//
+// Passing OneNode keeps the family itself; passing the root of a family over
+// the levels below f appends that family to every set of f.
+func (o *familyOps) shift(src *NodeTable, f NodeID, delta int, one NodeID, memo map[NodeID]NodeID) (NodeID, error) {
+	if f == ZeroNode {
+		return f, nil
+	}
+	if f == OneNode {
+		return one, nil
+	}
+	if r, ok := memo[f]; ok {
+		return r, nil
+	}
+
+	node, err := src.GetNode(f)
+	if err != nil {
+		return NullNode, err
+	}
+
+	lo, err := o.shift(src, node.Lo, delta, one, memo)
+	if err != nil {
+		return NullNode, err
+	}
+	hi, err := o.shift(src, node.Hi, delta, one, memo)
+	if err != nil {
+		return NullNode, err
+	}
+
+	r := o.nt.AddNode(node.Level+delta, lo, hi)
+	memo[f] = r
+	return r, nil
+}
+
+// family returns the root of z as a family, treating an unbuilt ZDD as empty
+func (z *ZDD) family() NodeID {
+	if z.root == NullNode {
+		return ZeroNode
+	}
+	return z.root
+}
+
+// workspace creates a scratch node table holding copies of the given ZDDs.
+//
+// ZDDs that all belong to one manager are not copied: the operation runs in
+// the shared table, whose operation cache then carries over between calls.
+// Quasi-reduced operands are reduced within the shared table first.
+// Returns the operation context and the roots in argument order.
+func workspace(ctx context.Context, zdds ...*ZDD) (*familyOps, []NodeID, error) {
+	roots := make([]NodeID, len(zdds))
+
+	if m := sharedManager(zdds); m != nil {
+		ops := newFamilyOps(ctx, m.table())
+		for i, z := range zdds {
+			roots[i] = z.family()
+			if !z.config.QuasiReduced {
+				continue
+			}
+
+			// the operations expect reduced operands
+			root, err := ops.importNode(ops.nt, roots[i], make(map[NodeID]NodeID))
+			if err != nil {
+				return nil, nil, err
+			}
+			roots[i] = root
+		}
+		return ops, roots, nil
+	}
+
+	ops := newFamilyOps(ctx, NewNodeTable())
+
+	for i, z := range zdds {
+		root, err := ops.importNode(z.nodes, z.family(), make(map[NodeID]NodeID))
+		if err != nil {
+			return nil, nil, err
+		}
+		roots[i] = root
+	}
+
+	return ops, roots, nil
+}
+
+// sharedManager returns the manager all zdds belong to, or nil
+func sharedManager(zdds []*ZDD) *Manager {
+	if len(zdds) == 0 {
+		return nil
+	}
+	m := zdds[0].manager
+	for _, z := range zdds[1:] {
+		if z.manager != m {
+			return nil
+		}
+	}
+	return m
+}
+
+// derive creates a new ZDD over vars whose family is root in the working table.
+//
+// Only nodes reachable from root are copied, so intermediate results of the
+// operation do not inflate the size of the new ZDD. If work is the shared
+// table of z's manager, nothing is copied and the new ZDD is registered with
+// the manager instead.
+func (z *ZDD) derive(vars int, work *NodeTable, root NodeID) (*ZDD, error) {
+	cfg := *z.config
+	if z.manager != nil && work == z.manager.table() {
+		result := z.manager.NewZDD(vars)
+		result.config = &cfg
+		result.root = root
+		if vars == z.vars {
+			result.groups = z.groups
+		}
+		return result, nil
+	}
+
+	result := &ZDD{
+		root:   NullNode,
+		nodes:  NewNodeTable(),
+		vars:   vars,
+		config: &cfg,
+	}
+
+	ops := newFamilyOps(context.Background(), result.nodes)
+	copied, err := ops.importNode(work, root, make(map[NodeID]NodeID))
+	if err != nil {
+		return nil, err
+	}
+
+	if vars == z.vars {
+		result.groups = z.groups
+	}
+
+	result.root = copied
+	return result, nil
+}
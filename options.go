@@ -1,6 +1,8 @@
 package gozdd
 
 import (
+	"io"
+	"log/slog"
 	"runtime"
 	"time"
 )
@@ -19,6 +21,36 @@ type Config struct {
 	// Timeout specifies the maximum duration for ZDD construction.
 	// A value of 0 means no timeout is enforced.
 	Timeout time.Duration
+	
+	// ValidationCache enables memoization of IsValid results during Build.
+	ValidationCache bool
+	
+	// Trace receives a log line for every spec call during Build (optional).
+	Trace io.Writer
+	
+	// ResultCache enables memoization of built-in evaluator results.
+	ResultCache bool
+	
+	// SolutionDecorator is called on every solution returned to the caller (optional).
+	SolutionDecorator func(*Solution)
+	
+	// StableOrder makes every search break cost ties in the documented solution order.
+	StableOrder bool
+	
+	// BreadthFirst makes Build construct the diagram level by level.
+	BreadthFirst bool
+	
+	// Deterministic makes parallel construction assign the same node IDs on every run.
+	Deterministic bool
+	
+	// QuasiReduced makes Build create a node at every level of every path.
+	QuasiReduced bool
+	
+	// Progress is called periodically while Build runs (optional).
+	Progress func(ProgressInfo)
+	
+	// Logger receives debug events from Build and EvaluateZDD (optional).
+	Logger *slog.Logger
 }
 
 // Option configures ZDD construction parameters using the functional options pattern.
@@ -29,9 +61,14 @@ type Option func(*Config)
 // 
 // If workers <= 0, defaults to runtime.NumCPU() for optimal CPU utilization.
 // If workers == 1, construction runs sequentially without goroutine overhead.
-// If workers > 1, construction uses parallel algorithms where applicable.
+// If workers > 1, Build constructs the diagram level by level: the distinct
+// states of a level are shared out among the workers, which call GetChild
+// and IsValid concurrently, so the spec and its states must be safe for
+// concurrent use. Node IDs then depend on scheduling unless
+// WithDeterministic is also given.
 //
-// Note: Not all construction phases can be parallelized. The actual speedup
+// Note: Not all construction phases can be parallelized. Nodes are created
+// by a single goroutine, so the speedup comes from the spec's own work and
 // depends on the problem structure and constraint complexity.
 func WithParallel(workers int) Option {
 	return func(c *Config) {
@@ -69,6 +106,174 @@ func WithTimeout(d time.Duration) Option {
 	}
 }
 
+// WithValidationCache memoizes IsValid results during Build.
+//
+// Terminal states are often validated repeatedly, especially when SkipState
+// shortcuts reach the terminal from many places. With this option each distinct
+// state (by Hash and Equal) is validated once per Build. If the spec implements
+// BatchValidator, ValidationCache.IsValidBatch can be used to validate states
+// in bulk outside of construction.
+func WithValidationCache() Option {
+	return func(c *Config) {
+		c.ValidationCache = true
+	}
+}
+
+// WithTrace logs every GetChild and IsValid call made during Build to w.
+//
+// Each line shows the level, the branch taken, a summary of the state and the
+// outcome: the child state, a skip target, or the prune error. States that
+// implement fmt.Stringer are rendered with String. Tracing is meant for small
+// instances while debugging a spec; output grows with the number of transitions.
+func WithTrace(w io.Writer) Option {
+	return func(c *Config) {
+		c.Trace = w
+	}
+}
+
+// WithResultCache memoizes the results of the built-in evaluators on the ZDD.
+//
+// Count, FindKBest and EvaluateZDD with CountEvaluator, CostEvaluator or
+// KBestEvaluator return cached results for repeated queries with identical
+// parameters, such as the same cost vector. Entries are dropped automatically
+// when the diagram is rebuilt and can be cleared with InvalidateResults.
+// Returned solutions are copies, so callers may modify them freely.
+//
+// Per-node intermediate values are shared across different queries too: the
+// subtree counts behind Count, CountBig, LogCount, Marginals and the Count of
+// a KBestResult are computed once per diagram.
+func WithResultCache() Option {
+	return func(c *Config) {
+		c.ResultCache = true
+	}
+}
+
+// WithSolutionDecorator calls fn on every solution before it is returned.
+//
+// The decorator runs once per returned solution from FindKBest, EvaluateZDD
+// with the built-in optimization evaluators, FindKBestAnytime, the exact
+// evaluators, FrozenZDD.FindKBest, WhatIfSession.Best and SolveMaxSAT. Use it
+// to fill Metadata with names or derived figures in one place instead of after
+// every query. Variables and Cost are already final when fn is called, and
+// Metadata is never nil. Solutions served from the result cache are fresh
+// copies and are decorated again.
+func WithSolutionDecorator(fn func(*Solution)) Option {
+	return func(c *Config) {
+		c.SolutionDecorator = fn
+	}
+}
+
+// WithStableOrder guarantees the documented order for solutions of equal cost.
+//
+// Solutions are ordered by cost, then by the highest variable in which they
+// differ: the solution without that variable comes first. Equivalently, sets
+// of equal cost ascend as binary numbers in which variable v is worth
+// 2^(v-1), so [1 2] precedes [3] and [3] precedes [1 3]. This is also the
+// lexicographic order of the variable lists read from the highest variable
+// down. The order depends only on the family and the costs, never on node IDs
+// or scheduling, so results are reproducible across runs, machines and
+// library versions. Costs tie only if their float64 sums are exactly equal.
+//
+// FindKBest, CostEvaluator, KBestEvaluator and the exact evaluators always
+// follow this order, and GroupPatterns and FrozenZDD.Sets enumerate in it. The
+// option extends the guarantee to the branch-and-bound searches of
+// FindKBestAnytime and FrozenZDD.FindKBest, which then explore the Lo arc
+// first rather than the more promising one. An interrupted anytime search may
+// therefore find good solutions later.
+func WithStableOrder() Option {
+	return func(c *Config) {
+		c.StableOrder = true
+	}
+}
+
+// WithBreadthFirst makes Build construct the diagram level by level instead
+// of depth first.
+//
+// This is the frontier method of TdZdd and Graphillion: all distinct states
+// of a level are collected and merged before any of them is expanded, and
+// their nodes are created bottom-up once every level is expanded. The states
+// of a level are released as soon as it is expanded, so memory for states is
+// bounded by the widest levels still pending, whereas depth-first
+// construction keeps every state until Build returns. Frontier-style specs,
+// whose states only describe the boundary between decided and open
+// variables, benefit most. WithParallel implies this mode; both build the
+// same family as the default, but node IDs are assigned in another order.
+func WithBreadthFirst() Option {
+	return func(c *Config) {
+		c.BreadthFirst = true
+	}
+}
+
+// WithDeterministic makes parallel construction reproducible: every run
+// assigns the same node IDs and builds the identical diagram, whatever the
+// number of workers, so results can be compared byte for byte, for example
+// with golden files of WriteDOT output.
+//
+// Workers still compute the transitions of each level concurrently, but
+// the states they reach are merged afterwards on one goroutine, in a fixed
+// order. The diagram is the one WithBreadthFirst builds without workers.
+// The merging no longer overlaps with the spec's work, so the speedup is
+// smaller when GetChild is cheap compared with hashing and comparing states.
+// Sequential construction is always deterministic and needs no option.
+func WithDeterministic() Option {
+	return func(c *Config) {
+		c.Deterministic = true
+	}
+}
+
+// WithQuasiReduced makes Build construct a quasi-reduced diagram, in which
+// every path to the 1-terminal has a node at every level.
+//
+// Zero suppression removes nodes whose Hi arc leads to the 0-terminal, so
+// an arc of a reduced diagram may jump over levels whose variables are
+// unselected on it. A quasi-reduced diagram keeps these nodes instead, and
+// fills the levels a skip or a shared sub-diagram jumps over with them, so
+// algorithms can walk it level by level without accounting for gaps. Arcs
+// to the 0-terminal still jump. The diagram represents the same family and
+// works with every evaluator, but has more nodes; equal sub-diagrams are
+// still shared. Since no arc skips a level, CountEvaluator with SkippedFree
+// counts the same as without. Only Build honours the option; operations
+// combining diagrams return reduced results, and ZDD.Reduce turns the
+// diagram into its reduced form. Manager.Register and Manager.GC keep the
+// diagram quasi-reduced.
+func WithQuasiReduced() Option {
+	return func(c *Config) {
+		c.QuasiReduced = true
+	}
+}
+
+// WithProgress calls fn periodically while Build runs, with the nodes
+// created, the level being constructed, the states found and the elapsed
+// time.
+//
+// Reports come about ten times per second and once more when construction
+// succeeds, with Done set, so fn can drive a progress bar or estimate the
+// remaining time from the level. fn runs on the building goroutine, or on
+// one worker at a time with WithParallel, and should return quickly.
+func WithProgress(fn func(ProgressInfo)) Option {
+	return func(c *Config) {
+		c.Progress = fn
+	}
+}
+
+// WithLogger sends debug events from Build and EvaluateZDD to logger.
+//
+// At slog.LevelDebug, Build logs its strategy, each level transition of
+// breadth-first construction, and a summary with the node count, the states
+// found, the state and validation cache hit rates, and the numbers of pruned
+// branches and skips. Each EvaluateZDD call, and so each convenience method
+// such as Count, logs the evaluator, its duration and whether the result
+// cache answered it. At LevelTrace every pruned branch is logged with its
+// error, which explains an unexpectedly small family.
+//
+// Nothing is logged, and no counting is done, if the logger's handler
+// discards debug events.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
 // newConfig creates a new configuration with sensible defaults and applies
 // the provided options in order.
 //
@@ -0,0 +1,159 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// MultiObjectiveEvaluator finds the Pareto-optimal solutions for several
+// cost vectors.
+//
+// A solution is Pareto-optimal if no other solution is at most as costly in
+// every objective and cheaper in one. Each node keeps the non-dominated cost
+// vectors of its completions, merging its children bottom-up and pruning
+// dominated entries, so solutions are never enumerated. Solutions with equal
+// cost vectors are reported once, as the first of them in the order
+// documented at WithStableOrder. The result is a ParetoResult.
+type MultiObjectiveEvaluator struct {
+	// Costs holds one cost vector per objective, each laid out like
+	// CostEvaluator.Costs (1-based indexing)
+	Costs [][]float64
+}
+
+// ParetoResult holds the Pareto-optimal solutions.
+type ParetoResult struct {
+	// Solutions are ordered lexicographically by their cost vectors. Cost is
+	// the first objective and Metadata["costs"] holds the whole vector.
+	Solutions []*Solution
+
+	// Costs holds the cost vector of each solution
+	Costs [][]float64
+}
+
+// paretoEntry is a non-dominated completion of a node: its cost vector and
+// the completion of the chosen child it continues with
+type paretoEntry struct {
+	costs []float64
+	take  bool
+	rank  int
+}
+
+// Evaluate finds the Pareto-optimal solutions
+func (e MultiObjectiveEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
+	if err := checkObjectives(zdd.vars, e.Costs); err != nil {
+		return ParetoResult{}, err
+	}
+
+	fronts := map[NodeID][]paretoEntry{OneNode: {{costs: make([]float64, len(e.Costs))}}}
+	err := zdd.bottomUp(ctx, func(id NodeID, node Node) error {
+		// Lo candidates come first, so ties keep the stable order
+		var candidates []paretoEntry
+		for i, entry := range fronts[node.Lo] {
+			candidates = append(candidates, paretoEntry{costs: entry.costs, rank: i})
+		}
+		for i, entry := range fronts[node.Hi] {
+			costs := make([]float64, len(entry.costs))
+			for j := range costs {
+				costs[j] = entry.costs[j] + e.Costs[j][node.Level]
+			}
+			candidates = append(candidates, paretoEntry{costs: costs, take: true, rank: i})
+		}
+
+		var front []paretoEntry
+		for i, c := range candidates {
+			kept := true
+			for j, other := range candidates {
+				if dominates(other.costs, c.costs) || (j < i && equalVector(other.costs, c.costs)) {
+					kept = false
+					break
+				}
+			}
+			if kept {
+				front = append(front, c)
+			}
+		}
+
+		fronts[id] = front
+		return nil
+	})
+	if err != nil {
+		return ParetoResult{}, fmt.Errorf("pareto evaluation failed: %w", err)
+	}
+
+	root := zdd.family()
+	order := make([]int, len(fronts[root]))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return lexLess(fronts[root][order[a]].costs, fronts[root][order[b]].costs)
+	})
+
+	result := ParetoResult{Solutions: make([]*Solution, len(order)), Costs: make([][]float64, len(order))}
+	for i, rank := range order {
+		costs := append([]float64(nil), fronts[root][rank].costs...)
+		result.Costs[i] = costs
+		result.Solutions[i] = &Solution{
+			Variables: e.variables(zdd, fronts, root, rank),
+			Cost:      costs[0],
+			Metadata:  map[string]interface{}{"costs": append([]float64(nil), costs...)},
+		}
+	}
+	return result, nil
+}
+
+// variables reconstructs the variables of a completion of a node
+func (e MultiObjectiveEvaluator) variables(zdd *ZDD, fronts map[NodeID][]paretoEntry, nodeID NodeID, rank int) []int {
+	vars := []int{}
+	for nodeID != OneNode {
+		entry := fronts[nodeID][rank]
+		node, _ := zdd.GetNode(nodeID)
+		if entry.take {
+			vars = append(vars, node.Level)
+			nodeID = node.Hi
+		} else {
+			nodeID = node.Lo
+		}
+		rank = entry.rank
+	}
+	sort.Ints(vars)
+	return vars
+}
+
+// dominates reports whether cost vector a is at most b everywhere and less
+// somewhere
+func dominates(a, b []float64) bool {
+	strict := false
+	for i := range a {
+		if a[i] > b[i] {
+			return false
+		}
+		if a[i] < b[i] {
+			strict = true
+		}
+	}
+	return strict
+}
+
+// equalVector reports whether two cost vectors are equal
+func equalVector(a, b []float64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ParetoFront returns the Pareto-optimal solutions for the given objectives.
+//
+// This is a type-safe convenience method for MultiObjectiveEvaluator. Each
+// solution's Metadata["costs"] holds its cost vector.
+func (z *ZDD) ParetoFront(ctx context.Context, objectives ...[]float64) ([]*Solution, error) {
+	result, err := EvaluateZDD(ctx, z, MultiObjectiveEvaluator{Costs: objectives})
+	if err != nil {
+		return nil, err
+	}
+	return result.(ParetoResult).Solutions, nil
+}
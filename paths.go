@@ -0,0 +1,165 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// Step is one arc on a path through the diagram.
+type Step struct {
+	// From is the node the arc leaves
+	From NodeID
+
+	// Level is the variable decided at From
+	Level int
+
+	// Take is true for the Hi arc (variable selected), false for the Lo arc
+	Take bool
+}
+
+// PathTo returns one path of arcs from the root to target.
+//
+// The path explains which partial assignment leads to target: each step
+// records a decided variable and whether it was selected. Levels between
+// consecutive steps are skipped by zero suppression and count as not
+// selected. Lo arcs are preferred, so the returned path selects as few
+// high-level variables as possible. The path to the root itself is empty.
+//
+// Returns ErrInvalidNode if target is not reachable from the root.
+func (z *ZDD) PathTo(ctx context.Context, target NodeID) ([]Step, error) {
+	reach, err := z.reaching(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if !reach[z.root] {
+		return nil, fmt.Errorf("%w: node %d is not reachable from the root", ErrInvalidNode, target)
+	}
+
+	var path []Step
+	for id := z.root; id != target; {
+		node, err := z.GetNode(id)
+		if err != nil {
+			return nil, err
+		}
+
+		take := !reach[node.Lo]
+		path = append(path, Step{From: id, Level: node.Level, Take: take})
+		if take {
+			id = node.Hi
+		} else {
+			id = node.Lo
+		}
+	}
+
+	return path, nil
+}
+
+// PathsTo returns an iterator over all paths from the root to target.
+//
+// Paths are produced depth-first with Lo arcs before Hi arcs; each yielded
+// slice is freshly allocated. The number of paths can grow exponentially with
+// the number of levels above target, so callers should stop early when they
+// have seen enough. Iteration ends early if ctx is cancelled, and yields
+// nothing if target is not reachable.
+func (z *ZDD) PathsTo(ctx context.Context, target NodeID) iter.Seq[[]Step] {
+	return func(yield func([]Step) bool) {
+		reach, err := z.reaching(ctx, target)
+		if err != nil || !reach[z.root] {
+			return
+		}
+
+		var path []Step
+		var walk func(id NodeID) bool
+		walk = func(id NodeID) bool {
+			if ctx.Err() != nil {
+				return false
+			}
+			if id == target {
+				return yield(append([]Step(nil), path...))
+			}
+
+			node, err := z.GetNode(id)
+			if err != nil {
+				return false
+			}
+
+			for _, take := range []bool{false, true} {
+				child := node.Lo
+				if take {
+					child = node.Hi
+				}
+				if !reach[child] {
+					continue
+				}
+
+				path = append(path, Step{From: id, Level: node.Level, Take: take})
+				ok := walk(child)
+				path = path[:len(path)-1]
+				if !ok {
+					return false
+				}
+			}
+			return true
+		}
+
+		walk(z.root)
+	}
+}
+
+// reaching marks every node from which target can be reached, target included
+func (z *ZDD) reaching(ctx context.Context, target NodeID) (map[NodeID]bool, error) {
+	goal, err := z.GetNode(target)
+	if err != nil {
+		return nil, err
+	}
+
+	reach := make(map[NodeID]bool)
+	if z.root == NullNode {
+		return reach, nil
+	}
+
+	var mark func(id NodeID) (bool, error)
+	mark = func(id NodeID) (bool, error) {
+		if id == target {
+			reach[id] = true
+			return true, nil
+		}
+		if r, ok := reach[id]; ok {
+			return r, nil
+		}
+
+		node, err := z.GetNode(id)
+		if err != nil {
+			return false, err
+		}
+
+		// children have lower levels, so target cannot be below a node at
+		// its own level or lower
+		if node.IsTerminal() || node.Level <= goal.Level {
+			reach[id] = false
+			return false, nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		lo, err := mark(node.Lo)
+		if err != nil {
+			return false, err
+		}
+		hi, err := mark(node.Hi)
+		if err != nil {
+			return false, err
+		}
+
+		reach[id] = lo || hi
+		return reach[id], nil
+	}
+
+	if _, err := mark(z.root); err != nil {
+		return nil, err
+	}
+	return reach, nil
+}
@@ -0,0 +1,101 @@
+package gozdd
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// progressInterval is the minimum time between two progress reports
+const progressInterval = 100 * time.Millisecond
+
+// progressCheck is the number of steps between two looks at the clock
+const progressCheck = 256
+
+// ProgressInfo describes how far a Build has come.
+type ProgressInfo struct {
+	// Nodes is the number of nodes created so far
+	Nodes int
+
+	// Level is the level being constructed. Levels count down from
+	// Variables() to 1, and breadth-first construction passes through them
+	// twice: down while expanding states, then up while creating nodes.
+	Level int
+
+	// States is the number of distinct states found so far
+	States int
+
+	// Elapsed is the time since Build started
+	Elapsed time.Duration
+
+	// Done is set on the final report, made once construction succeeds
+	Done bool
+}
+
+// buildProgress reports the progress of one Build. A nil *buildProgress
+// reports nothing, so builders call it unconditionally.
+type buildProgress struct {
+	fn    func(ProgressInfo)
+	nodes *NodeTable
+	base  int
+	start time.Time
+
+	steps  atomic.Int64
+	states atomic.Int64
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// newBuildProgress returns a reporter calling fn, or nil if fn is nil
+func newBuildProgress(fn func(ProgressInfo), nodes *NodeTable) *buildProgress {
+	if fn == nil {
+		return nil
+	}
+	now := time.Now()
+	return &buildProgress{fn: fn, nodes: nodes, base: nodes.Size(), start: now, last: now}
+}
+
+// state counts a newly found state
+func (p *buildProgress) state() {
+	if p != nil {
+		p.states.Add(1)
+	}
+}
+
+// step notes work at level and reports if the interval has passed. Safe for
+// concurrent use; reports are never made concurrently.
+func (p *buildProgress) step(level int) {
+	if p == nil || p.steps.Add(1)%progressCheck != 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if now := time.Now(); now.Sub(p.last) >= progressInterval {
+		p.last = now
+		p.fn(p.info(level, false))
+	}
+}
+
+// done makes the final report
+func (p *buildProgress) done(level int) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fn(p.info(level, true))
+}
+
+// info snapshots the counters
+func (p *buildProgress) info(level int, done bool) ProgressInfo {
+	return ProgressInfo{
+		Nodes:   p.nodes.Size() - p.base,
+		Level:   level,
+		States:  int(p.states.Load()),
+		Elapsed: time.Since(p.start),
+		Done:    done,
+	}
+}
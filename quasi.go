@@ -0,0 +1,28 @@
+package gozdd
+
+// addNode returns the node at level with arcs lo and hi. Quasi-reduced
+// diagrams keep nodes whose Hi arc leads to the 0-terminal and fill the
+// levels an arc jumps over with such nodes; the empty family stays the
+// 0-terminal.
+func (z *ZDD) addNode(level int, lo, hi NodeID) NodeID {
+	if !z.config.QuasiReduced || (lo == ZeroNode && hi == ZeroNode) {
+		return z.nodes.AddNode(level, lo, hi)
+	}
+	return z.nodes.insert(level, z.nodes.lift(lo, level-1), z.nodes.lift(hi, level-1))
+}
+
+// lift returns a node at level with the family of id, chaining nodes whose
+// Hi arcs lead to the 0-terminal above it. The 0-terminal is not lifted.
+func (nt *NodeTable) lift(id NodeID, level int) NodeID {
+	if id == ZeroNode {
+		return id
+	}
+	node, err := nt.GetNode(id)
+	if err != nil {
+		return id
+	}
+	for l := node.Level + 1; l <= level; l++ {
+		id = nt.insert(l, id, ZeroNode)
+	}
+	return id
+}
@@ -0,0 +1,139 @@
+package gozdd_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/zzenonn/go-zdd"
+)
+
+// buildBoth builds spec as a reduced and as a quasi-reduced diagram
+func buildBoth(t *testing.T, spec *SimpleSpec, opts ...gozdd.Option) (reduced, quasi *gozdd.ZDD) {
+	t.Helper()
+	reduced = gozdd.NewZDD(spec.vars, opts...)
+	quasi = gozdd.NewZDD(spec.vars, append(opts, gozdd.WithQuasiReduced())...)
+	for _, z := range []*gozdd.ZDD{reduced, quasi} {
+		if err := z.Build(context.Background(), spec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if quasi.IsReduced() {
+		t.Fatal("WithQuasiReduced built a reduced diagram")
+	}
+	return reduced, quasi
+}
+
+func TestQuasiReducedEquals(t *testing.T) {
+	spec := &SimpleSpec{vars: 3, maxCount: 1}
+	reduced, quasi := buildBoth(t, spec)
+	if !reduced.Equals(quasi) || !quasi.Equals(reduced) {
+		t.Error("a quasi-reduced diagram differs from the reduced diagram of its family")
+	}
+	if !quasi.IsSubfamilyOf(reduced) || !reduced.IsSubfamilyOf(quasi) {
+		t.Error("a quasi-reduced diagram is not a subfamily of the reduced diagram of its family")
+	}
+
+	// ZDDs of one manager share a table, which must not shortcut the walk
+	m := gozdd.NewManager()
+	managedReduced := m.NewZDD(3)
+	managedQuasi := m.NewZDD(3, gozdd.WithQuasiReduced())
+	for _, z := range []*gozdd.ZDD{managedReduced, managedQuasi} {
+		if err := z.Build(context.Background(), spec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !managedReduced.Equals(managedQuasi) || !managedQuasi.Equals(managedReduced) {
+		t.Error("ZDDs of one manager: a quasi-reduced diagram differs from the reduced one")
+	}
+
+	// one solution more is a strict superfamily
+	larger, _ := buildBoth(t, &SimpleSpec{vars: 3, maxCount: 2})
+	if quasi.Equals(larger) || !quasi.IsSubfamilyOf(larger) || larger.IsSubfamilyOf(quasi) {
+		t.Error("a quasi-reduced diagram compares wrongly with a larger family")
+	}
+}
+
+func TestQuasiReducedEvaluators(t *testing.T) {
+	ctx := context.Background()
+	reduced, quasi := buildBoth(t, &SimpleSpec{vars: 4, maxCount: 2})
+	costs := []float64{0, 3, -1, 2, -2}
+	rats := []*big.Rat{nil, big.NewRat(3, 1), big.NewRat(-1, 1), big.NewRat(2, 1), big.NewRat(-2, 1)}
+
+	queries := map[string]func(z *gozdd.ZDD) (interface{}, error){
+		"MinCardinality": func(z *gozdd.ZDD) (interface{}, error) { return z.MinCardinality(ctx) },
+		"MaxCardinality": func(z *gozdd.ZDD) (interface{}, error) { return z.MaxCardinality(ctx) },
+		"FindBottleneck": func(z *gozdd.ZDD) (interface{}, error) { return z.FindBottleneck(ctx, costs) },
+		"FindLexicographic": func(z *gozdd.ZDD) (interface{}, error) {
+			return z.FindLexicographic(ctx, costs, []float64{0, 1, 1, 1, 1})
+		},
+		"FindKBestRat": func(z *gozdd.ZDD) (interface{}, error) {
+			result, err := z.FindKBestRat(ctx, 4, rats)
+			return fmt.Sprint(solutionList(result.Solutions), result.Costs), err
+		},
+		"ApproxCount": func(z *gozdd.ZDD) (interface{}, error) { return z.ApproxCount(ctx, 20, 4327) },
+		"Sample": func(z *gozdd.ZDD) (interface{}, error) {
+			samples, err := z.Sample(ctx, 8, 4327)
+			return solutionList(samples), err
+		},
+		"SampleBoltzmann": func(z *gozdd.ZDD) (interface{}, error) {
+			samples, err := z.SampleBoltzmann(ctx, 8, costs, 1.5, 4327)
+			return solutionList(samples), err
+		},
+		"Combine": func(z *gozdd.ZDD) (interface{}, error) {
+			combined, err := gozdd.Combine(ctx, z, gozdd.NewZDD(4))
+			if err != nil {
+				return nil, err
+			}
+			return combined.Count(ctx)
+		},
+	}
+	for name, query := range queries {
+		want, err := query(reduced)
+		if err != nil {
+			t.Fatalf("%s on the reduced diagram: %v", name, err)
+		}
+		got, err := query(quasi)
+		if err != nil {
+			t.Errorf("%s on the quasi-reduced diagram: %v", name, err)
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%s: quasi-reduced %v, reduced %v", name, got, want)
+		}
+	}
+}
+
+func TestQuasiReducedManager(t *testing.T) {
+	ctx := context.Background()
+	reduced, quasi := buildBoth(t, &SimpleSpec{vars: 4, maxCount: 2})
+	_, divisor := buildBoth(t, &SimpleSpec{vars: 4, maxCount: 1})
+
+	m := gozdd.NewManager()
+	for _, z := range []*gozdd.ZDD{quasi, divisor} {
+		if err := m.Register(z); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if quasi.IsReduced() {
+		t.Error("Register reduced a quasi-reduced diagram")
+	}
+	m.GC()
+	if quasi.IsReduced() || !quasi.Equals(reduced) {
+		t.Error("GC changed a quasi-reduced diagram")
+	}
+
+	// operations within the shared table see reduced operands
+	want, err := reduced.Divide(ctx, divisor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := quasi.Divide(ctx, divisor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equals(want) || !got.IsReduced() {
+		t.Error("Divide of managed quasi-reduced diagrams differs from the reduced result")
+	}
+}
@@ -0,0 +1,173 @@
+package gozdd
+
+import "sort"
+
+// Contains reports whether the set of selected variables vars is a solution.
+//
+// The query follows a single path from the root, so it takes time
+// proportional to Variables() regardless of how many solutions the ZDD
+// holds. The order of vars does not matter and repeated variables are
+// ignored. Sets with variables outside 1..Variables() are never contained.
+func (z *ZDD) Contains(vars []int) bool {
+	want := append([]int(nil), vars...)
+	sort.Sort(sort.Reverse(sort.IntSlice(want)))
+
+	id := z.family()
+	for i := 0; ; {
+		// skip repeats of the variable just taken
+		for i > 0 && i < len(want) && want[i] == want[i-1] {
+			i++
+		}
+
+		if id == ZeroNode {
+			return false
+		}
+		if id == OneNode {
+			return i == len(want)
+		}
+
+		node, err := z.GetNode(id)
+		if err != nil {
+			return false
+		}
+
+		switch {
+		case i < len(want) && want[i] > node.Level:
+			// the variable was skipped, so no solution here selects it
+			return false
+		case i < len(want) && want[i] == node.Level:
+			id = node.Hi
+			i++
+		default:
+			id = node.Lo
+		}
+	}
+}
+
+// IsEmpty reports whether the ZDD has no solutions. A ZDD that has not been
+// built yet is empty.
+func (z *ZDD) IsEmpty() bool {
+	return z.family() == ZeroNode
+}
+
+// Equals reports whether z and other hold exactly the same solutions.
+//
+// Reduced diagrams are canonical, so for ZDDs sharing a node table, such as
+// ZDDs of one Manager, the roots are compared in constant time. Otherwise
+// both diagrams are walked in step, visiting each pair of nodes once; nodes
+// of quasi-reduced diagrams whose Hi arc leads to the 0-terminal are passed
+// through, so a quasi-reduced diagram equals the reduced diagram of its
+// family. Only the solutions are compared, not the number of variables or
+// the configuration.
+func (z *ZDD) Equals(other *ZDD) bool {
+	if other == nil {
+		return false
+	}
+	if z.nodes == other.nodes && !z.config.QuasiReduced && !other.config.QuasiReduced {
+		return z.family() == other.family()
+	}
+
+	seen := make(map[nodePair]bool)
+	stack := []nodePair{{z.family(), other.family()}}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		f, g := z.skipQuasi(p.f), other.skipQuasi(p.g)
+		if f == ZeroNode || f == OneNode || g == ZeroNode || g == OneNode {
+			if f != g {
+				return false
+			}
+			continue
+		}
+		if (z.nodes == other.nodes && f == g) || seen[nodePair{f, g}] {
+			continue
+		}
+		seen[nodePair{f, g}] = true
+
+		fn, errF := z.GetNode(f)
+		gn, errG := other.GetNode(g)
+		if errF != nil || errG != nil || fn.Level != gn.Level {
+			return false
+		}
+		stack = append(stack, nodePair{fn.Lo, gn.Lo}, nodePair{fn.Hi, gn.Hi})
+	}
+	return true
+}
+
+// IsSubfamilyOf reports whether every solution of z is also a solution of
+// other.
+//
+// The check walks both diagrams in step without building new nodes, so it
+// works for ZDDs from unrelated node tables and needs no context. Either
+// diagram may be quasi-reduced. Only the solutions are compared, not the
+// number of variables.
+func (z *ZDD) IsSubfamilyOf(other *ZDD) bool {
+	if other == nil {
+		return false
+	}
+
+	// every pair on the stack must hold for the answer to be true
+	seen := make(map[nodePair]bool)
+	stack := []nodePair{{z.family(), other.family()}}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		f, g := z.skipQuasi(p.f), p.g
+		if f == ZeroNode || (z.nodes == other.nodes && f == g) || seen[nodePair{f, g}] {
+			continue
+		}
+		if g == ZeroNode {
+			return false
+		}
+		seen[nodePair{f, g}] = true
+
+		gn, err := other.GetNode(g)
+		if err != nil && g != OneNode {
+			return false
+		}
+		if f == OneNode {
+			// only the empty set remains, which g holds if its Lo chain
+			// ends in One
+			if g != OneNode {
+				stack = append(stack, nodePair{f, gn.Lo})
+			}
+			continue
+		}
+
+		fn, err := z.GetNode(f)
+		switch {
+		case err != nil:
+			return false
+		case g == OneNode || fn.Level > gn.Level:
+			// every solution through fn.Hi selects a variable g never
+			// selects; fn.Hi is not empty once quasi nodes are skipped
+			return false
+		case fn.Level < gn.Level:
+			stack = append(stack, nodePair{f, gn.Lo})
+		default:
+			stack = append(stack, nodePair{fn.Lo, gn.Lo}, nodePair{fn.Hi, gn.Hi})
+		}
+	}
+	return true
+}
+
+// nodePair identifies a node of z together with a node of another ZDD
+type nodePair struct {
+	f, g NodeID
+}
+
+// skipQuasi follows the Lo arcs of nodes whose Hi arc leads to the
+// 0-terminal, which quasi-reduced diagrams keep; the node it stops at has
+// the same family. Nodes of reduced diagrams are returned unchanged.
+func (z *ZDD) skipQuasi(id NodeID) NodeID {
+	for id != ZeroNode && id != OneNode {
+		node, err := z.GetNode(id)
+		if err != nil || node.Hi != ZeroNode {
+			return id
+		}
+		id = node.Lo
+	}
+	return id
+}
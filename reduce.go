@@ -0,0 +1,69 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+)
+
+// Reduce brings the ZDD into reduced canonical form.
+//
+// Nodes whose Hi arc leads to the 0-terminal are removed, equal sub-diagrams
+// are merged into one node, and nodes that the root does not reach are
+// dropped, so that Size counts the diagram and nothing else. This turns a
+// quasi-reduced diagram built WithQuasiReduced into its reduced form and
+// frees the nodes that earlier or cancelled builds left in the table. The
+// family is unchanged.
+//
+// Node IDs change. Annotations follow their nodes; those of removed nodes
+// are dropped. A ZDD registered with a Manager is reduced within the shared
+// table, and Manager.GC frees the nodes it no longer uses.
+func (z *ZDD) Reduce(ctx context.Context) error {
+	if z.root == NullNode {
+		return nil
+	}
+
+	target := z.nodes
+	if z.manager == nil {
+		target = NewNodeTable()
+	}
+
+	memo := map[NodeID]NodeID{ZeroNode: ZeroNode, OneNode: OneNode}
+	kept := make(map[NodeID]NodeID)
+	err := z.bottomUp(ctx, func(id NodeID, node Node) error {
+		memo[id] = target.AddNode(node.Level, memo[node.Lo], memo[node.Hi])
+		if memo[node.Hi] != ZeroNode {
+			kept[id] = memo[id]
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("reduce failed: %w", err)
+	}
+
+	z.notes.remap(kept)
+	z.root = memo[z.root]
+	z.nodes = target
+	return nil
+}
+
+// IsReduced reports whether the ZDD is in reduced canonical form: no
+// reachable node has its Hi arc leading to the 0-terminal and, unless the
+// ZDD shares its table through a Manager, the table holds no nodes the root
+// does not reach. Equal sub-diagrams are always shared, as the node table
+// merges them on insertion. An unbuilt ZDD is reduced.
+//
+// Build yields a reduced diagram unless WithQuasiReduced is set or the table
+// already held nodes of an earlier build; Reduce restores the form.
+func (z *ZDD) IsReduced() bool {
+	reachable := 0
+	for l := 1; l <= z.vars; l++ {
+		for _, id := range z.layer(l) {
+			node, err := z.nodes.GetNode(id)
+			if err != nil || node.Hi == ZeroNode {
+				return false
+			}
+			reachable++
+		}
+	}
+	return z.manager != nil || reachable+2 == z.nodes.Size()
+}
@@ -0,0 +1,284 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Relabel returns a copy of the ZDD with its variables renamed.
+//
+// mapping sends variable v to mapping[v]; variables without an entry keep
+// their index. The renaming must be one-to-one, but it need not preserve the
+// variable order: nodes are re-sorted as needed, so any permutation is
+// allowed. The result has enough variables for the largest new index and at
+// least Variables(). Registered groups are renamed along with their members.
+// Relabeling a sub-problem diagram by a constant offset places it at another
+// position of a larger model without building it again.
+//
+// Returns ErrInvalidVariable if a key is outside 1..Variables(), a target is
+// below 1, or two variables map to the same index.
+func (z *ZDD) Relabel(ctx context.Context, mapping map[int]int) (*ZDD, error) {
+	target := make([]int, z.vars+1)
+	for v := 1; v <= z.vars; v++ {
+		target[v] = v
+	}
+
+	vars := z.vars
+	for v, to := range mapping {
+		if v < 1 || v > z.vars {
+			return nil, fmt.Errorf("%w: variable %d", ErrInvalidVariable, v)
+		}
+		if to < 1 {
+			return nil, fmt.Errorf("%w: variable %d mapped to %d", ErrInvalidVariable, v, to)
+		}
+		target[v] = to
+		vars = max(vars, to)
+	}
+
+	taken := make(map[int]int, z.vars)
+	for v := 1; v <= z.vars; v++ {
+		if prev, ok := taken[target[v]]; ok {
+			return nil, fmt.Errorf("%w: variables %d and %d both map to %d", ErrInvalidVariable, prev, v, target[v])
+		}
+		taken[target[v]] = v
+	}
+
+	ops, roots, err := workspace(ctx, z)
+	if err != nil {
+		return nil, err
+	}
+	root, err := ops.relabel(roots[0], target, make(map[NodeID]NodeID))
+	if err != nil {
+		return nil, fmt.Errorf("relabel failed: %w", err)
+	}
+
+	result, err := z.derive(vars, ops.nt, root)
+	if err != nil {
+		return nil, err
+	}
+
+	result.groups = nil
+	if len(z.groups) > 0 {
+		result.groups = make(map[string][]int, len(z.groups))
+		for name, members := range z.groups {
+			renamed := make([]int, len(members))
+			for i, v := range members {
+				renamed[i] = target[v]
+			}
+			sort.Ints(renamed)
+			result.groups[name] = renamed
+		}
+	}
+	return result, nil
+}
+
+// relabel renames every variable l of f to target[l].
+//
+// Each node becomes the union of its relabeled Lo family and its relabeled Hi
+// family with the new variable added, which handles targets in any order.
+func (o *familyOps) relabel(f NodeID, target []int, memo map[NodeID]NodeID) (NodeID, error) {
+	if f == ZeroNode || f == OneNode {
+		return f, nil
+	}
+	if r, ok := memo[f]; ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	node := o.node(f)
+	lo, err := o.relabel(node.Lo, target, memo)
+	if err != nil {
+		return NullNode, err
+	}
+	hi, err := o.relabel(node.Hi, target, memo)
+	if err != nil {
+		return NullNode, err
+	}
+	if hi, err = o.change(hi, target[node.Level]); err != nil {
+		return NullNode, err
+	}
+
+	r, err := o.union(lo, hi)
+	if err != nil {
+		return NullNode, err
+	}
+	memo[f] = r
+	return r, nil
+}
+
+// SwapAdjacent returns a copy of the ZDD with variables l and l+1 exchanged.
+//
+// Variable indices are levels, so this is the level swap of variable
+// reordering: it gives the same result as Relabel with l and l+1 mapped to
+// each other, but works locally: nodes at levels l and l+1 are rewritten
+// from their cofactors, nodes above are rebuilt over the result and nodes
+// below are left untouched. Chains of swaps align the variable orders of two
+// ZDDs before a binary operation. Registered groups are renamed along with
+// their members.
+//
+// Returns ErrInvalidVariable if l is outside 1..Variables()-1.
+func (z *ZDD) SwapAdjacent(ctx context.Context, l int) (*ZDD, error) {
+	if l < 1 || l >= z.vars {
+		return nil, fmt.Errorf("%w: cannot swap level %d with %d", ErrInvalidVariable, l, l+1)
+	}
+
+	ops, roots, err := workspace(ctx, z)
+	if err != nil {
+		return nil, err
+	}
+	root, err := ops.swap(roots[0], l, make(map[NodeID]NodeID))
+	if err != nil {
+		return nil, fmt.Errorf("swap failed: %w", err)
+	}
+
+	result, err := z.derive(z.vars, ops.nt, root)
+	if err != nil {
+		return nil, err
+	}
+	result.groups = nil
+	if len(z.groups) > 0 {
+		result.groups = make(map[string][]int, len(z.groups))
+	}
+	for name, members := range z.groups {
+		renamed := make([]int, len(members))
+		for i, v := range members {
+			switch v {
+			case l:
+				v = l + 1
+			case l + 1:
+				v = l
+			}
+			renamed[i] = v
+		}
+		sort.Ints(renamed)
+		result.groups[name] = renamed
+	}
+	return result, nil
+}
+
+// swap exchanges variables l and l+1 in f.
+//
+// A node at level l+1 with cofactors f00, f01, f10, f11 on the two variables
+// becomes (l+1, (l, f00, f10), (l, f01, f11)); a node at level l simply moves
+// up to level l+1.
+func (o *familyOps) swap(f NodeID, l int, memo map[NodeID]NodeID) (NodeID, error) {
+	node := o.node(f)
+	if node.Level < l {
+		return f, nil
+	}
+	if r, ok := memo[f]; ok {
+		return r, nil
+	}
+	if err := o.checkCancel(); err != nil {
+		return NullNode, err
+	}
+
+	var r NodeID
+	switch {
+	case node.Level == l:
+		r = o.nt.AddNode(l+1, node.Lo, node.Hi)
+	case node.Level == l+1:
+		f00, f01 := o.cofactors(node.Lo, l)
+		f10, f11 := o.cofactors(node.Hi, l)
+		r = o.nt.AddNode(l+1, o.nt.AddNode(l, f00, f10), o.nt.AddNode(l, f01, f11))
+	default:
+		lo, err := o.swap(node.Lo, l, memo)
+		if err != nil {
+			return NullNode, err
+		}
+		hi, err := o.swap(node.Hi, l, memo)
+		if err != nil {
+			return NullNode, err
+		}
+		r = o.nt.AddNode(node.Level, lo, hi)
+	}
+
+	memo[f] = r
+	return r, nil
+}
+
+// cofactors splits f, whose top level is at most l, into the solutions
+// without and with variable l
+func (o *familyOps) cofactors(f NodeID, l int) (without, with NodeID) {
+	node := o.node(f)
+	if node.Level == l {
+		return node.Lo, node.Hi
+	}
+	return f, ZeroNode
+}
+
+// InsertFree returns a copy of the ZDD with don't-care variables inserted.
+//
+// free lists the indices the new variables take in the result, which has
+// Variables()+len(free) variables. The existing variables keep their relative
+// order and are renumbered to fill the remaining indices. Each new variable
+// is free: every solution appears once without it and once with it. This
+// aligns a ZDD with another one over a larger, overlapping set of variables,
+// so that the two can be intersected. Registered groups are renumbered along
+// with their members.
+//
+// Returns ErrInvalidVariable if an index is outside the result's variables or
+// listed twice.
+func (z *ZDD) InsertFree(ctx context.Context, free []int) (*ZDD, error) {
+	vars := z.vars + len(free)
+	isFree := make([]bool, vars+1)
+	for _, v := range free {
+		if v < 1 || v > vars {
+			return nil, fmt.Errorf("%w: free variable %d outside 1..%d", ErrInvalidVariable, v, vars)
+		}
+		if isFree[v] {
+			return nil, fmt.Errorf("%w: free variable %d listed twice", ErrInvalidVariable, v)
+		}
+		isFree[v] = true
+	}
+
+	// existing variables take the remaining indices in order
+	target := make([]int, z.vars+1)
+	for v, to := 1, 1; v <= z.vars; v, to = v+1, to+1 {
+		for isFree[to] {
+			to++
+		}
+		target[v] = to
+	}
+
+	ops, roots, err := workspace(ctx, z)
+	if err != nil {
+		return nil, err
+	}
+	root, err := ops.relabel(roots[0], target, make(map[NodeID]NodeID))
+	if err != nil {
+		return nil, fmt.Errorf("insert free failed: %w", err)
+	}
+
+	// the power set of the free variables
+	chain := OneNode
+	for l := 1; l <= vars; l++ {
+		if isFree[l] {
+			chain = ops.nt.AddNode(l, chain, chain)
+		}
+	}
+	if root, err = ops.join(root, chain); err != nil {
+		return nil, fmt.Errorf("insert free failed: %w", err)
+	}
+
+	result, err := z.derive(vars, ops.nt, root)
+	if err != nil {
+		return nil, err
+	}
+
+	result.groups = nil
+	if len(z.groups) > 0 {
+		result.groups = make(map[string][]int, len(z.groups))
+		for name, members := range z.groups {
+			renamed := make([]int, len(members))
+			for i, v := range members {
+				renamed[i] = target[v]
+			}
+			result.groups[name] = renamed
+		}
+	}
+	return result, nil
+}
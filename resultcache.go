@@ -0,0 +1,334 @@
+package gozdd
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// resultKey identifies an evaluator query up to its cost vector
+type resultKey struct {
+	kind  string
+	k     int
+	costs uint64
+}
+
+// resultEntry stores one memoized evaluator result
+type resultEntry struct {
+	costs []float64
+	value interface{}
+}
+
+// resultCache memoizes built-in evaluator results for one diagram
+type resultCache struct {
+	mu sync.Mutex
+
+	// root and nodes identify the diagram the entries were computed for
+	root  NodeID
+	nodes *NodeTable
+
+	entries map[resultKey][]resultEntry
+	hits    int64
+	misses  int64
+
+	// tables holds per-node intermediate values shared between evaluators,
+	// such as subtree counts, by name
+	tables map[string]interface{}
+}
+
+// evaluate returns a cached result for the query or runs the evaluator,
+// reporting whether the result was cached.
+//
+// Evaluators without a cache key are run directly.
+func (c *resultCache) evaluate(ctx context.Context, z *ZDD, evaluator Evaluator) (interface{}, bool, error) {
+	key, costs, ok := cacheKey(evaluator)
+	if !ok {
+		value, err := evaluator.Evaluate(ctx, z)
+		return value, false, err
+	}
+
+	if value, found := c.lookup(z, key, costs); found {
+		return cloneResult(value), true, nil
+	}
+
+	value, err := evaluator.Evaluate(ctx, z)
+	if err != nil {
+		return value, false, err
+	}
+
+	c.store(z, key, costs, value)
+	return cloneResult(value), false, nil
+}
+
+// lookup finds a stored result, resetting the cache if the diagram changed
+func (c *resultCache) lookup(z *ZDD, key resultKey, costs []float64) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sync(z)
+	for _, e := range c.entries[key] {
+		if equalCosts(e.costs, costs) {
+			c.hits++
+			return e.value, true
+		}
+	}
+	c.misses++
+	return nil, false
+}
+
+// store records a result for the query
+func (c *resultCache) store(z *ZDD, key resultKey, costs []float64, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sync(z)
+	saved := append([]float64(nil), costs...)
+	c.entries[key] = append(c.entries[key], resultEntry{costs: saved, value: value})
+}
+
+// sync drops all entries if they were computed for another diagram.
+// The caller must hold c.mu.
+func (c *resultCache) sync(z *ZDD) {
+	if c.entries == nil || c.root != z.root || c.nodes != z.nodes {
+		c.entries = make(map[resultKey][]resultEntry)
+		c.tables = make(map[string]interface{})
+		c.root = z.root
+		c.nodes = z.nodes
+	}
+}
+
+// nodeTable returns the per-node values computed by compute.
+//
+// With the result cache enabled the table is computed once per diagram and
+// shared by every evaluator asking for the same name, so counting, then
+// k-best, then marginals walk the diagram for subtree counts only once.
+// Callers must not modify the table.
+func nodeTable[T any](z *ZDD, name string, compute func() (map[NodeID]T, error)) (map[NodeID]T, error) {
+	if !z.config.ResultCache {
+		return compute()
+	}
+
+	c := &z.results
+	root, nodes := z.root, z.nodes
+	c.mu.Lock()
+	c.sync(z)
+	if table, ok := c.tables[name]; ok {
+		c.mu.Unlock()
+		return table.(map[NodeID]T), nil
+	}
+	c.mu.Unlock()
+
+	// compute without the lock; concurrent misses compute the same table
+	table, err := compute()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sync(z)
+	if c.root == root && c.nodes == nodes {
+		c.tables[name] = table
+	}
+	return table, nil
+}
+
+// InvalidateResults clears the evaluator result cache of the ZDD.
+//
+// Rebuilding the ZDD invalidates the cache automatically; explicit
+// invalidation is needed only when results must be recomputed anyway, for
+// example to measure evaluation time.
+func (z *ZDD) InvalidateResults() {
+	z.results.mu.Lock()
+	defer z.results.mu.Unlock()
+	z.results.entries = nil
+	z.results.tables = nil
+}
+
+// ResultCacheStats returns the number of evaluator queries answered from the
+// result cache and the number that had to be computed.
+func (z *ZDD) ResultCacheStats() (hits, misses int64) {
+	z.results.mu.Lock()
+	defer z.results.mu.Unlock()
+	return z.results.hits, z.results.misses
+}
+
+// cacheKey derives the cache key of a built-in evaluator.
+// Cost models other than CostMatrix cannot be compared, so queries using
+// them are not cached.
+func cacheKey(evaluator Evaluator) (resultKey, []float64, bool) {
+	switch e := evaluator.(type) {
+	case CountEvaluator:
+		return resultKey{kind: "count" + skipped(e.Skipped)}, nil, true
+	case BigCountEvaluator:
+		return resultKey{kind: "count-big" + skipped(e.Skipped)}, nil, true
+	case CostEvaluator:
+		costs, sparse, ok := modelParams(e.Costs, e.SparseCosts, e.Model)
+		if !ok {
+			break
+		}
+		return resultKey{kind: "cost" + sparse + sense(e.Maximize), costs: hashCosts(costs)}, costs, true
+	case KBestEvaluator:
+		costs, sparse, ok := modelParams(e.Costs, e.SparseCosts, e.Model)
+		if !ok {
+			break
+		}
+		kind := "kbest" + sparse + sense(e.Maximize)
+		if e.Distinct {
+			kind += "-distinct"
+		}
+		if e.Fixed != nil {
+			costs = appendFixed(costs, e.Fixed)
+			kind += "-fixed"
+		}
+		return resultKey{kind: kind, k: e.K, costs: hashCosts(costs)}, costs, true
+	}
+	return resultKey{}, nil, false
+}
+
+// sense tags the keys of maximizing queries
+func sense(maximize bool) string {
+	if maximize {
+		return "-max"
+	}
+	return ""
+}
+
+// skipped tags the keys of counts with free skipped variables
+func skipped(semantics SkipSemantics) string {
+	if semantics == SkippedFree {
+		return "-free"
+	}
+	return ""
+}
+
+// modelParams flattens the cost parameters of an evaluator for keying, like
+// costParams, and reports false for cost models that cannot be compared.
+//
+// A CostMatrix is flattened row by row and tagged so it never matches a
+// vector.
+func modelParams(dense []float64, sparse map[int]float64, model CostModel) ([]float64, string, bool) {
+	switch m := model.(type) {
+	case nil:
+		costs, tag := costParams(dense, sparse)
+		return costs, tag, true
+	case CostMatrix:
+		flat := make([]float64, 0, 2*len(m))
+		for _, row := range m {
+			flat = append(flat, row[0], row[1])
+		}
+		return flat, "-matrix", true
+	}
+	return nil, "", false
+}
+
+// costParams flattens the cost parameters of an evaluator for keying.
+//
+// Sparse costs become sorted (variable, cost) pairs and are tagged so they
+// never match a dense vector. Evaluators reject setting both, so such
+// queries are never stored.
+func costParams(dense []float64, sparse map[int]float64) ([]float64, string) {
+	if sparse == nil {
+		return dense, ""
+	}
+
+	vars := make([]int, 0, len(sparse))
+	for v := range sparse {
+		vars = append(vars, v)
+	}
+	sort.Ints(vars)
+
+	flat := make([]float64, 0, 2*len(vars))
+	for _, v := range vars {
+		flat = append(flat, float64(v), sparse[v])
+	}
+	return flat, "-sparse"
+}
+
+// appendFixed appends fixed assignments to a copy of the flattened costs as
+// sorted (variable, 0 or 1) pairs
+func appendFixed(costs []float64, fixed map[int]bool) []float64 {
+	vars := make([]int, 0, len(fixed))
+	for v := range fixed {
+		vars = append(vars, v)
+	}
+	sort.Ints(vars)
+
+	flat := append(make([]float64, 0, len(costs)+2*len(vars)), costs...)
+	flat = append(flat, float64(len(vars)))
+	for _, v := range vars {
+		in := 0.0
+		if fixed[v] {
+			in = 1
+		}
+		flat = append(flat, float64(v), in)
+	}
+	return flat
+}
+
+// hashCosts hashes the bit patterns of a cost vector
+func hashCosts(costs []float64) uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	for _, c := range costs {
+		bits := math.Float64bits(c)
+		for i := range buf {
+			buf[i] = byte(bits >> (8 * i))
+		}
+		h.Write(buf[:])
+	}
+	return h.Sum64()
+}
+
+// equalCosts compares cost vectors by bit pattern, so NaN entries match
+func equalCosts(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Float64bits(a[i]) != math.Float64bits(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// cloneResult copies the solutions inside an evaluator result so callers
+// cannot modify cached values
+func cloneResult(value interface{}) interface{} {
+	switch r := value.(type) {
+	case OptimalResult:
+		r.Solution = cloneSolution(r.Solution)
+		return r
+	case KBestResult:
+		solutions := make([]*Solution, len(r.Solutions))
+		for i, s := range r.Solutions {
+			solutions[i] = cloneSolution(s)
+		}
+		r.Solutions = solutions
+		return r
+	case *big.Int:
+		return new(big.Int).Set(r)
+	}
+	return value
+}
+
+// cloneSolution returns a copy of s with its own variables and metadata
+func cloneSolution(s *Solution) *Solution {
+	if s == nil {
+		return nil
+	}
+
+	metadata := make(map[string]interface{}, len(s.Metadata))
+	for k, v := range s.Metadata {
+		metadata[k] = v
+	}
+	return &Solution{
+		Variables: append([]int{}, s.Variables...),
+		Cost:      s.Cost,
+		Metadata:  metadata,
+	}
+}
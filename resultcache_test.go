@@ -0,0 +1,112 @@
+package gozdd
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// tableIDs identifies the shared per-node tables of z by name, so a table
+// computed again shows up as a new identity
+func tableIDs(z *ZDD) map[string]uintptr {
+	z.results.mu.Lock()
+	defer z.results.mu.Unlock()
+
+	ids := make(map[string]uintptr)
+	for name, table := range z.results.tables {
+		ids[name] = reflect.ValueOf(table).Pointer()
+	}
+	return ids
+}
+
+func tableNames(ids map[string]uintptr) []string {
+	names := make([]string, 0, len(ids))
+	for name := range ids {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// atMost builds the non-empty sets of at most max of 6 variables
+func atMost(t *testing.T, z *ZDD, max int) {
+	t.Helper()
+	spec := NewCompositeSpec(6, BasicState{Counters: []int{0}}, CountConstraint{Min: 0, Max: max})
+	if err := z.Build(context.Background(), spec); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResultCacheSharesNodeTables(t *testing.T) {
+	ctx := context.Background()
+	z := NewZDD(6, WithResultCache())
+	atMost(t, z, 2)
+	costs := []float64{0, 1, 2, 3, 4, 5, 6}
+
+	count, err := z.Count(ctx)
+	if err != nil || count != 21 {
+		t.Fatalf("Count = %d, %v, want 21", count, err)
+	}
+	first := tableIDs(z)
+	if names := tableNames(first); !reflect.DeepEqual(names, []string{"count"}) {
+		t.Fatalf("after Count the tables are %v, want [count]", names)
+	}
+
+	// k-best reuses the subtree counts; marginals and log counts share
+	// the scaled ones
+	if _, err := z.FindKBest(ctx, 3, costs); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := z.Marginals(ctx); err != nil {
+		t.Fatal(err)
+	}
+	scaled := tableIDs(z)
+	if _, err := z.LogCount(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := z.Marginals(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := z.CountBig(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	after := tableIDs(z)
+	if names := tableNames(after); !reflect.DeepEqual(names, []string{"count", "count-big", "count-scaled"}) {
+		t.Fatalf("tables %v, want [count count-big count-scaled]", names)
+	}
+	if after["count"] != first["count"] || after["count-scaled"] != scaled["count-scaled"] {
+		t.Fatal("a shared table was computed twice")
+	}
+
+	z.InvalidateResults()
+	if names := tableNames(tableIDs(z)); len(names) != 0 {
+		t.Fatalf("InvalidateResults kept tables %v", names)
+	}
+	if count, err := z.Count(ctx); err != nil || count != 21 {
+		t.Fatalf("Count after InvalidateResults = %d, %v, want 21", count, err)
+	}
+
+	// a rebuild drops the tables of the old diagram
+	atMost(t, z, 1)
+	if count, err := z.Count(ctx); err != nil || count != 6 {
+		t.Fatalf("Count after a rebuild = %d, %v, want 6", count, err)
+	}
+	if names := tableNames(tableIDs(z)); !reflect.DeepEqual(names, []string{"count"}) {
+		t.Fatalf("after a rebuild the tables are %v, want [count]", names)
+	}
+}
+
+func TestCountSkippedFreeTable(t *testing.T) {
+	ctx := context.Background()
+	z := NewZDD(6, WithResultCache())
+	atMost(t, z, 2)
+
+	if _, err := EvaluateZDD(ctx, z, CountEvaluator{Skipped: SkippedFree}); err != nil {
+		t.Fatal(err)
+	}
+	if names := tableNames(tableIDs(z)); !reflect.DeepEqual(names, []string{"count-free"}) {
+		t.Fatalf("a free count computed tables %v, want [count-free]", names)
+	}
+}
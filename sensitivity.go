@@ -0,0 +1,174 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// SensitivityEvaluator reports, for every variable, the optimal cost when
+// the variable is forced into or out of the solution.
+//
+// The difference to the unrestricted optimum is the reduced cost of the
+// decision: how much worse the best plan gets if the variable must be
+// selected, or must be left out. Instead of 2·Variables() optimizations, a
+// bottom-up pass finds the cheapest completion below every node and a
+// top-down pass the cheapest path from the root to it. The cheapest solution
+// selecting v then runs through a Hi arc of a level-v node, and the cheapest
+// one without v through a Lo arc of such a node or an arc skipping level v.
+// Costs are given as for CostEvaluator. The result is a SensitivityResult.
+type SensitivityEvaluator struct {
+	// Costs specifies the cost of selecting each variable (1-based indexing)
+	Costs []float64
+
+	// SparseCosts maps variables to their selection cost; variables without
+	// an entry cost 0
+	SparseCosts map[int]float64
+
+	// Model computes costs per level and branch
+	Model CostModel
+}
+
+// SensitivityResult holds the optimal costs under single-variable
+// restrictions.
+type SensitivityResult struct {
+	// Optimal is the unrestricted optimal cost; Found is false when the
+	// family has no solutions
+	Optimal float64
+	Found   bool
+
+	// ForcedIn[v] and ForcedOut[v] are the optimal costs of the solutions
+	// that select v, or leave it out, for v in 1..Variables(); index 0 is
+	// unused. +Inf marks a restriction no solution meets.
+	ForcedIn  []float64
+	ForcedOut []float64
+}
+
+// skipArc is an arc skipping levels lo..hi, with the cheapest solution
+// using it
+type skipArc struct {
+	lo, hi int
+	cost   float64
+}
+
+// Evaluate computes the restricted optimal costs
+func (e SensitivityEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
+	costs, base, err := resolveCosts(zdd.vars, e.Costs, e.SparseCosts, e.Model)
+	if err != nil {
+		return SensitivityResult{}, err
+	}
+
+	result := SensitivityResult{
+		Optimal:   math.Inf(1),
+		ForcedIn:  make([]float64, zdd.vars+1),
+		ForcedOut: make([]float64, zdd.vars+1),
+	}
+	for v := range result.ForcedIn {
+		result.ForcedIn[v], result.ForcedOut[v] = math.Inf(1), math.Inf(1)
+	}
+	root := zdd.family()
+	if root == ZeroNode {
+		return result, nil
+	}
+
+	// below[n] is the cheapest completion below n
+	below := map[NodeID]float64{ZeroNode: math.Inf(1), OneNode: 0}
+	err = zdd.bottomUp(ctx, func(id NodeID, node Node) error {
+		below[id] = math.Min(below[node.Lo], below[node.Hi]+costs[node.Level])
+		return nil
+	})
+	if err != nil {
+		return SensitivityResult{}, fmt.Errorf("sensitivity evaluation failed: %w", err)
+	}
+
+	// parents have higher levels than their children, so visiting layers
+	// top-down finishes every node's prefix cost before its use
+	above := map[NodeID]float64{root: 0}
+	arcs := []skipArc{{lo: levelOf(zdd, root) + 1, hi: zdd.vars, cost: below[root]}}
+	for l := zdd.vars; l >= 1; l-- {
+		for _, id := range zdd.layer(l) {
+			node, err := zdd.GetNode(id)
+			if err != nil {
+				return SensitivityResult{}, err
+			}
+			prefix := above[id]
+			c := costs[node.Level]
+
+			relax(above, node.Lo, prefix)
+			relax(above, node.Hi, prefix+c)
+
+			lo, hi := prefix+below[node.Lo], prefix+c+below[node.Hi]
+			result.ForcedOut[node.Level] = math.Min(result.ForcedOut[node.Level], lo)
+			result.ForcedIn[node.Level] = math.Min(result.ForcedIn[node.Level], hi)
+			arcs = append(arcs,
+				skipArc{lo: levelOf(zdd, node.Lo) + 1, hi: node.Level - 1, cost: lo},
+				skipArc{lo: levelOf(zdd, node.Hi) + 1, hi: node.Level - 1, cost: hi})
+		}
+	}
+	paintSkips(arcs, result.ForcedOut)
+
+	result.Optimal, result.Found = below[root]+base, true
+	for v := 1; v <= zdd.vars; v++ {
+		result.ForcedIn[v] += base
+		result.ForcedOut[v] += base
+	}
+	return result, nil
+}
+
+// relax lowers the prefix cost of a node
+func relax(above map[NodeID]float64, id NodeID, cost float64) {
+	if old, ok := above[id]; !ok || cost < old {
+		above[id] = cost
+	}
+}
+
+// levelOf returns the level of a node, 0 for terminals
+func levelOf(zdd *ZDD, id NodeID) int {
+	node, _ := zdd.GetNode(id)
+	return node.Level
+}
+
+// paintSkips lowers out[l] to the cheapest arc skipping level l.
+//
+// Arcs are taken cheapest first and each level is settled by the first arc
+// covering it, jumping over settled levels, so the pass is near linear.
+func paintSkips(arcs []skipArc, out []float64) {
+	sort.Slice(arcs, func(i, j int) bool { return arcs[i].cost < arcs[j].cost })
+
+	// next[l] is the lowest unsettled level at or above l
+	next := make([]int, len(out)+1)
+	for l := range next {
+		next[l] = l
+	}
+	var find func(l int) int
+	find = func(l int) int {
+		for next[l] != l {
+			next[l] = next[next[l]]
+			l = next[l]
+		}
+		return l
+	}
+
+	for _, arc := range arcs {
+		if math.IsInf(arc.cost, 1) {
+			break
+		}
+		for l := find(arc.lo); l <= arc.hi; l = find(l) {
+			out[l] = math.Min(out[l], arc.cost)
+			next[l] = l + 1
+		}
+	}
+}
+
+// Sensitivity returns the optimal cost with each variable forced in and
+// forced out.
+//
+// This is a type-safe convenience method for SensitivityEvaluator.
+func (z *ZDD) Sensitivity(ctx context.Context, costs []float64) (SensitivityResult, error) {
+	result, err := EvaluateZDD(ctx, z, SensitivityEvaluator{Costs: costs})
+	if err != nil {
+		return SensitivityResult{}, err
+	}
+	return result.(SensitivityResult), nil
+}
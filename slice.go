@@ -0,0 +1,143 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// DiagramSlice is a band of consecutive levels extracted from a ZDD.
+//
+// The slice exposes the boundary interface of the band so that large models
+// built in stages can be analyzed or recomposed hierarchically.
+type DiagramSlice struct {
+	// Family holds the partial assignments to the sliced variables that occur
+	// on some root-to-1 path of the original ZDD. Variable fromLevel-toLevel+1
+	// of the family corresponds to fromLevel in the original, and variable 1
+	// to toLevel.
+	Family *ZDD
+
+	// Entries are the original nodes where paths enter the band, sorted by NodeID.
+	// An entry below toLevel means a path crosses the band selecting nothing.
+	Entries []NodeID
+
+	// Exits are the original nodes below toLevel that paths reach when leaving
+	// the band, including OneNode, sorted by NodeID. ZeroNode is never an exit.
+	Exits []NodeID
+
+	// Offset is the amount subtracted from original levels to obtain slice
+	// levels, which is toLevel-1.
+	Offset int
+}
+
+// Slice extracts the layers fromLevel down to toLevel as a standalone family.
+//
+// Since construction proceeds from the highest level to the lowest,
+// fromLevel is the upper boundary and toLevel the lower boundary, and both
+// are inclusive. The resulting family has fromLevel-toLevel+1 variables.
+//
+// Returns ErrInvalidLevel unless 1 <= toLevel <= fromLevel <= Variables().
+func (z *ZDD) Slice(ctx context.Context, fromLevel, toLevel int) (*DiagramSlice, error) {
+	if toLevel < 1 || fromLevel < toLevel || fromLevel > z.vars {
+		return nil, fmt.Errorf("%w: slice %d..%d of %d variables", ErrInvalidLevel, fromLevel, toLevel, z.vars)
+	}
+
+	entries, err := z.sliceEntries(ctx, fromLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	ops := newFamilyOps(ctx, NewNodeTable())
+	exits := make(map[NodeID]bool)
+	band := map[NodeID]NodeID{ZeroNode: ZeroNode}
+
+	// copyOf returns the copy of a node reached inside the band. The band is
+	// copied bottom-up, so a node not copied yet lies below toLevel: it is an
+	// exit, where the partial assignment is complete.
+	copyOf := func(id NodeID) NodeID {
+		if mapped, ok := band[id]; ok {
+			return mapped
+		}
+		exits[id] = true
+		band[id] = OneNode
+		return OneNode
+	}
+
+	for l := toLevel; l <= fromLevel; l++ {
+		for _, id := range z.layer(l) {
+			if err := ctx.Err(); err != nil {
+				return nil, fmt.Errorf("slice failed: %w", err)
+			}
+			node, err := z.GetNode(id)
+			if err != nil {
+				return nil, fmt.Errorf("slice failed: %w", err)
+			}
+			band[id] = ops.nt.AddNode(l-toLevel+1, copyOf(node.Lo), copyOf(node.Hi))
+		}
+	}
+
+	root := ZeroNode
+	for _, entry := range entries {
+		var err error
+		if root, err = ops.union(root, copyOf(entry)); err != nil {
+			return nil, fmt.Errorf("slice failed: %w", err)
+		}
+	}
+
+	family, err := z.derive(fromLevel-toLevel+1, ops.nt, root)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DiagramSlice{
+		Family:  family,
+		Entries: entries,
+		Exits:   sortedNodeIDs(exits),
+		Offset:  toLevel - 1,
+	}, nil
+}
+
+// sliceEntries finds the nodes at or below fromLevel reached from above
+func (z *ZDD) sliceEntries(ctx context.Context, fromLevel int) ([]NodeID, error) {
+	entries := make(map[NodeID]bool)
+	visited := make(map[NodeID]bool)
+
+	stack := []NodeID{z.family()}
+	for len(stack) > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == ZeroNode || visited[id] {
+			continue
+		}
+		visited[id] = true
+
+		node, err := z.GetNode(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if node.Level <= fromLevel {
+			entries[id] = true
+			continue
+		}
+		stack = append(stack, node.Lo, node.Hi)
+	}
+
+	return sortedNodeIDs(entries), nil
+}
+
+// sortedNodeIDs returns the keys of a node set in ascending order
+func sortedNodeIDs(set map[NodeID]bool) []NodeID {
+	ids := make([]NodeID, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
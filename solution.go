@@ -3,7 +3,10 @@ package gozdd
 import (
 	"context"
 	"fmt"
+	"math"
+	"math/big"
 	"sort"
+	"time"
 )
 
 // Solution represents a feasible solution extracted from a ZDD.
@@ -22,6 +25,26 @@ type Solution struct {
 	Metadata map[string]interface{}
 }
 
+// solutionLess orders solutions by cost, breaking ties with colexLess
+func solutionLess(a, b *Solution) bool {
+	if a.Cost != b.Cost {
+		return a.Cost < b.Cost
+	}
+	return colexLess(a.Variables, b.Variables)
+}
+
+// colexLess compares ascending variable lists by their highest differing
+// variable; the list without it sorts first
+func colexLess(a, b []int) bool {
+	i, j := len(a)-1, len(b)-1
+	for ; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if a[i] != b[j] {
+			return a[i] < b[j]
+		}
+	}
+	return i < j
+}
+
 // Evaluator defines the interface for ZDD evaluation algorithms.
 //
 // Evaluators traverse the ZDD structure to extract information such as:
@@ -46,8 +69,33 @@ type Evaluator interface {
 // CountEvaluator counts the total number of solutions in the ZDD.
 //
 // This evaluator computes the cardinality of the solution set represented
-// by the ZDD using efficient bottom-up traversal.
-type CountEvaluator struct{}
+// by the ZDD using efficient bottom-up traversal. Nodes are visited level by
+// level rather than recursively, so diagrams with hundreds of thousands of
+// levels are fine. Counts that exceed the int64 range fail with
+// ErrCountOverflow; BigCountEvaluator handles them.
+type CountEvaluator struct {
+	// Skipped selects how variables skipped by an arc are counted; by
+	// default they are unselected
+	Skipped SkipSemantics
+}
+
+// SkipSemantics selects how counting treats the variables that an arc of the
+// diagram skips.
+//
+// A ZDD reads skipped variables as unselected, which is what zero
+// suppression means. Models that use SkipState to jump over variables they
+// do not constrain mean the opposite: any value is allowed, so each skipped
+// variable doubles the solutions through the arc.
+type SkipSemantics int
+
+const (
+	// SkippedUnselected counts skipped variables as not selected
+	SkippedUnselected SkipSemantics = iota
+
+	// SkippedFree counts skipped variables as free, multiplying the
+	// solutions through an arc by 2^gap for a gap of skipped levels
+	SkippedFree
+)
 
 // Evaluate counts all solutions in the ZDD
 func (e CountEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
@@ -55,73 +103,160 @@ func (e CountEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, er
 		return int64(0), nil
 	}
 	
-	// Memoization table for dynamic programming
-	memo := make(map[NodeID]int64)
-	
-	count, err := e.countRecursive(ctx, zdd, zdd.root, memo)
+	var counts map[NodeID]int64
+	var err error
+	if e.Skipped == SkippedFree {
+		counts, err = zdd.freeCounts(ctx)
+	} else {
+		counts, err = zdd.subtreeCounts(ctx)
+	}
 	if err != nil {
 		return int64(0), fmt.Errorf("count evaluation failed: %w", err)
 	}
 	
+	count := counts[zdd.root]
+	if e.Skipped == SkippedFree {
+		count = shiftCount(count, zdd.vars-levelOf(zdd, zdd.root))
+	}
+	if count < 0 {
+		return int64(0), fmt.Errorf("count evaluation failed: %w", ErrCountOverflow)
+	}
 	return count, nil
 }
 
-// countRecursive performs recursive solution counting with memoization
-func (e CountEvaluator) countRecursive(ctx context.Context, zdd *ZDD, nodeID NodeID, memo map[NodeID]int64) (int64, error) {
-	// Check for cancellation
-	select {
-	case <-ctx.Done():
-		return 0, ctx.Err()
-	default:
-	}
-	
-	// Check memoization
-	if count, exists := memo[nodeID]; exists {
-		return count, nil
-	}
-	
-	// Handle terminal nodes
-	if nodeID == ZeroNode {
-		memo[nodeID] = 0
-		return 0, nil
-	}
-	if nodeID == OneNode {
-		memo[nodeID] = 1
-		return 1, nil
+// subtreeCounts counts the solutions below every reachable node, bottom-up
+// in level order so deep diagrams need no recursion. Counts beyond the int64
+// range are -1.
+func (z *ZDD) subtreeCounts(ctx context.Context) (map[NodeID]int64, error) {
+	return nodeTable(z, "count", func() (map[NodeID]int64, error) {
+		counts := map[NodeID]int64{ZeroNode: 0, OneNode: 1}
+		err := z.bottomUp(ctx, func(id NodeID, node Node) error {
+			lo, hi := counts[node.Lo], counts[node.Hi]
+			if lo < 0 || hi < 0 || lo > math.MaxInt64-hi {
+				counts[id] = -1
+			} else {
+				counts[id] = lo + hi
+			}
+			return nil
+		})
+		return counts, err
+	})
+}
+
+// freeCounts counts the assignments to variables 1..level of every reachable
+// node that lead to the 1-terminal, with skipped variables free. Counts
+// beyond the int64 range are -1.
+func (z *ZDD) freeCounts(ctx context.Context) (map[NodeID]int64, error) {
+	return nodeTable(z, "count-free", func() (map[NodeID]int64, error) {
+		counts := map[NodeID]int64{ZeroNode: 0, OneNode: 1}
+		err := z.bottomUp(ctx, func(id NodeID, node Node) error {
+			lo := shiftCount(counts[node.Lo], node.Level-1-levelOf(z, node.Lo))
+			hi := shiftCount(counts[node.Hi], node.Level-1-levelOf(z, node.Hi))
+			if lo < 0 || hi < 0 || lo > math.MaxInt64-hi {
+				counts[id] = -1
+			} else {
+				counts[id] = lo + hi
+			}
+			return nil
+		})
+		return counts, err
+	})
+}
+
+// shiftCount multiplies a count by 2^levels, returning -1 on overflow
+func shiftCount(count int64, levels int) int64 {
+	if count <= 0 || levels == 0 {
+		return count
 	}
-	
-	// Get node structure
-	node, err := zdd.GetNode(nodeID)
-	if err != nil {
-		return 0, err
+	if levels >= 63 || count > math.MaxInt64>>levels {
+		return -1
 	}
-	
-	// Recursively count solutions in both subtrees
-	loCount, err := e.countRecursive(ctx, zdd, node.Lo, memo)
-	if err != nil {
-		return 0, err
+	return count << levels
+}
+
+// BigCountEvaluator counts the solutions in the ZDD exactly, with no upper
+// limit.
+//
+// It is CountEvaluator with math/big arithmetic, for diagrams over many
+// variables whose counts exceed the int64 range. The result is a *big.Int.
+type BigCountEvaluator struct {
+	// Skipped selects how variables skipped by an arc are counted; by
+	// default they are unselected
+	Skipped SkipSemantics
+}
+
+// Evaluate counts all solutions in the ZDD
+func (e BigCountEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, error) {
+	if e.Skipped == SkippedFree {
+		counts, err := zdd.bigFreeCounts(ctx)
+		if err != nil {
+			return new(big.Int), fmt.Errorf("count evaluation failed: %w", err)
+		}
+		root := zdd.family()
+		return new(big.Int).Lsh(counts[root], uint(zdd.vars-levelOf(zdd, root))), nil
 	}
-	
-	hiCount, err := e.countRecursive(ctx, zdd, node.Hi, memo)
+
+	counts, err := zdd.bigCounts(ctx)
 	if err != nil {
-		return 0, err
+		return new(big.Int), fmt.Errorf("count evaluation failed: %w", err)
 	}
 	
-	// Total count is sum of both subtrees
-	totalCount := loCount + hiCount
-	memo[nodeID] = totalCount
-	
-	return totalCount, nil
+	return new(big.Int).Set(counts[zdd.family()]), nil
+}
+
+// bigCounts counts the solutions below every reachable node exactly
+func (z *ZDD) bigCounts(ctx context.Context) (map[NodeID]*big.Int, error) {
+	return nodeTable(z, "count-big", func() (map[NodeID]*big.Int, error) {
+		counts := map[NodeID]*big.Int{
+			ZeroNode: big.NewInt(0),
+			OneNode:  big.NewInt(1),
+		}
+		err := z.bottomUp(ctx, func(id NodeID, node Node) error {
+			counts[id] = new(big.Int).Add(counts[node.Lo], counts[node.Hi])
+			return nil
+		})
+		return counts, err
+	})
+}
+
+// bigFreeCounts is freeCounts with exact counts
+func (z *ZDD) bigFreeCounts(ctx context.Context) (map[NodeID]*big.Int, error) {
+	return nodeTable(z, "count-big-free", func() (map[NodeID]*big.Int, error) {
+		counts := map[NodeID]*big.Int{
+			ZeroNode: big.NewInt(0),
+			OneNode:  big.NewInt(1),
+		}
+		err := z.bottomUp(ctx, func(id NodeID, node Node) error {
+			lo := new(big.Int).Lsh(counts[node.Lo], uint(node.Level-1-levelOf(z, node.Lo)))
+			hi := new(big.Int).Lsh(counts[node.Hi], uint(node.Level-1-levelOf(z, node.Hi)))
+			counts[id] = lo.Add(lo, hi)
+			return nil
+		})
+		return counts, err
+	})
 }
 
 // CostEvaluator finds the optimal solution with minimum cost.
 //
 // This evaluator requires cost information for each variable and computes
-// the solution with the lowest total cost using dynamic programming.
+// the solution with the lowest total cost using dynamic programming. Like
+// CountEvaluator it visits nodes level by level, without recursion.
 type CostEvaluator struct {
 	// Costs specifies the cost of selecting each variable (1-based indexing)
 	// Costs[0] is ignored, Costs[i] is the cost of selecting variable i
 	Costs []float64
+	
+	// SparseCosts maps variables to their selection cost; variables without
+	// an entry cost 0. Use instead of Costs when few variables have costs.
+	SparseCosts map[int]float64
+	
+	// Model computes costs per level and branch. Use instead of Costs when
+	// not selecting a variable has a cost too, or costs follow a rule.
+	Model CostModel
+	
+	// Maximize finds the solution with the highest cost instead, for
+	// objectives such as total value; costs keep their sign
+	Maximize bool
 }
 
 // OptimalResult represents the result of optimal solution evaluation
@@ -137,118 +272,107 @@ func (e CostEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, err
 		return OptimalResult{Found: false}, nil
 	}
 	
-	if len(e.Costs) <= zdd.vars {
-		return OptimalResult{Found: false}, fmt.Errorf("insufficient cost data: need %d costs, got %d", zdd.vars, len(e.Costs)-1)
-	}
-	
-	// Memoization for optimal costs and solutions
-	costMemo := make(map[NodeID]float64)
-	solutionMemo := make(map[NodeID][]int)
-	
-	cost, solution, err := e.optimalRecursive(ctx, zdd, zdd.root, costMemo, solutionMemo)
+	costs, base, err := resolveCosts(zdd.vars, e.Costs, e.SparseCosts, e.Model)
 	if err != nil {
-		return OptimalResult{Found: false}, fmt.Errorf("optimal evaluation failed: %w", err)
+		return OptimalResult{Found: false}, err
 	}
-	
-	if len(solution) == 0 && cost == 0 && zdd.root == ZeroNode {
+	if zdd.root == ZeroNode {
 		return OptimalResult{Found: false}, nil
 	}
-	
-	result := &Solution{
-		Variables: solution,
-		Cost:      cost,
-		Metadata:  make(map[string]interface{}),
-	}
-	
-	return OptimalResult{Solution: result, Cost: cost, Found: true}, nil
-}
-
-// optimalRecursive finds optimal solution recursively with memoization
-func (e CostEvaluator) optimalRecursive(ctx context.Context, zdd *ZDD, nodeID NodeID, costMemo map[NodeID]float64, solutionMemo map[NodeID][]int) (float64, []int, error) {
-	// Check for cancellation
-	select {
-	case <-ctx.Done():
-		return 0, nil, ctx.Err()
-	default:
+	if e.Maximize {
+		costs, base = negateCosts(costs), negateCost(base)
 	}
+	e.Costs = costs
 	
-	// Check memoization
-	if cost, exists := costMemo[nodeID]; exists {
-		return cost, solutionMemo[nodeID], nil
-	}
-	
-	// Handle terminal nodes
-	if nodeID == ZeroNode {
-		costMemo[nodeID] = float64(1e9) // Infeasible (high cost)
-		solutionMemo[nodeID] = nil
-		return float64(1e9), nil, nil
-	}
-	if nodeID == OneNode {
-		costMemo[nodeID] = 0
-		solutionMemo[nodeID] = []int{}
-		return 0, []int{}, nil
-	}
-	
-	// Get node structure
-	node, err := zdd.GetNode(nodeID)
+	// Minimum completion cost of every node, bottom-up in level order;
+	// infeasible completions are never preferred, however large real costs are
+	best := map[NodeID]float64{ZeroNode: math.Inf(1), OneNode: 0}
+	err = zdd.bottomUp(ctx, func(id NodeID, node Node) error {
+		best[id] = math.Min(best[node.Lo], best[node.Hi]+e.Costs[node.Level])
+		return nil
+	})
 	if err != nil {
-		return 0, nil, err
+		return OptimalResult{Found: false}, fmt.Errorf("optimal evaluation failed: %w", err)
 	}
 	
-	// Evaluate both subtrees
-	loCost, loSolution, err := e.optimalRecursive(ctx, zdd, node.Lo, costMemo, solutionMemo)
-	if err != nil {
-		return 0, nil, err
+	// Follow the optimal arcs from the root, preferring Lo on ties
+	cost := best[zdd.root]
+	solution := []int{}
+	for id := zdd.root; id != OneNode; {
+		node, err := zdd.GetNode(id)
+		if err != nil {
+			return OptimalResult{Found: false}, err
+		}
+		if best[node.Lo] <= best[node.Hi]+e.Costs[node.Level] {
+			id = node.Lo
+		} else {
+			solution = append(solution, node.Level)
+			id = node.Hi
+		}
 	}
-	
-	hiCost, hiSolution, err := e.optimalRecursive(ctx, zdd, node.Hi, costMemo, solutionMemo)
-	if err != nil {
-		return 0, nil, err
+	for i, j := 0, len(solution)-1; i < j; i, j = i+1, j-1 {
+		solution[i], solution[j] = solution[j], solution[i]
 	}
 	
-	// Add variable cost to hi-arc path
-	if node.Level > 0 && node.Level < len(e.Costs) {
-		hiCost += e.Costs[node.Level]
+	cost += base
+	if e.Maximize {
+		cost = negateCost(cost)
 	}
 	
-	// Choose the better option
-	var bestCost float64
-	var bestSolution []int
-	
-	if loCost <= hiCost {
-		bestCost = loCost
-		bestSolution = make([]int, len(loSolution))
-		copy(bestSolution, loSolution)
-	} else {
-		bestCost = hiCost
-		bestSolution = make([]int, len(hiSolution)+1)
-		copy(bestSolution, hiSolution)
-		bestSolution[len(hiSolution)] = node.Level // Add current variable
+	result := &Solution{
+		Variables: solution,
+		Cost:      cost,
+		Metadata:  make(map[string]interface{}),
 	}
 	
-	// Memoize result
-	costMemo[nodeID] = bestCost
-	solutionMemo[nodeID] = bestSolution
-	
-	return bestCost, bestSolution, nil
+	return OptimalResult{Solution: result, Cost: cost, Found: true}, nil
 }
 
 // KBestEvaluator finds the k best solutions with lowest costs.
 //
-// This evaluator uses a priority queue approach to efficiently extract
-// the top k solutions without enumerating all solutions.
+// This evaluator keeps the k best completions of every node, merging the
+// sorted lists of its two children bottom-up like a k-shortest-path search
+// over the diagram's DAG. It takes O(Size()·k) time and memory, independent
+// of the number of solutions, and never enumerates the family.
 type KBestEvaluator struct {
 	// K is the number of best solutions to find
 	K int
 	
 	// Costs specifies the cost of selecting each variable (1-based indexing)
 	Costs []float64
+	
+	// SparseCosts maps variables to their selection cost; variables without
+	// an entry cost 0. Use instead of Costs when few variables have costs.
+	SparseCosts map[int]float64
+	
+	// Model computes costs per level and branch. Use instead of Costs when
+	// not selecting a variable has a cost too, or costs follow a rule.
+	Model CostModel
+	
+	// Maximize finds the k solutions with the highest costs instead, in
+	// descending cost order; costs keep their sign
+	Maximize bool
+	
+	// Distinct returns one solution for each of the k best distinct costs
+	// instead of k solutions, so ties no longer crowd out worse cost levels.
+	// Each level is represented by its first solution in the stable order.
+	Distinct bool
+	
+	// Fixed forces variables in (true) or out (false) of every returned
+	// solution, as in "the best plans that include variable 2". The
+	// restriction is applied while merging, so the diagram is not rebuilt.
+	Fixed map[int]bool
+	
+	// required[l] counts the variables forced in among 1..l
+	required []int
 }
 
 // KBestResult represents the result of k-best evaluation
 type KBestResult struct {
 	Solutions []*Solution
-	Count     int
+	
+	// Count is the total number of solutions, saturated at math.MaxInt
+	Count int
 }
 
 // Evaluate finds the k best solutions with lowest costs
@@ -257,90 +381,210 @@ func (e KBestEvaluator) Evaluate(ctx context.Context, zdd *ZDD) (interface{}, er
 		return KBestResult{Solutions: []*Solution{}, Count: 0}, nil
 	}
 	
-	if len(e.Costs) <= zdd.vars {
-		return KBestResult{}, fmt.Errorf("insufficient cost data: need %d costs, got %d", zdd.vars, len(e.Costs)-1)
+	costs, base, err := resolveCosts(zdd.vars, e.Costs, e.SparseCosts, e.Model)
+	if err != nil {
+		return KBestResult{}, err
+	}
+	if e.Maximize {
+		costs, base = negateCosts(costs), negateCost(base)
 	}
+	e.Costs = costs
 	
-	// Use a simple approach: enumerate solutions and sort by cost
-	// For large k, more sophisticated algorithms would be needed
-	solutions, err := e.enumerateSolutions(ctx, zdd, zdd.root, []int{}, 0)
+	e.required = make([]int, zdd.vars+1)
+	for v, in := range e.Fixed {
+		if v < 1 || v > zdd.vars {
+			return KBestResult{}, fmt.Errorf("%w: fixed variable %d", ErrInvalidVariable, v)
+		}
+		if in {
+			e.required[v] = 1
+		}
+	}
+	for l := 1; l <= zdd.vars; l++ {
+		e.required[l] += e.required[l-1]
+	}
+	count, err := saturatedCount(ctx, zdd)
 	if err != nil {
 		return KBestResult{}, fmt.Errorf("k-best evaluation failed: %w", err)
 	}
+	if root, _ := zdd.GetNode(zdd.root); e.skipsRequired(zdd.vars+1, root.Level) {
+		return KBestResult{Solutions: []*Solution{}, Count: count}, nil
+	}
 	
-	// Sort solutions by cost
-	sort.Slice(solutions, func(i, j int) bool {
-		return solutions[i].Cost < solutions[j].Cost
-	})
+	lists := map[NodeID][]kBestEntry{OneNode: {{}}}
+	if err := zdd.bottomUp(ctx, func(id NodeID, node Node) error {
+		e.merge(zdd, id, node, lists)
+		return nil
+	}); err != nil {
+		return KBestResult{}, fmt.Errorf("k-best evaluation failed: %w", err)
+	}
 	
-	// Return top k solutions
-	count := len(solutions)
-	if count > e.K {
-		solutions = solutions[:e.K]
+	solutions := make([]*Solution, len(lists[zdd.root]))
+	for i, entry := range lists[zdd.root] {
+		cost := entry.cost + base
+		if e.Maximize {
+			cost = negateCost(cost)
+		}
+		solutions[i] = &Solution{
+			Variables: kBestVariables(zdd, lists, zdd.root, i),
+			Cost:      cost,
+			Metadata:  make(map[string]interface{}),
+		}
 	}
 	
 	return KBestResult{Solutions: solutions, Count: count}, nil
 }
 
-// enumerateSolutions recursively enumerates all solutions with costs
-func (e KBestEvaluator) enumerateSolutions(ctx context.Context, zdd *ZDD, nodeID NodeID, currentVars []int, currentCost float64) ([]*Solution, error) {
-	// Check for cancellation
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	default:
-	}
-	
-	// Handle terminal nodes
-	if nodeID == ZeroNode {
-		return []*Solution{}, nil // No solutions
+// kBestEntry is one of the k best completions of a node: its cost and the
+// rank of the completion of the chosen child it continues with
+type kBestEntry struct {
+	cost float64
+	take bool
+	rank int
+}
+
+// merge computes the k best completions of a node from those of its
+// children, in ascending cost order with ties broken in the stable order
+func (e KBestEvaluator) merge(zdd *ZDD, nodeID NodeID, node Node, lists map[NodeID][]kBestEntry) {
+	// merge both sorted lists; on equal cost the solution without the
+	// variable comes first, which is the stable order
+	lo, hi := lists[node.Lo], lists[node.Hi]
+	if e.Fixed != nil {
+		loNode, _ := zdd.GetNode(node.Lo)
+		hiNode, _ := zdd.GetNode(node.Hi)
+		if fixed, ok := e.Fixed[node.Level]; (ok && fixed) || e.skipsRequired(node.Level, loNode.Level) {
+			lo = nil
+		}
+		if fixed, ok := e.Fixed[node.Level]; (ok && !fixed) || e.skipsRequired(node.Level, hiNode.Level) {
+			hi = nil
+		}
 	}
-	if nodeID == OneNode {
-		// Create solution from current path
-		vars := make([]int, len(currentVars))
-		copy(vars, currentVars)
-		sort.Ints(vars) // Sort for consistent output
-		
-		solution := &Solution{
-			Variables: vars,
-			Cost:      currentCost,
-			Metadata:  make(map[string]interface{}),
+	c := e.Costs[node.Level]
+	merged := make([]kBestEntry, 0, min(e.K, len(lo)+len(hi)))
+	i, j := 0, 0
+	for len(merged) < e.K && (i < len(lo) || j < len(hi)) {
+		var next kBestEntry
+		if j == len(hi) || (i < len(lo) && lo[i].cost <= hi[j].cost+c) {
+			next = kBestEntry{cost: lo[i].cost, rank: i}
+			i++
+		} else {
+			next = kBestEntry{cost: hi[j].cost + c, take: true, rank: j}
+			j++
+		}
+		// a level's first entry is its representative in the stable order
+		if e.Distinct && len(merged) > 0 && merged[len(merged)-1].cost == next.cost {
+			continue
 		}
-		return []*Solution{solution}, nil
+		merged = append(merged, next)
 	}
 	
-	// Get node structure
-	node, err := zdd.GetNode(nodeID)
-	if err != nil {
-		return nil, err
+	lists[nodeID] = merged
+}
+
+// skipsRequired reports whether an arc from level from down to level to
+// skips a variable forced in; skipped variables are not selected
+func (e KBestEvaluator) skipsRequired(from, to int) bool {
+	return e.required[from-1]-e.required[to] > 0
+}
+
+// kBestVariables reconstructs the variables of the rank-th best completion
+// of a node
+func kBestVariables(zdd *ZDD, lists map[NodeID][]kBestEntry, nodeID NodeID, rank int) []int {
+	vars := []int{}
+	for nodeID != OneNode {
+		entry := lists[nodeID][rank]
+		node, _ := zdd.GetNode(nodeID)
+		if entry.take {
+			vars = append(vars, node.Level)
+			nodeID = node.Hi
+		} else {
+			nodeID = node.Lo
+		}
+		rank = entry.rank
 	}
-	
-	var allSolutions []*Solution
-	
-	// Explore lo-arc (don't take variable)
-	loSolutions, err := e.enumerateSolutions(ctx, zdd, node.Lo, currentVars, currentCost)
+	sort.Ints(vars)
+	return vars
+}
+
+// saturatedCount returns the number of solutions, clamped to math.MaxInt
+func saturatedCount(ctx context.Context, zdd *ZDD) (int, error) {
+	counts, err := zdd.subtreeCounts(ctx)
 	if err != nil {
-		return nil, err
+		return 0, err
+	}
+	count := counts[zdd.family()]
+	if count < 0 || count > math.MaxInt {
+		return math.MaxInt, nil
+	}
+	return int(count), nil
+}
+
+// negateCosts returns a negated copy of a cost vector, turning maximization
+// into minimization
+func negateCosts(costs []float64) []float64 {
+	negated := make([]float64, len(costs))
+	for i, c := range costs {
+		negated[i] = negateCost(c)
+	}
+	return negated
+}
+
+// negateCost returns -c, with zero staying positive zero
+func negateCost(c float64) float64 {
+	return 0 - c
+}
+
+// resolveCosts returns the dense 1-based selection cost vector for vars
+// variables and a constant base cost, from exactly one of a dense vector, a
+// sparse map or a cost model.
+//
+// A model's cost of not selecting a variable is folded into the base: every
+// level is either selected or not, so a solution's cost is the sum of all
+// not-selected costs plus, for each selected variable, the difference between
+// its two costs. Levels skipped by zero suppression count as not selected.
+func resolveCosts(vars int, dense []float64, sparse map[int]float64, model CostModel) ([]float64, float64, error) {
+	set := 0
+	for _, given := range []bool{dense != nil, sparse != nil, model != nil} {
+		if given {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, 0, fmt.Errorf("%w: only one of Costs, SparseCosts and Model may be set", ErrInvalidConstraint)
 	}
-	allSolutions = append(allSolutions, loSolutions...)
-	
-	// Explore hi-arc (take variable)
-	newVars := make([]int, len(currentVars)+1)
-	copy(newVars, currentVars)
-	newVars[len(currentVars)] = node.Level
 	
-	newCost := currentCost
-	if node.Level > 0 && node.Level < len(e.Costs) {
-		newCost += e.Costs[node.Level]
+	if m, ok := model.(CostMatrix); ok && len(m) <= vars {
+		return nil, 0, fmt.Errorf("insufficient cost data: need %d matrix rows, got %d", vars, len(m)-1)
 	}
 	
-	hiSolutions, err := e.enumerateSolutions(ctx, zdd, node.Hi, newVars, newCost)
-	if err != nil {
-		return nil, err
+	switch {
+	case model != nil:
+		costs := make([]float64, vars+1)
+		base := 0.0
+		for l := 1; l <= vars; l++ {
+			skip := model.Cost(l, false)
+			costs[l] = model.Cost(l, true) - skip
+			base += skip
+		}
+		if o, ok := model.(offsetModel); ok {
+			base += o.Offset()
+		}
+		return costs, base, nil
+		
+	case sparse != nil:
+		costs := make([]float64, vars+1)
+		for v, c := range sparse {
+			if v < 1 || v > vars {
+				return nil, 0, fmt.Errorf("%w: sparse cost for variable %d", ErrInvalidVariable, v)
+			}
+			costs[v] = c
+		}
+		return costs, 0, nil
 	}
-	allSolutions = append(allSolutions, hiSolutions...)
 	
-	return allSolutions, nil
+	if len(dense) <= vars {
+		return nil, 0, fmt.Errorf("insufficient cost data: need %d costs, got %d", vars, len(dense)-1)
+	}
+	return dense, 0, nil
 }
 
 // CustomEvaluator allows applications to define custom evaluation logic.
@@ -382,5 +626,55 @@ func EvaluateZDD(ctx context.Context, zdd *ZDD, evaluator Evaluator) (interface{
 		return nil, fmt.Errorf("%w: evaluator is nil", ErrInvalidConstraint)
 	}
 	
-	return evaluator.Evaluate(ctx, zdd)
+	start := time.Now()
+	var result interface{}
+	var cached bool
+	var err error
+	if zdd.config.ResultCache {
+		result, cached, err = zdd.results.evaluate(ctx, zdd, evaluator)
+	} else {
+		result, err = evaluator.Evaluate(ctx, zdd)
+	}
+	zdd.logEvaluation(ctx, evaluator, start, cached, err)
+	if err != nil {
+		return result, err
+	}
+	
+	zdd.decorateResult(result)
+	return result, nil
+}
+
+// decorateResult applies the solution decorator to the solutions of a
+// built-in evaluator result
+func (z *ZDD) decorateResult(result interface{}) {
+	switch r := result.(type) {
+	case OptimalResult:
+		if r.Found {
+			z.decorate(r.Solution)
+		}
+	case KBestResult:
+		z.decorate(r.Solutions...)
+	case IntKBestResult:
+		z.decorate(r.Solutions...)
+	case RatKBestResult:
+		z.decorate(r.Solutions...)
+	case ParetoResult:
+		z.decorate(r.Solutions...)
+	case SampleResult:
+		z.decorate(r.Solutions...)
+	}
+}
+
+// decorate passes each solution to the configured decorator, if any
+func (z *ZDD) decorate(solutions ...*Solution) {
+	fn := z.config.SolutionDecorator
+	if fn == nil {
+		return
+	}
+	for _, s := range solutions {
+		if s.Metadata == nil {
+			s.Metadata = make(map[string]interface{})
+		}
+		fn(s)
+	}
 }
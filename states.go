@@ -263,6 +263,10 @@ func (s *MapState) Equal(other State) bool {
 // variable assignments make subsequent variables irrelevant. For example, in the TripS
 // data center problem, when P_{kt} = 0 (don't place storage), all related T_{jkt} and
 // B_{ijkt} variables must be 0, so construction can skip those levels entirely.
+//
+// SkipTo must lie below the current level; Build fails with ErrInvalidLevel
+// otherwise. A SkipTo of 0 or less skips to the terminal. Specs written
+// from scratch can return the next level directly with LevelSpec instead.
 type SkipState struct {
 	State  State // The actual constraint state
 	SkipTo int   // 1-based level to skip to (must be < current level)
@@ -0,0 +1,239 @@
+package gozdd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BuildStats describes the work done by one construction.
+type BuildStats struct {
+	// NodesPerLevel holds at index l the number of nodes created at level l;
+	// index 0 is unused. Nodes that already existed, such as sub-diagrams
+	// shared through a Manager, are not counted.
+	NodesPerLevel []int
+
+	// Nodes is the total number of nodes created
+	Nodes int
+
+	// GetChildCalls is the number of transitions computed by the spec
+	GetChildCalls int64
+
+	// Prunes counts the branches pruned by GetChild errors, by reason. The
+	// reason is the text of the innermost error the GetChild error wraps,
+	// so errors that wrap a sentinel with %w share one entry whatever
+	// values they report. A *ConstraintError keeps its constraint index in
+	// the reason, as in "constraint 0: count exceeds maximum".
+	Prunes map[string]int64
+
+	// StateHits is the number of states that were equal to a state already
+	// found at their level, and so shared its node
+	StateHits int64
+
+	// StateMisses is the number of distinct states expanded
+	StateMisses int64
+
+	// Skips is the number of SkipState transitions
+	Skips int64
+
+	// SkippedLevels is the total distance of all skips: a skip made at
+	// level l to level t counts l-t, the decision at l plus the levels
+	// between that are never visited. A skip to the next level counts 1.
+	SkippedLevels int64
+
+	// Elapsed is the wall time of the construction
+	Elapsed time.Duration
+}
+
+// TotalPrunes returns the number of pruned branches over all errors.
+func (s BuildStats) TotalPrunes() int64 {
+	var total int64
+	for _, n := range s.Prunes {
+		total += n
+	}
+	return total
+}
+
+// StateHitRate returns the fraction of state lookups answered by an
+// equal state, or 0 without lookups.
+func (s BuildStats) StateHitRate() float64 {
+	return hitRate(s.StateHits, s.StateMisses)
+}
+
+// BuildWithStats constructs the ZDD like Build and reports the work done.
+//
+// The statistics cover what the builder observes, so they are the same
+// for every spec: a spec no longer needs to count its own calls or skips.
+// If construction fails, the statistics collected until the failure are
+// returned with the error. Counting costs a few atomic operations per
+// transition, so use Build when the statistics are not needed.
+func (z *ZDD) BuildWithStats(ctx context.Context, spec ConstraintSpec) (BuildStats, error) {
+	counters := &buildCounters{}
+	err := z.build(ctx, spec, counters)
+	return counters.stats(z.vars), err
+}
+
+// buildCounters collects the statistics of one Build. A nil *buildCounters
+// counts nothing, so builders call it unconditionally. Safe for concurrent
+// use.
+type buildCounters struct {
+	start time.Time
+
+	// nodes and base locate the nodes created by the build
+	nodes *NodeTable
+	base  NodeID
+
+	getChild      atomic.Int64
+	stateHits     atomic.Int64
+	stateMisses   atomic.Int64
+	skips         atomic.Int64
+	skippedLevels atomic.Int64
+
+	mu     sync.Mutex
+	prunes map[string]int64
+}
+
+// begin starts counting the nodes created in nodes
+func (c *buildCounters) begin(nodes *NodeTable) {
+	if c == nil {
+		return
+	}
+	c.start = time.Now()
+	c.nodes = nodes
+	c.base = NodeID(nodes.Size() + 1)
+}
+
+// stateFound counts a state lookup; hit is set if an equal state was
+// already known at the level
+func (c *buildCounters) stateFound(hit bool) {
+	if c == nil {
+		return
+	}
+	if hit {
+		c.stateHits.Add(1)
+	} else {
+		c.stateMisses.Add(1)
+	}
+}
+
+// prune counts a branch pruned by err
+func (c *buildCounters) prune(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.prunes == nil {
+		c.prunes = make(map[string]int64)
+	}
+	c.prunes[pruneReason(err)]++
+}
+
+// pruneReason groups a GetChild error by the constraint and the innermost
+// error behind it, leaving out the values wrapped around that
+func pruneReason(err error) string {
+	var constraint *ConstraintError
+	if errors.As(err, &constraint) {
+		return fmt.Sprintf("constraint %d: %v", constraint.Index, rootError(constraint.Err))
+	}
+	return rootError(err).Error()
+}
+
+// rootError follows the single-error wrap chain of err to its end
+func rootError(err error) error {
+	for {
+		inner := errors.Unwrap(err)
+		if inner == nil {
+			return err
+		}
+		err = inner
+	}
+}
+
+// totalPrunes returns the number of pruned branches so far
+func (c *buildCounters) totalPrunes() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var total int64
+	for _, n := range c.prunes {
+		total += n
+	}
+	return total
+}
+
+// stats snapshots the counters of a diagram over vars variables
+func (c *buildCounters) stats(vars int) BuildStats {
+	s := BuildStats{
+		NodesPerLevel: make([]int, vars+1),
+		GetChildCalls: c.getChild.Load(),
+		Prunes:        make(map[string]int64),
+		StateHits:     c.stateHits.Load(),
+		StateMisses:   c.stateMisses.Load(),
+		Skips:         c.skips.Load(),
+		SkippedLevels: c.skippedLevels.Load(),
+	}
+	if c.nodes == nil {
+		// the build failed before it started
+		return s
+	}
+	s.Elapsed = time.Since(c.start)
+
+	c.mu.Lock()
+	for text, n := range c.prunes {
+		s.Prunes[text] = n
+	}
+	c.mu.Unlock()
+
+	c.nodes.mu.RLock()
+	for id := c.base; id < c.nodes.next; id++ {
+		if l := c.nodes.nodes[id].Level; l >= 1 && l <= vars {
+			s.NodesPerLevel[l]++
+			s.Nodes++
+		}
+	}
+	c.nodes.mu.RUnlock()
+	return s
+}
+
+// countedSpec counts the transitions of a spec, and logs single ones at
+// LevelTrace when the build is logged at that level
+type countedSpec struct {
+	ConstraintSpec
+	counters *buildCounters
+	log      *buildLog
+}
+
+// unwrap returns the counted spec
+func (s countedSpec) unwrap() interface{} {
+	return s.ConstraintSpec
+}
+
+// GetChild delegates to the spec and counts pruned branches and skips
+func (s countedSpec) GetChild(ctx context.Context, state State, level int, take bool) (State, error) {
+	s.counters.getChild.Add(1)
+	child, err := s.ConstraintSpec.GetChild(ctx, state, level, take)
+	if err != nil {
+		s.counters.prune(err)
+		if s.log.tracing() {
+			s.log.logger.LogAttrs(ctx, LevelTrace, "pruned",
+				slog.Int("level", level),
+				slog.Bool("take", take),
+				slog.String("state", summarizeState(state)),
+				slog.Any("error", err))
+		}
+		return child, err
+	}
+
+	if skip, ok := child.(*SkipState); ok {
+		s.counters.skips.Add(1)
+		s.counters.skippedLevels.Add(int64(max(level-max(skip.SkipTo, 0), 0)))
+		if s.log.tracing() {
+			s.log.logger.LogAttrs(ctx, LevelTrace, "skipped",
+				slog.Int("level", level),
+				slog.Bool("take", take),
+				slog.Int("to", skip.SkipTo))
+		}
+	}
+	return child, err
+}
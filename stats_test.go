@@ -0,0 +1,67 @@
+package gozdd_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zzenonn/go-zdd"
+)
+
+// skipSpec accepts every set, but leaving out the variable at a level in
+// skips jumps to the level it maps to
+type skipSpec struct {
+	vars  int
+	skips map[int]int
+}
+
+func (s *skipSpec) Variables() int            { return s.vars }
+func (s *skipSpec) InitialState() gozdd.State { return gozdd.NewIntState(0) }
+func (s *skipSpec) IsValid(state gozdd.State) bool {
+	return true
+}
+
+func (s *skipSpec) GetChild(ctx context.Context, state gozdd.State, level int, take bool) (gozdd.State, error) {
+	if to, ok := s.skips[level]; ok && !take {
+		return gozdd.NewSkipState(state, to), nil
+	}
+	return state, nil
+}
+
+func TestBuildStatsSkippedLevels(t *testing.T) {
+	// one skip over three levels and one to the next level
+	spec := &skipSpec{vars: 6, skips: map[int]int{6: 3, 3: 2}}
+
+	stats, err := gozdd.NewZDD(6).BuildWithStats(context.Background(), spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Skips != 2 || stats.SkippedLevels != 4 {
+		t.Fatalf("Skips = %d, SkippedLevels = %d, want 2 and 4", stats.Skips, stats.SkippedLevels)
+	}
+}
+
+func TestBuildStatsGroupsPrunes(t *testing.T) {
+	// the errors of both constraints carry the count or sum they reached
+	weights := []float64{0, 1, 2, 3, 4, 5, 6, 7, 8}
+	spec := gozdd.NewCompositeSpec(8, gozdd.BasicState{Counters: []int{0}},
+		gozdd.CountConstraint{Min: 0, Max: 3},
+		gozdd.SumConstraint{Weights: weights, Min: 0, Max: 12})
+
+	stats, err := gozdd.NewZDD(8).BuildWithStats(context.Background(), spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for reason := range stats.Prunes {
+		switch reason {
+		case "constraint 0: count exceeds maximum", "constraint 1: sum exceeds maximum":
+		default:
+			t.Errorf("unexpected prune reason %q", reason)
+		}
+	}
+	if len(stats.Prunes) != 2 {
+		t.Fatalf("Prunes = %v, want one entry per constraint", stats.Prunes)
+	}
+	if total, pruned := stats.TotalPrunes(), spec.PruneCounts(); total != pruned[0]+pruned[1] {
+		t.Fatalf("TotalPrunes = %d, the constraints pruned %v", total, pruned)
+	}
+}
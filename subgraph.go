@@ -0,0 +1,19 @@
+package gozdd
+
+// Subgraph returns the sub-diagram rooted at id as an independent ZDD.
+//
+// The result has id's level as its number of variables, so variable numbers
+// keep their meaning: its family is the set of completions over variables
+// 1..level that lead from id to the 1-terminal. A terminal yields a ZDD with
+// no variables, holding either the empty family or the family containing
+// only the empty set.
+//
+// Returns ErrInvalidNode if id does not exist.
+func (z *ZDD) Subgraph(id NodeID) (*ZDD, error) {
+	node, err := z.GetNode(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return z.derive(node.Level, z.nodes, id)
+}
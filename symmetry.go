@@ -0,0 +1,565 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// DetectSymmetries finds groups of interchangeable variables in the ZDD.
+//
+// Two variables i and j are interchangeable when swapping them maps the family
+// onto itself, which holds exactly when the sets containing i but not j mirror
+// the sets containing j but not i. Interchangeability is an equivalence relation,
+// so the variables split into orbits.
+//
+// Returns the non-trivial orbits (two or more members), each sorted ascending,
+// ordered by their smallest member. The smallest member of each orbit serves as
+// its representative.
+func (z *ZDD) DetectSymmetries(ctx context.Context) ([][]int, error) {
+	ops, roots, err := workspace(ctx, z)
+	if err != nil {
+		return nil, err
+	}
+	f := roots[0]
+
+	var orbits [][]int
+	for v := 1; v <= z.vars; v++ {
+		placed := false
+		for i, orbit := range orbits {
+			same, err := ops.interchangeable(f, orbit[0], v)
+			if err != nil {
+				return nil, fmt.Errorf("symmetry detection failed: %w", err)
+			}
+			if same {
+				orbits[i] = append(orbit, v)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			orbits = append(orbits, []int{v})
+		}
+	}
+
+	nontrivial := make([][]int, 0, len(orbits))
+	for _, orbit := range orbits {
+		if len(orbit) > 1 {
+			nontrivial = append(nontrivial, orbit)
+		}
+	}
+
+	return nontrivial, nil
+}
+
+// interchangeable reports whether family f is invariant under swapping i and j
+func (o *familyOps) interchangeable(f NodeID, i, j int) (bool, error) {
+	onlyI, err := o.subset1(f, i)
+	if err != nil {
+		return false, err
+	}
+	if onlyI, err = o.subset0(onlyI, j); err != nil {
+		return false, err
+	}
+
+	onlyJ, err := o.subset0(f, i)
+	if err != nil {
+		return false, err
+	}
+	if onlyJ, err = o.subset1(onlyJ, j); err != nil {
+		return false, err
+	}
+
+	// Node tables are canonical, so equal families share the same NodeID
+	return onlyI == onlyJ, nil
+}
+
+// CollapseSymmetry reduces the ZDD to one representative per symmetry class.
+//
+// Each orbit lists variables that are interchangeable in the family, such as
+// the result of DetectSymmetries. Within an orbit, the representative of a set
+// selects the lowest-numbered variables of that orbit; all other members of
+// the class are removed. Counting the collapsed ZDD therefore counts solutions
+// up to symmetry.
+//
+// The orbits are not verified against the family; passing variables that are
+// not interchangeable drops genuine solutions.
+func (z *ZDD) CollapseSymmetry(ctx context.Context, orbits [][]int) (*ZDD, error) {
+	canon := NewZDD(z.vars)
+	if err := canon.Build(ctx, NewQuotientSpec(universeSpec{vars: z.vars}, orbits)); err != nil {
+		return nil, fmt.Errorf("canonical family: %w", err)
+	}
+
+	ops, roots, err := workspace(ctx, z, canon)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := ops.intersect(roots[0], roots[1])
+	if err != nil {
+		return nil, fmt.Errorf("collapse failed: %w", err)
+	}
+
+	return z.derive(z.vars, ops.nt, root)
+}
+
+// QuotientSpec wraps a ConstraintSpec so that construction only emits one
+// representative per symmetry class.
+//
+// For every orbit of interchangeable variables, a set is kept only if the
+// selected members of the orbit are the lowest-numbered ones. The pruning
+// happens during construction, so symmetric branches are never expanded.
+type QuotientSpec struct {
+	spec ConstraintSpec
+
+	// orbitOf maps a level to its orbit index, or -1
+	orbitOf []int
+
+	// lower maps a level to the next lower member of its orbit, or 0
+	lower []int
+
+	orbits int
+}
+
+// NewQuotientSpec creates a spec that builds spec up to the given symmetries.
+//
+// Orbits must be disjoint and contain variables within 1..spec.Variables().
+// Invalid orbits cause Build to fail with ErrInvalidVariable.
+func NewQuotientSpec(spec ConstraintSpec, orbits [][]int) *QuotientSpec {
+	vars := spec.Variables()
+	q := &QuotientSpec{
+		spec:    spec,
+		orbitOf: make([]int, vars+1),
+		lower:   make([]int, vars+1),
+		orbits:  len(orbits),
+	}
+
+	for l := range q.orbitOf {
+		q.orbitOf[l] = -1
+	}
+
+	for i, orbit := range orbits {
+		members := make([]int, len(orbit))
+		copy(members, orbit)
+		sort.Ints(members)
+
+		for k, v := range members {
+			if v < 1 || v > vars || q.orbitOf[v] != -1 {
+				// Mark the spec invalid; reported from GetChild
+				q.orbitOf = nil
+				return q
+			}
+			q.orbitOf[v] = i
+			if k > 0 {
+				q.lower[v] = members[k-1]
+			}
+		}
+	}
+
+	return q
+}
+
+// quotientState tracks which orbits require their next lower member
+type quotientState struct {
+	inner   State
+	pending []bool
+}
+
+// Clone creates a deep copy of the quotientState
+func (s *quotientState) Clone() State {
+	pending := make([]bool, len(s.pending))
+	copy(pending, s.pending)
+	return &quotientState{inner: s.inner.Clone(), pending: pending}
+}
+
+// Hash combines the wrapped state's hash with the pending flags
+func (s *quotientState) Hash() uint64 {
+	hash := s.inner.Hash()
+	for i, p := range s.pending {
+		if p {
+			hash = hash*31 + uint64(i+1)
+		}
+	}
+	return hash
+}
+
+// Equal checks equality with another quotientState
+func (s *quotientState) Equal(other State) bool {
+	o, ok := other.(*quotientState)
+	if !ok || len(s.pending) != len(o.pending) {
+		return false
+	}
+	for i := range s.pending {
+		if s.pending[i] != o.pending[i] {
+			return false
+		}
+	}
+	return s.inner.Equal(o.inner)
+}
+
+// Variables returns the number of decision variables
+func (q *QuotientSpec) Variables() int {
+	return q.spec.Variables()
+}
+
+// InitialState wraps the initial state of the underlying spec
+func (q *QuotientSpec) InitialState() State {
+	return &quotientState{inner: q.spec.InitialState(), pending: make([]bool, q.orbits)}
+}
+
+// GetChild applies the underlying transition and the symmetry-breaking rule
+func (q *QuotientSpec) GetChild(ctx context.Context, state State, level int, take bool) (State, error) {
+	if q.orbitOf == nil {
+		return nil, fmt.Errorf("%w: orbits must be disjoint variables within 1..%d", ErrInvalidVariable, q.Variables())
+	}
+
+	s := state.(*quotientState)
+	pending := make([]bool, len(s.pending))
+	copy(pending, s.pending)
+
+	if err := q.assign(pending, level, take); err != nil {
+		return nil, err
+	}
+
+	child, err := q.spec.GetChild(ctx, s.inner, level, take)
+	if err != nil {
+		return nil, err
+	}
+
+	if skip, ok := child.(*SkipState); ok {
+		// Skipped levels are not selected
+		for l := level - 1; l > skip.SkipTo && l > 0; l-- {
+			if err := q.assign(pending, l, false); err != nil {
+				return nil, err
+			}
+		}
+		return NewSkipState(&quotientState{inner: skip.State, pending: pending}, skip.SkipTo), nil
+	}
+
+	return &quotientState{inner: child, pending: pending}, nil
+}
+
+// assign updates pending flags for an assignment, rejecting non-canonical sets
+func (q *QuotientSpec) assign(pending []bool, level int, take bool) error {
+	orbit := q.orbitOf[level]
+	if orbit < 0 {
+		return nil
+	}
+
+	if pending[orbit] && !take {
+		return fmt.Errorf("symmetric assignment at level %d is not canonical", level)
+	}
+
+	pending[orbit] = take && q.lower[level] > 0
+	return nil
+}
+
+// IsValid delegates to the underlying spec
+func (q *QuotientSpec) IsValid(state State) bool {
+	s := state.(*quotientState)
+	for _, p := range s.pending {
+		if p {
+			return false
+		}
+	}
+	return q.spec.IsValid(s.inner)
+}
+
+// universeSpec accepts every subset of its variables
+type universeSpec struct {
+	vars int
+}
+
+// Variables returns the number of decision variables
+func (u universeSpec) Variables() int {
+	return u.vars
+}
+
+// InitialState returns an empty state shared by all subsets
+func (u universeSpec) InitialState() State {
+	return NewIntState()
+}
+
+// GetChild accepts every assignment
+func (u universeSpec) GetChild(ctx context.Context, state State, level int, take bool) (State, error) {
+	return state, nil
+}
+
+// IsValid accepts every subset
+func (u universeSpec) IsValid(state State) bool {
+	return true
+}
+
+// SymmetricGroup declares blocks of variables that are interchangeable as a whole.
+//
+// Every block must have the same length, and position p of one block plays the
+// same role as position p of every other block. For example, in a bin-packing
+// model where variable (bin, item) says whether item goes into bin, each bin's
+// item variables form one block and the bins are interchangeable.
+type SymmetricGroup struct {
+	// Name identifies the group in error messages
+	Name string
+
+	// Blocks lists the variables of each interchangeable block
+	Blocks [][]int
+}
+
+// ContiguousBlocks creates a SymmetricGroup of count blocks of size consecutive
+// variables each, starting at variable start.
+//
+// Example: bins 1..k with m items each, laid out bin by bin from variable 1:
+//   group := ContiguousBlocks("bins", 1, m, k)
+func ContiguousBlocks(name string, start, size, count int) SymmetricGroup {
+	blocks := make([][]int, count)
+	for b := range blocks {
+		blocks[b] = make([]int, size)
+		for p := range blocks[b] {
+			blocks[b][p] = start + b*size + p
+		}
+	}
+	return SymmetricGroup{Name: name, Blocks: blocks}
+}
+
+// DeclareSymmetry annotates the spec with groups of interchangeable blocks.
+//
+// During construction the spec injects lexicographic symmetry-breaking rules:
+// for each pair of adjacent blocks, the assignment of the earlier block
+// (compared position by position, selected before not selected) must be
+// lexicographically greater than or equal to the assignment of the later block.
+// Only one representative of every symmetry class is kept, which can shrink
+// both build time and node count dramatically.
+//
+// The declarations are trusted; declaring blocks that are not truly
+// interchangeable removes genuine solutions.
+//
+// Returns ErrInvalidVariable if blocks have different sizes, overlap each other
+// or previously declared groups, or reference variables outside 1..Variables().
+func (c *CompositeConstraintSpec) DeclareSymmetry(groups ...SymmetricGroup) error {
+	breaker := c.symmetry
+	if breaker == nil {
+		breaker = newSymmetryBreaker(c.vars)
+	}
+
+	for _, group := range groups {
+		if err := breaker.add(group); err != nil {
+			return err
+		}
+	}
+
+	c.symmetry = breaker
+	return nil
+}
+
+// symmetryBreaker enforces lexicographic ordering between adjacent blocks
+type symmetryBreaker struct {
+	// slotOf maps a level to its value slot, or -1 if not in any group
+	slotOf []int
+
+	// slots describes every grouped variable
+	slots []symmetrySlot
+
+	// pairs lists the adjacent block pairs that must be ordered
+	pairs []symmetryPair
+}
+
+// symmetrySlot locates a grouped variable
+type symmetrySlot struct {
+	pairs []int // indices of pairs comparing this variable
+	pos   int   // position within its block
+}
+
+// symmetryPair compares two blocks position by position
+type symmetryPair struct {
+	left, right []int // slot indices by position
+}
+
+// newSymmetryBreaker creates a breaker with no groups
+func newSymmetryBreaker(vars int) *symmetryBreaker {
+	slotOf := make([]int, vars+1)
+	for l := range slotOf {
+		slotOf[l] = -1
+	}
+	return &symmetryBreaker{slotOf: slotOf}
+}
+
+// add registers the blocks of a group, validating their layout
+func (b *symmetryBreaker) add(group SymmetricGroup) error {
+	if len(group.Blocks) < 2 {
+		return nil
+	}
+
+	size := len(group.Blocks[0])
+	blockSlots := make([][]int, len(group.Blocks))
+	for i, block := range group.Blocks {
+		if len(block) != size {
+			return fmt.Errorf("%w: group %q block %d has %d variables, want %d", ErrInvalidVariable, group.Name, i, len(block), size)
+		}
+
+		blockSlots[i] = make([]int, size)
+		for p, v := range block {
+			if v < 1 || v >= len(b.slotOf) || b.slotOf[v] != -1 {
+				return fmt.Errorf("%w: group %q variable %d is out of range or already grouped", ErrInvalidVariable, group.Name, v)
+			}
+			b.slotOf[v] = len(b.slots)
+			blockSlots[i][p] = len(b.slots)
+			b.slots = append(b.slots, symmetrySlot{pos: p})
+		}
+	}
+
+	for i := 0; i+1 < len(blockSlots); i++ {
+		pair := len(b.pairs)
+		b.pairs = append(b.pairs, symmetryPair{left: blockSlots[i], right: blockSlots[i+1]})
+		for p := 0; p < size; p++ {
+			left, right := blockSlots[i][p], blockSlots[i+1][p]
+			b.slots[left].pairs = append(b.slots[left].pairs, pair)
+			b.slots[right].pairs = append(b.slots[right].pairs, pair)
+		}
+	}
+
+	return nil
+}
+
+// symmetryState wraps a constraint state with lexicographic comparison progress
+type symmetryState struct {
+	inner State
+
+	// values holds assigned values still needed for comparison: -1 unknown, 0, 1
+	values []int8
+
+	// next is the next position to compare per pair, or -1 once strictly ordered
+	next []int
+}
+
+// Clone creates a deep copy of the symmetryState
+func (s *symmetryState) Clone() State {
+	values := make([]int8, len(s.values))
+	copy(values, s.values)
+	next := make([]int, len(s.next))
+	copy(next, s.next)
+	return &symmetryState{inner: s.inner.Clone(), values: values, next: next}
+}
+
+// Hash combines the wrapped state's hash with the comparison progress
+func (s *symmetryState) Hash() uint64 {
+	hash := s.inner.Hash()
+	for _, v := range s.values {
+		hash = hash*31 + uint64(v+2)
+	}
+	for _, n := range s.next {
+		hash = hash*31 + uint64(n+2)
+	}
+	return hash
+}
+
+// Equal checks equality with another symmetryState
+func (s *symmetryState) Equal(other State) bool {
+	o, ok := other.(*symmetryState)
+	if !ok || len(s.values) != len(o.values) || len(s.next) != len(o.next) {
+		return false
+	}
+	for i := range s.values {
+		if s.values[i] != o.values[i] {
+			return false
+		}
+	}
+	for i := range s.next {
+		if s.next[i] != o.next[i] {
+			return false
+		}
+	}
+	return s.inner.Equal(o.inner)
+}
+
+// initial wraps the initial constraint state
+func (b *symmetryBreaker) initial(inner State) State {
+	values := make([]int8, len(b.slots))
+	for i := range values {
+		values[i] = -1
+	}
+	return &symmetryState{inner: inner, values: values, next: make([]int, len(b.pairs))}
+}
+
+// child applies the underlying transition and the symmetry-breaking rules
+func (b *symmetryBreaker) child(ctx context.Context, state State, level int, take bool, next func(context.Context, State, int, bool) (State, error)) (State, error) {
+	s := state.(*symmetryState)
+
+	child, err := next(ctx, s.inner, level, take)
+	if err != nil {
+		return nil, err
+	}
+
+	slot := b.slotOf[level]
+	if slot < 0 {
+		wrapped := s.Clone().(*symmetryState)
+		wrapped.inner = child
+		return wrapped, nil
+	}
+
+	wrapped := &symmetryState{inner: child}
+	wrapped.values = make([]int8, len(s.values))
+	copy(wrapped.values, s.values)
+	wrapped.next = make([]int, len(s.next))
+	copy(wrapped.next, s.next)
+
+	if take {
+		wrapped.values[slot] = 1
+	} else {
+		wrapped.values[slot] = 0
+	}
+
+	for _, pair := range b.slots[slot].pairs {
+		if err := b.advance(wrapped, pair); err != nil {
+			return nil, err
+		}
+	}
+
+	return wrapped, nil
+}
+
+// advance compares a block pair as far as the known values allow
+func (b *symmetryBreaker) advance(s *symmetryState, pair int) error {
+	p := b.pairs[pair]
+
+	for s.next[pair] >= 0 && s.next[pair] < len(p.left) {
+		pos := s.next[pair]
+		left, right := s.values[p.left[pos]], s.values[p.right[pos]]
+		if left < 0 || right < 0 {
+			return nil
+		}
+
+		switch {
+		case left > right:
+			s.next[pair] = -1
+		case left < right:
+			return fmt.Errorf("symmetric blocks out of lexicographic order at position %d", pos)
+		default:
+			s.next[pair]++
+		}
+
+		b.release(s, p.left[pos])
+		b.release(s, p.right[pos])
+	}
+
+	if s.next[pair] < 0 {
+		for pos := range p.left {
+			b.release(s, p.left[pos])
+			b.release(s, p.right[pos])
+		}
+	}
+
+	return nil
+}
+
+// release forgets a slot's value once no pair still needs it, improving state sharing
+func (b *symmetryBreaker) release(s *symmetryState, slot int) {
+	if s.values[slot] < 0 {
+		return
+	}
+	pos := b.slots[slot].pos
+	for _, pair := range b.slots[slot].pairs {
+		if s.next[pair] >= 0 && s.next[pair] <= pos {
+			return
+		}
+	}
+	s.values[slot] = -1
+}
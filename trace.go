@@ -0,0 +1,83 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// maxTraceState bounds the length of state summaries in trace output
+const maxTraceState = 120
+
+// tracedSpec logs every transition and terminal validation of a spec
+type tracedSpec struct {
+	ConstraintSpec
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// newTracedSpec wraps spec so that its calls are logged to w
+func newTracedSpec(spec ConstraintSpec, w io.Writer) *tracedSpec {
+	return &tracedSpec{ConstraintSpec: spec, w: w}
+}
+
+// unwrap returns the traced spec
+func (t *tracedSpec) unwrap() interface{} {
+	return t.ConstraintSpec
+}
+
+// GetChild delegates to the spec and logs the transition and its outcome
+func (t *tracedSpec) GetChild(ctx context.Context, state State, level int, take bool) (State, error) {
+	child, err := t.ConstraintSpec.GetChild(ctx, state, level, take)
+
+	var outcome string
+	switch c := child.(type) {
+	case nil:
+		outcome = fmt.Sprintf("pruned: %v", err)
+	case *SkipState:
+		outcome = fmt.Sprintf("skip to %d %s", c.SkipTo, summarizeState(c.State))
+	default:
+		outcome = summarizeState(c)
+		if err != nil {
+			outcome = fmt.Sprintf("pruned: %v", err)
+		}
+	}
+
+	t.printf("level=%d take=%t state=%s -> %s\n", level, take, summarizeState(state), outcome)
+	return child, err
+}
+
+// IsValid delegates to the spec and logs the terminal verdict
+func (t *tracedSpec) IsValid(state State) bool {
+	valid := t.ConstraintSpec.IsValid(state)
+	t.printf("terminal state=%s -> valid=%t\n", summarizeState(state), valid)
+	return valid
+}
+
+// printf serializes writes from concurrent construction
+func (t *tracedSpec) printf(format string, args ...interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Fprintf(t.w, format, args...)
+}
+
+// summarizeState renders a state for trace output, preferring fmt.Stringer
+func summarizeState(state State) string {
+	var s string
+	switch st := state.(type) {
+	case nil:
+		return "<nil>"
+	case fmt.Stringer:
+		s = st.String()
+	default:
+		s = strings.TrimPrefix(fmt.Sprintf("%+v", st), "&")
+	}
+
+	if len(s) > maxTraceState {
+		s = s[:maxTraceState-3] + "..."
+	}
+	return s
+}
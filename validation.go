@@ -0,0 +1,180 @@
+package gozdd
+
+import (
+	"sync"
+)
+
+// BatchValidator is an optional interface for specs that can validate many
+// terminal states more efficiently together than one at a time.
+type BatchValidator interface {
+	// IsValidBatch returns IsValid for each state, in order.
+	IsValidBatch(states []State) []bool
+}
+
+// ValidationCache memoizes the IsValid results of a ConstraintSpec.
+//
+// States are looked up by Hash and confirmed with Equal, so hash collisions
+// never return another state's result. The cache is safe for concurrent use.
+type ValidationCache struct {
+	spec ConstraintSpec
+
+	mu      sync.Mutex
+	entries map[uint64][]validationEntry
+	hits    int64
+	misses  int64
+}
+
+// validationEntry stores the validity of one state
+type validationEntry struct {
+	state State
+	valid bool
+}
+
+// NewValidationCache creates an empty cache for spec.
+func NewValidationCache(spec ConstraintSpec) *ValidationCache {
+	return &ValidationCache{
+		spec:    spec,
+		entries: make(map[uint64][]validationEntry),
+	}
+}
+
+// IsValid returns spec.IsValid(state), computing it at most once per distinct state.
+func (c *ValidationCache) IsValid(state State) bool {
+	hash := state.Hash()
+
+	c.mu.Lock()
+	if valid, ok := c.lookup(hash, state); ok {
+		c.hits++
+		c.mu.Unlock()
+		return valid
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	// Validate outside the lock; a concurrent duplicate computation is harmless
+	valid := c.spec.IsValid(state)
+
+	c.mu.Lock()
+	c.store(hash, state, valid)
+	c.mu.Unlock()
+
+	return valid
+}
+
+// IsValidBatch validates several states, consulting the cache first.
+//
+// States that are not cached are validated together through the spec's
+// BatchValidator implementation when available, otherwise one at a time.
+// A state repeated within the batch is validated once and counts as a hit
+// after its first occurrence.
+func (c *ValidationCache) IsValidBatch(states []State) []bool {
+	results := make([]bool, len(states))
+	hashes := make([]uint64, len(states))
+
+	// pending holds the first occurrence of each uncached state; repeats maps
+	// later occurrences to their position in pending
+	var pending []int
+	byHash := make(map[uint64][]int)
+	repeats := make(map[int]int)
+
+	c.mu.Lock()
+	for i, state := range states {
+		hashes[i] = state.Hash()
+		if valid, ok := c.lookup(hashes[i], state); ok {
+			c.hits++
+			results[i] = valid
+			continue
+		}
+		if j, ok := pendingIndex(states, pending, byHash[hashes[i]], state); ok {
+			c.hits++
+			repeats[i] = j
+			continue
+		}
+		c.misses++
+		byHash[hashes[i]] = append(byHash[hashes[i]], len(pending))
+		pending = append(pending, i)
+	}
+	c.mu.Unlock()
+
+	if len(pending) == 0 {
+		return results
+	}
+
+	batch := make([]State, len(pending))
+	for j, i := range pending {
+		batch[j] = states[i]
+	}
+
+	var valid []bool
+	if bv, ok := c.spec.(BatchValidator); ok {
+		valid = bv.IsValidBatch(batch)
+	} else {
+		valid = make([]bool, len(batch))
+		for j, state := range batch {
+			valid[j] = c.spec.IsValid(state)
+		}
+	}
+
+	c.mu.Lock()
+	for j, i := range pending {
+		results[i] = valid[j]
+		c.store(hashes[i], states[i], valid[j])
+	}
+	c.mu.Unlock()
+	for i, j := range repeats {
+		results[i] = valid[j]
+	}
+
+	return results
+}
+
+// pendingIndex finds state among the pending states at the given positions
+func pendingIndex(states []State, pending, positions []int, state State) (int, bool) {
+	for _, j := range positions {
+		if states[pending[j]].Equal(state) {
+			return j, true
+		}
+	}
+	return 0, false
+}
+
+// Stats returns the number of cache hits and misses so far.
+func (c *ValidationCache) Stats() (hits, misses int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// lookup finds a cached result; the caller must hold mu
+func (c *ValidationCache) lookup(hash uint64, state State) (bool, bool) {
+	for _, entry := range c.entries[hash] {
+		if entry.state.Equal(state) {
+			return entry.valid, true
+		}
+	}
+	return false, false
+}
+
+// store records a result unless an equal state is already cached; the caller must hold mu
+func (c *ValidationCache) store(hash uint64, state State, valid bool) {
+	if _, ok := c.lookup(hash, state); ok {
+		return
+	}
+	c.entries[hash] = append(c.entries[hash], validationEntry{state: state.Clone(), valid: valid})
+}
+
+// cachedSpec routes IsValid through a ValidationCache
+type cachedSpec struct {
+	ConstraintSpec
+	cache *ValidationCache
+}
+
+// unwrap returns the cached spec
+func (s cachedSpec) unwrap() interface{} {
+	return s.ConstraintSpec
+}
+
+// IsValid consults the validation cache
+func (s cachedSpec) IsValid(state State) bool {
+	return s.cache.IsValid(state)
+}
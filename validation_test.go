@@ -0,0 +1,138 @@
+package gozdd_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zzenonn/go-zdd"
+)
+
+// collidingState hashes every value alike, so only Equal tells them apart
+type collidingState struct{ v int }
+
+func (s collidingState) Clone() gozdd.State { return s }
+func (s collidingState) Hash() uint64       { return 42 }
+func (s collidingState) Equal(other gozdd.State) bool {
+	o, ok := other.(collidingState)
+	return ok && o.v == s.v
+}
+
+// paritySpec accepts even values and counts its validations
+type paritySpec struct {
+	calls   int
+	batches [][]gozdd.State
+}
+
+func (s *paritySpec) Variables() int            { return 1 }
+func (s *paritySpec) InitialState() gozdd.State { return collidingState{} }
+func (s *paritySpec) GetChild(ctx context.Context, state gozdd.State, level int, take bool) (gozdd.State, error) {
+	return state, nil
+}
+
+func (s *paritySpec) IsValid(state gozdd.State) bool {
+	s.calls++
+	switch st := state.(type) {
+	case collidingState:
+		return st.v%2 == 0
+	case *gozdd.IntState:
+		return st.Values[0]%2 == 0
+	}
+	return false
+}
+
+// batchParitySpec validates through IsValidBatch
+type batchParitySpec struct{ paritySpec }
+
+func (s *batchParitySpec) IsValidBatch(states []gozdd.State) []bool {
+	s.batches = append(s.batches, states)
+	valid := make([]bool, len(states))
+	for i, state := range states {
+		valid[i] = s.IsValid(state)
+	}
+	return valid
+}
+
+func TestValidationCacheMatchesIsValid(t *testing.T) {
+	for _, colliding := range []bool{false, true} {
+		spec := &paritySpec{}
+		cache := gozdd.NewValidationCache(spec)
+		state := func(v int) gozdd.State {
+			if colliding {
+				return collidingState{v}
+			}
+			return gozdd.NewIntState(v)
+		}
+
+		// each value is looked up three times
+		for round := 0; round < 3; round++ {
+			for v := 0; v < 10; v++ {
+				if got, want := cache.IsValid(state(v)), v%2 == 0; got != want {
+					t.Fatalf("colliding=%v: IsValid(%d) = %v, want %v", colliding, v, got, want)
+				}
+			}
+		}
+
+		if spec.calls != 10 {
+			t.Fatalf("colliding=%v: spec validated %d times, want once per state (10)", colliding, spec.calls)
+		}
+		if hits, misses := cache.Stats(); hits != 20 || misses != 10 {
+			t.Fatalf("colliding=%v: stats %d hits, %d misses, want 20 and 10", colliding, hits, misses)
+		}
+	}
+}
+
+func TestValidationCacheBatch(t *testing.T) {
+	for _, batched := range []bool{false, true} {
+		bs := &batchParitySpec{}
+		var spec gozdd.ConstraintSpec = &bs.paritySpec
+		if batched {
+			spec = bs
+		}
+		cache := gozdd.NewValidationCache(spec)
+
+		// 1 is cached beforehand; 2 and 3 repeat within the batch
+		cache.IsValid(collidingState{1})
+		states := []gozdd.State{
+			collidingState{1}, collidingState{2}, collidingState{3},
+			collidingState{2}, collidingState{4}, collidingState{3},
+		}
+		got := cache.IsValidBatch(states)
+
+		for i, state := range states {
+			if want := state.(collidingState).v%2 == 0; got[i] != want {
+				t.Fatalf("batched=%v: result %d = %v, want %v", batched, i, got[i], want)
+			}
+		}
+		if bs.calls != 4 {
+			t.Fatalf("batched=%v: spec validated %d times, want 4", batched, bs.calls)
+		}
+		if batched && (len(bs.batches) != 1 || len(bs.batches[0]) != 3) {
+			t.Fatalf("batched=%v: batches %v, want one batch of the 3 uncached states", batched, bs.batches)
+		}
+		if hits, misses := cache.Stats(); hits != 3 || misses != 4 {
+			t.Fatalf("batched=%v: stats %d hits, %d misses, want 3 and 4", batched, hits, misses)
+		}
+
+		// the whole batch is cached now
+		cache.IsValidBatch(states)
+		if hits, misses := cache.Stats(); hits != 9 || misses != 4 || bs.calls != 4 {
+			t.Fatalf("batched=%v: after a cached batch %d hits, %d misses, %d calls", batched, hits, misses, bs.calls)
+		}
+	}
+}
+
+func TestWithValidationCacheBuild(t *testing.T) {
+	ctx := context.Background()
+	spec := &SimpleSpec{vars: 8, maxCount: 3}
+
+	plain := gozdd.NewZDD(8)
+	cached := gozdd.NewZDD(8, gozdd.WithValidationCache())
+	for _, z := range []*gozdd.ZDD{plain, cached} {
+		if err := z.Build(ctx, spec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !plain.Equals(cached) {
+		t.Fatal("WithValidationCache changed the family")
+	}
+}
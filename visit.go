@@ -0,0 +1,183 @@
+package gozdd
+
+import (
+	"context"
+	"errors"
+)
+
+// Sentinel errors that visitor callbacks return to steer a traversal.
+var (
+	// SkipNode tells Visit not to descend into the children of the node whose
+	// PreNode callback returned it. PostNode is not called for that node.
+	SkipNode = errors.New("skip node")
+
+	// StopVisit ends the traversal immediately; Visit then returns nil.
+	StopVisit = errors.New("stop visit")
+)
+
+// Visitor receives callbacks during a depth-first traversal of a ZDD.
+//
+// Every reachable node, terminals included, is visited exactly once: PreNode
+// when it is first reached, then the Lo arc and sub-diagram, then the Hi arc
+// and sub-diagram, then PostNode. Sub-diagrams that were already visited
+// through another parent are not traversed again, but the arc into them is
+// still reported. Terminals have no arcs.
+//
+// A callback can return SkipNode or StopVisit to control the traversal; any
+// other error aborts it and is returned by Visit.
+type Visitor interface {
+	// PreNode is called when a node is reached for the first time
+	PreNode(id NodeID, node Node) error
+
+	// Arc is called for each outgoing arc; take is true for the Hi arc
+	Arc(from, to NodeID, take bool) error
+
+	// PostNode is called after all descendants of a node have been visited
+	PostNode(id NodeID, node Node) error
+}
+
+// VisitorFuncs adapts plain functions to the Visitor interface.
+// Nil fields are treated as callbacks that do nothing.
+type VisitorFuncs struct {
+	Pre   func(id NodeID, node Node) error
+	OnArc func(from, to NodeID, take bool) error
+	Post  func(id NodeID, node Node) error
+}
+
+// PreNode calls v.Pre if set
+func (v VisitorFuncs) PreNode(id NodeID, node Node) error {
+	if v.Pre == nil {
+		return nil
+	}
+	return v.Pre(id, node)
+}
+
+// Arc calls v.OnArc if set
+func (v VisitorFuncs) Arc(from, to NodeID, take bool) error {
+	if v.OnArc == nil {
+		return nil
+	}
+	return v.OnArc(from, to, take)
+}
+
+// PostNode calls v.Post if set
+func (v VisitorFuncs) PostNode(id NodeID, node Node) error {
+	if v.Post == nil {
+		return nil
+	}
+	return v.Post(id, node)
+}
+
+// Visit traverses the ZDD depth-first from the root, calling the visitor's
+// hooks for every reachable node and arc.
+//
+// Visiting an unbuilt ZDD makes no calls. Returns the context error if the
+// traversal is cancelled, or the first error returned by a callback other
+// than SkipNode and StopVisit.
+//
+// Example:
+//
+//	// Count nodes per level
+//	width := make(map[int]int)
+//	err := zdd.Visit(ctx, gozdd.VisitorFuncs{
+//	    Pre: func(id gozdd.NodeID, n gozdd.Node) error {
+//	        width[n.Level]++
+//	        return nil
+//	    },
+//	})
+func (z *ZDD) Visit(ctx context.Context, visitor Visitor) error {
+	if z.root == NullNode {
+		return nil
+	}
+
+	w := &walker{ctx: ctx, zdd: z, visitor: visitor, seen: make(map[NodeID]bool)}
+	err := w.visit(z.root)
+	if errors.Is(err, StopVisit) {
+		return nil
+	}
+	return err
+}
+
+// walker holds the state of a single Visit traversal
+type walker struct {
+	ctx     context.Context
+	zdd     *ZDD
+	visitor Visitor
+	seen    map[NodeID]bool
+	stack   []visitFrame
+}
+
+// visit traverses the sub-diagram rooted at id.
+//
+// Nodes whose arcs are being traversed sit on an explicit stack of frames,
+// so deep diagrams need no recursion.
+func (w *walker) visit(id NodeID) error {
+	if err := w.enter(id); err != nil {
+		return err
+	}
+
+	for len(w.stack) > 0 {
+		f := &w.stack[len(w.stack)-1]
+		if f.node.IsTerminal() || f.next == 2 {
+			w.stack = w.stack[:len(w.stack)-1]
+			if err := w.visitor.PostNode(f.id, f.node); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// the Lo arc and sub-diagram, then the Hi arc and sub-diagram
+		take := f.next == 1
+		f.next++
+		from, to := f.id, f.node.Lo
+		if take {
+			to = f.node.Hi
+		}
+		if err := w.visitor.Arc(from, to, take); err != nil {
+			return err
+		}
+		if err := w.enter(to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// visitFrame is a node whose arcs are being traversed
+type visitFrame struct {
+	id   NodeID
+	node Node
+
+	// next counts the arcs traversed so far
+	next int
+}
+
+// enter reaches id, pushing a frame for it unless it was seen before or its
+// PreNode callback skips it
+func (w *walker) enter(id NodeID) error {
+	if w.seen[id] {
+		return nil
+	}
+	w.seen[id] = true
+
+	select {
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	default:
+	}
+
+	node, err := w.zdd.GetNode(id)
+	if err != nil {
+		return err
+	}
+
+	if err := w.visitor.PreNode(id, node); err != nil {
+		if errors.Is(err, SkipNode) {
+			return nil
+		}
+		return err
+	}
+
+	w.stack = append(w.stack, visitFrame{id: id, node: node})
+	return nil
+}
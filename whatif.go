@@ -0,0 +1,318 @@
+package gozdd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// WhatIfSession supports interactive exploration of a prebuilt ZDD.
+//
+// A session keeps per-node dynamic programming tables for solution counts and,
+// when costs are supplied, minimum costs. Variables can be fixed to selected or
+// not selected and released again; each change only recomputes nodes at or above
+// the affected level, since values of lower nodes cannot depend on it.
+//
+// Sessions are not safe for concurrent use.
+type WhatIfSession struct {
+	zdd   *ZDD
+	costs []float64
+
+	// fixed maps variable levels to their forced assignment
+	fixed map[int]bool
+
+	// order lists reachable non-terminal nodes sorted by ascending level
+	order []NodeID
+	nodes map[NodeID]Node
+
+	// count holds the consistent solutions below each node, -1 beyond
+	// the int64 range
+	count  map[NodeID]int64
+	best   map[NodeID]float64
+	takeHi map[NodeID]bool
+
+	// forcedIn[l] counts variables fixed to selected among levels 1..l
+	forcedIn []int
+}
+
+// NewWhatIfSession creates a session over a built ZDD.
+//
+// Parameters:
+//   - ctx: Context for cancellation of the initial evaluation
+//   - zdd: The ZDD to explore; it must not be modified during the session
+//   - costs: Optional cost vector (1-based, like CostEvaluator); nil disables Best
+func NewWhatIfSession(ctx context.Context, zdd *ZDD, costs []float64) (*WhatIfSession, error) {
+	if zdd == nil {
+		return nil, fmt.Errorf("%w: ZDD is nil", ErrInvalidNode)
+	}
+
+	if costs != nil && len(costs) <= zdd.vars {
+		return nil, fmt.Errorf("insufficient cost data: need %d costs, got %d", zdd.vars, len(costs)-1)
+	}
+
+	s := &WhatIfSession{
+		zdd:      zdd,
+		costs:    costs,
+		fixed:    make(map[int]bool),
+		nodes:    make(map[NodeID]Node),
+		count:    map[NodeID]int64{ZeroNode: 0, OneNode: 1},
+		best:     map[NodeID]float64{ZeroNode: math.Inf(1), OneNode: 0},
+		takeHi:   make(map[NodeID]bool),
+		forcedIn: make([]int, zdd.vars+1),
+	}
+
+	if err := s.collect(zdd.root); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(s.order, func(i, j int) bool {
+		return s.nodes[s.order[i]].Level < s.nodes[s.order[j]].Level
+	})
+
+	if err := s.recompute(ctx, 1); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// collect gathers all reachable non-terminal nodes
+func (s *WhatIfSession) collect(root NodeID) error {
+	if root == NullNode || root == ZeroNode || root == OneNode {
+		return nil
+	}
+
+	stack := []NodeID{root}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if _, seen := s.nodes[id]; seen || id == ZeroNode || id == OneNode {
+			continue
+		}
+
+		node, err := s.zdd.GetNode(id)
+		if err != nil {
+			return err
+		}
+
+		s.nodes[id] = node
+		s.order = append(s.order, id)
+		stack = append(stack, node.Lo, node.Hi)
+	}
+
+	return nil
+}
+
+// Fix forces variable v to the given assignment and updates counts and optima.
+func (s *WhatIfSession) Fix(ctx context.Context, v int, take bool) error {
+	if v < 1 || v > s.zdd.vars {
+		return fmt.Errorf("%w: variable %d", ErrInvalidVariable, v)
+	}
+
+	if current, ok := s.fixed[v]; ok && current == take {
+		return nil
+	}
+
+	s.fixed[v] = take
+	return s.recompute(ctx, v)
+}
+
+// Unfix releases variable v so it may take either value again.
+func (s *WhatIfSession) Unfix(ctx context.Context, v int) error {
+	if v < 1 || v > s.zdd.vars {
+		return fmt.Errorf("%w: variable %d", ErrInvalidVariable, v)
+	}
+
+	if _, ok := s.fixed[v]; !ok {
+		return nil
+	}
+
+	delete(s.fixed, v)
+	return s.recompute(ctx, v)
+}
+
+// Reset releases all fixed variables.
+func (s *WhatIfSession) Reset(ctx context.Context) error {
+	if len(s.fixed) == 0 {
+		return nil
+	}
+
+	s.fixed = make(map[int]bool)
+	return s.recompute(ctx, 1)
+}
+
+// Fixed returns a copy of the current variable assignments.
+func (s *WhatIfSession) Fixed() map[int]bool {
+	fixed := make(map[int]bool, len(s.fixed))
+	for v, take := range s.fixed {
+		fixed[v] = take
+	}
+	return fixed
+}
+
+// Count returns the number of solutions consistent with the fixed variables.
+//
+// Returns ErrCountOverflow if the count exceeds the int64 range; CountBig
+// counts without that limit.
+func (s *WhatIfSession) Count() (int64, error) {
+	root := s.zdd.root
+	if root == NullNode || s.forcedBetween(s.levelOf(root), s.zdd.vars+1) {
+		return 0, nil
+	}
+	if s.count[root] < 0 {
+		return 0, ErrCountOverflow
+	}
+	return s.count[root], nil
+}
+
+// CountBig returns the exact number of solutions consistent with the fixed
+// variables, however large.
+//
+// Unlike Count it is not kept up to date by Fix and Unfix, so every call
+// walks all nodes of the diagram.
+func (s *WhatIfSession) CountBig(ctx context.Context) (*big.Int, error) {
+	root := s.zdd.root
+	if root == NullNode || s.forcedBetween(s.levelOf(root), s.zdd.vars+1) {
+		return new(big.Int), nil
+	}
+
+	counts := map[NodeID]*big.Int{ZeroNode: new(big.Int), OneNode: big.NewInt(1)}
+	for _, id := range s.order {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		node := s.nodes[id]
+		allowLo, allowHi := s.allowed(node)
+		count := new(big.Int)
+		if allowLo {
+			count.Add(count, counts[node.Lo])
+		}
+		if allowHi {
+			count.Add(count, counts[node.Hi])
+		}
+		counts[id] = count
+	}
+	return counts[root], nil
+}
+
+// Best returns the minimum-cost solution consistent with the fixed variables.
+//
+// Returns false if no costs were supplied or no consistent solution exists.
+func (s *WhatIfSession) Best() (*Solution, bool) {
+	root := s.zdd.root
+	if s.costs == nil || root == NullNode || s.forcedBetween(s.levelOf(root), s.zdd.vars+1) {
+		return nil, false
+	}
+
+	cost := s.best[root]
+	if math.IsInf(cost, 1) {
+		return nil, false
+	}
+
+	vars := []int{}
+	for id := root; id != OneNode; {
+		node := s.nodes[id]
+		if s.takeHi[id] {
+			vars = append(vars, node.Level)
+			id = node.Hi
+		} else {
+			id = node.Lo
+		}
+	}
+	sort.Ints(vars)
+
+	sol := &Solution{
+		Variables: vars,
+		Cost:      cost,
+		Metadata:  make(map[string]interface{}),
+	}
+	s.zdd.decorate(sol)
+	return sol, true
+}
+
+// recompute refreshes the DP tables for all nodes at level >= from
+func (s *WhatIfSession) recompute(ctx context.Context, from int) error {
+	for l := 1; l <= s.zdd.vars; l++ {
+		s.forcedIn[l] = s.forcedIn[l-1]
+		if take, ok := s.fixed[l]; ok && take {
+			s.forcedIn[l]++
+		}
+	}
+
+	start := sort.Search(len(s.order), func(i int) bool {
+		return s.nodes[s.order[i]].Level >= from
+	})
+
+	for _, id := range s.order[start:] {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		node := s.nodes[id]
+		allowLo, allowHi := s.allowed(node)
+
+		var lo, hi int64
+		if allowLo {
+			lo = s.count[node.Lo]
+		}
+		if allowHi {
+			hi = s.count[node.Hi]
+		}
+		if lo < 0 || hi < 0 || lo > math.MaxInt64-hi {
+			s.count[id] = -1
+		} else {
+			s.count[id] = lo + hi
+		}
+
+		if s.costs != nil {
+			loCost, hiCost := math.Inf(1), math.Inf(1)
+			if allowLo {
+				loCost = s.best[node.Lo]
+			}
+			if allowHi {
+				hiCost = s.best[node.Hi] + s.costs[node.Level]
+			}
+
+			if loCost <= hiCost {
+				s.best[id] = loCost
+				s.takeHi[id] = false
+			} else {
+				s.best[id] = hiCost
+				s.takeHi[id] = true
+			}
+		}
+	}
+
+	return nil
+}
+
+// allowed reports which arcs of node are consistent with the fixed variables
+func (s *WhatIfSession) allowed(node Node) (lo, hi bool) {
+	take, isFixed := s.fixed[node.Level]
+	lo = (!isFixed || !take) && !s.forcedBetween(s.levelOf(node.Lo), node.Level)
+	hi = (!isFixed || take) && !s.forcedBetween(s.levelOf(node.Hi), node.Level)
+	return lo, hi
+}
+
+// forcedBetween reports whether any variable strictly between lo and hi is fixed
+// to selected, which makes an arc skipping those levels inconsistent
+func (s *WhatIfSession) forcedBetween(lo, hi int) bool {
+	if hi-1 <= lo {
+		return false
+	}
+	return s.forcedIn[hi-1]-s.forcedIn[lo] > 0
+}
+
+// levelOf returns the level of a node, treating terminals as level 0
+func (s *WhatIfSession) levelOf(id NodeID) int {
+	if node, ok := s.nodes[id]; ok {
+		return node.Level
+	}
+	return 0
+}
@@ -0,0 +1,48 @@
+package gozdd_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/zzenonn/go-zdd"
+)
+
+func TestWhatIfSessionCountOverflow(t *testing.T) {
+	ctx := context.Background()
+	for _, vars := range []int{63, 64} {
+		session, err := gozdd.NewWhatIfSession(ctx, gozdd.PowerSet(vars), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// 2^vars sets overflow, as ZDD.Count reports
+		if count, err := session.Count(); !errors.Is(err, gozdd.ErrCountOverflow) {
+			t.Fatalf("PowerSet(%d): Count = %d, %v, want ErrCountOverflow", vars, count, err)
+		}
+		want := new(big.Int).Lsh(big.NewInt(1), uint(vars))
+		if count, err := session.CountBig(ctx); err != nil || count.Cmp(want) != 0 {
+			t.Fatalf("PowerSet(%d): CountBig = %v, %v, want %v", vars, count, err, want)
+		}
+
+		// leaving out variables brings the count back to 2^62
+		for v := 1; v <= vars-62; v++ {
+			if err := session.Fix(ctx, v, false); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if count, err := session.Count(); err != nil || count != 1<<62 {
+			t.Fatalf("PowerSet(%d) with %d variables fixed: Count = %d, %v, want 2^62",
+				vars, vars-62, count, err)
+		}
+
+		// releasing them overflows again
+		if err := session.Reset(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := session.Count(); !errors.Is(err, gozdd.ErrCountOverflow) {
+			t.Fatalf("PowerSet(%d) after Reset: got %v, want ErrCountOverflow", vars, err)
+		}
+	}
+}
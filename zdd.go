@@ -56,6 +56,7 @@ package gozdd
 import (
 	"context"
 	"fmt"
+	"math/big"
 )
 
 // State represents the constraint state during ZDD construction.
@@ -128,6 +129,23 @@ type ConstraintSpec interface {
 	IsValid(state State) bool
 }
 
+// CompletionSpec is an optional interface for ConstraintSpecs that can
+// recognize states from which every completion is feasible.
+//
+// When a spec implements CompletionSpec, Build asks before expanding each
+// state. If every assignment of the remaining variables is feasible, the
+// builder links the state directly to a chain of don't-care nodes leading to
+// the 1-terminal, instead of exploring 2^level sub-assignments. Under zero
+// suppression each free variable still needs its own node, so the chain has
+// exactly level nodes and the solution count is multiplied by 2^level.
+type CompletionSpec interface {
+	// AllCompletionsValid reports whether every assignment of variables
+	// level down to 1 leads to a feasible solution from state.
+	//
+	// Returning false is always safe.
+	AllCompletionsValid(state State, level int) bool
+}
+
 // ZDD represents a Zero-suppressed Decision Diagram for constraint optimization.
 //
 // A ZDD compactly represents all feasible solutions to a constraint satisfaction
@@ -139,6 +157,11 @@ type ConstraintSpec interface {
 //
 // ZDDs are immutable after construction. To modify constraints,
 // create a new ZDD instance.
+//
+// A built ZDD may be evaluated from several goroutines, but node access goes
+// through the node table's lock, and Build, Release and Manager.GC must not
+// overlap with other use. For lock-free concurrent reads, serve a FrozenZDD
+// obtained from Freeze.
 type ZDD struct {
 	// root is the NodeID of the root node
 	root NodeID
@@ -149,11 +172,35 @@ type ZDD struct {
 	// vars is the number of decision variables
 	vars int
 	
-	// reduced indicates if ZDD reduction has been applied
-	reduced bool
-	
 	// config holds construction parameters
 	config *Config
+	
+	// groups maps registered group names to their variables
+	groups map[string][]int
+	
+	// manager owns the shared node table when the ZDD is registered (optional)
+	manager *Manager
+	
+	// states memoizes constructed states while Build runs
+	states *buildStates
+	
+	// progress reports on the running Build (optional)
+	progress *buildProgress
+	
+	// log records the events of the running Build (optional)
+	log *buildLog
+	
+	// counters collect the statistics of the running Build (optional)
+	counters *buildCounters
+	
+	// notes holds user metadata attached to nodes
+	notes annotations
+	
+	// levels caches reachable nodes per level for NodesAtLevel
+	levels levelIndex
+	
+	// results memoizes evaluator results when ResultCache is enabled
+	results resultCache
 }
 
 // NewZDD creates a new ZDD with the specified number of variables.
@@ -175,19 +222,19 @@ func NewZDD(vars int, opts ...Option) *ZDD {
 	}
 	
 	return &ZDD{
-		root:    NullNode,
-		nodes:   NewNodeTable(),
-		vars:    vars,
-		reduced: false,
-		config:  newConfig(opts...),
+		root:   NullNode,
+		nodes:  NewNodeTable(),
+		vars:   vars,
+		config: newConfig(opts...),
 	}
 }
 
-// Build constructs the ZDD from a constraint specification using recursive
-// top-down construction.
+// Build constructs the ZDD from a constraint specification using top-down
+// construction.
 //
-// This method implements the breadth-first ZDD construction algorithm by
-// processing variables in order from highest to lowest level. The algorithm:
+// This method implements the depth-first ZDD construction algorithm by
+// processing variables in order from highest to lowest level, or the
+// breadth-first frontier algorithm with WithBreadthFirst. The algorithm:
 //   1. Starts with the initial state at the root
 //   2. For each variable level, explores both assignment choices
 //   3. Applies constraint transitions via GetChild()
@@ -206,7 +253,21 @@ func NewZDD(vars int, opts ...Option) *ZDD {
 //
 // After successful construction, the ZDD represents all feasible solutions
 // to the constraint problem.
+//
+// With WithParallel(n) for n > 1, the distinct states of each level are
+// expanded by n goroutines at once, so spec must then be safe for concurrent
+// use. The diagram represents the same family, but node IDs may differ
+// between runs unless WithDeterministic is given.
+//
+// BuildWithStats constructs the diagram the same way and also reports the
+// work done.
 func (z *ZDD) Build(ctx context.Context, spec ConstraintSpec) error {
+	return z.build(ctx, spec, nil)
+}
+
+// build constructs the diagram of spec, counting into counters if they are
+// not nil
+func (z *ZDD) build(ctx context.Context, spec ConstraintSpec, counters *buildCounters) error {
 	if spec.Variables() != z.vars {
 		return fmt.Errorf("spec variables (%d) != ZDD variables (%d)", spec.Variables(), z.vars)
 	}
@@ -218,112 +279,224 @@ func (z *ZDD) Build(ctx context.Context, spec ConstraintSpec) error {
 		defer cancel()
 	}
 	
-	// Build ZDD recursively from top level down
-	root, err := z.buildRecursive(ctx, spec, spec.InitialState(), z.vars)
+	// Logging and statistics are private to this build; the log reports
+	// some of the statistics
+	z.log = newBuildLog(ctx, z.config.Logger)
+	if counters == nil && z.log != nil {
+		counters = &buildCounters{}
+	}
+	z.counters = counters
+	defer func() { z.log, z.counters = nil, nil }()
+	
+	spec = z.wrapSpec(spec)
+	
+	// Managed ZDDs build straight into the shared table, so sub-diagrams
+	// already present from other builds are reused rather than duplicated
+	if z.manager != nil {
+		z.nodes = z.manager.table()
+	}
+	counters.begin(z.nodes)
+	
+	// State memoization is private to this build
+	z.states = newBuildStates()
+	z.progress = newBuildProgress(z.config.Progress, z.nodes)
+	defer func() { z.states, z.progress = nil, nil }()
+	
+	// Build ZDD depth first from top level down, or level by level for
+	// breadth-first construction and when workers share each level's states
+	var root NodeID
+	var err error
+	breadthFirst := z.config.BreadthFirst || z.config.Workers > 1
+	z.log.started(z.vars, max(z.config.Workers, 1), breadthFirst)
+	if breadthFirst {
+		root, err = z.buildFrontier(ctx, spec)
+	} else {
+		root, err = z.buildDepthFirst(ctx, spec, spec.InitialState(), z.vars)
+	}
+	z.log.finished(spec, counters, root, z.nodes.Size(), err)
 	if err != nil {
 		return fmt.Errorf("build failed: %w", err)
 	}
 	
+	if z.config.QuasiReduced {
+		root = z.nodes.lift(root, z.vars)
+	}
 	z.root = root
+	z.progress.done(z.vars)
 	return nil
 }
 
-// buildRecursive implements the TdZdd-style ZDD construction algorithm.
+// buildDepthFirst implements the TdZdd-style ZDD construction algorithm.
 // This matches the construction process used in TripS-ZDD for optimal performance.
-func (z *ZDD) buildRecursive(ctx context.Context, spec ConstraintSpec, state State, level int) (NodeID, error) {
+//
+// The Lo sub-diagram of a state is built before its Hi sub-diagram, as by a
+// recursive descent, but pending states are kept on an explicit stack, so
+// diagrams over hundreds of thousands of variables need no deep call stack.
+func (z *ZDD) buildDepthFirst(ctx context.Context, spec ConstraintSpec, state State, level int) (NodeID, error) {
+	root, pending, err := z.enterState(ctx, spec, state, level)
+	if err != nil || pending == nil {
+		return root, err
+	}
+	
+	stack := []buildFrame{*pending}
+	for {
+		f := &stack[len(stack)-1]
+		
+		// Explore 0-arc (variable NOT selected), then 1-arc (IS selected)
+		if f.next <= 1 {
+			take := f.next == 1
+			f.next++
+			switch {
+			case !f.branch.explores(take):
+				f.set(take, ZeroNode)
+				continue
+			case take && f.branch == BranchDontCare:
+				// both arcs lead to the child built for the 0-arc
+				f.hi = f.lo
+				continue
+			}
+			child, pending, err := z.followArc(ctx, spec, f.state, f.level, take)
+			if err != nil {
+				return NullNode, err
+			}
+			if pending != nil {
+				stack = append(stack, *pending)
+			} else {
+				f.set(take, child)
+			}
+			continue
+		}
+		
+		// Create node with ZDD reduction rules
+		node := z.addNode(f.level, f.lo, f.hi)
+		
+		// Cache the result for state deduplication
+		z.states.store(f.state, f.level, node)
+		z.progress.state()
+		z.progress.step(f.level)
+		
+		if na, ok := unwrapSpec(spec).(NodeAnnotator); ok {
+			z.annotateBuilt(na, f.state, f.level, node)
+		}
+		
+		// Return the node to the arc of the parent that is waiting for it
+		stack = stack[:len(stack)-1]
+		if len(stack) == 0 {
+			return node, nil
+		}
+		parent := &stack[len(stack)-1]
+		parent.set(parent.next == 2, node)
+	}
+}
+
+// buildFrame is a state whose sub-diagram is under construction
+type buildFrame struct {
+	state State
+	level int
+	
+	// branch holds the arcs the spec lets the builder explore
+	branch Branching
+	
+	// next is the arc to explore next: 0 for Lo, 1 for Hi, 2 when both
+	// are built
+	next int
+	
+	lo, hi NodeID
+}
+
+// set records the node an arc leads to
+func (f *buildFrame) set(take bool, node NodeID) {
+	if take {
+		f.hi = node
+	} else {
+		f.lo = node
+	}
+}
+
+// enterState returns the node of state at level if it is known without
+// expanding the state, or a frame to expand it
+func (z *ZDD) enterState(ctx context.Context, spec ConstraintSpec, state State, level int) (NodeID, *buildFrame, error) {
 	// Check for cancellation
 	select {
 	case <-ctx.Done():
-		return NullNode, ctx.Err()
+		return NullNode, nil, ctx.Err()
 	default:
 	}
 	
 	// Terminal case: all variables processed
 	if level == 0 {
 		if spec.IsValid(state) {
-			return OneNode, nil
+			return OneNode, nil, nil
 		}
-		return ZeroNode, nil
+		return ZeroNode, nil, nil
+	}
+	
+	// Connect states whose completions are all feasible to a free chain
+	if cs, ok := unwrapSpec(spec).(CompletionSpec); ok && cs.AllCompletionsValid(state, level) {
+		return z.nodes.powerSet(level), nil, nil
 	}
 	
 	// Check for state deduplication using hash-based memoization
-	if existingNode := z.nodes.LookupState(state, level); existingNode != NullNode {
-		return existingNode, nil
+	existingNode := z.states.lookup(state, level)
+	z.counters.stateFound(existingNode != NullNode)
+	if existingNode != NullNode {
+		return existingNode, nil, nil
 	}
 	
-	// Explore 0-arc: variable NOT selected (lo branch)
-	var lo NodeID
-	loState, err := spec.GetChild(ctx, state, level, false)
+	return NullNode, &buildFrame{state: state, level: level, branch: branchingOf(spec, state, level)}, nil
+}
+
+// followArc applies one assignment to state at level and enters the child
+func (z *ZDD) followArc(ctx context.Context, spec ConstraintSpec, state State, level int, take bool) (NodeID, *buildFrame, error) {
+	child, err := spec.GetChild(ctx, state, level, take)
 	if err != nil {
 		// Constraint violation - prune this branch
-		lo = ZeroNode
-	} else {
-		// Handle level skipping optimization
-		if skipState, ok := loState.(*SkipState); ok {
-			// Skip directly to target level without recursive calls
-			if skipState.SkipTo <= 0 {
-				// Skip to terminal - check validity
-				if spec.IsValid(skipState.State) {
-					lo = OneNode
-				} else {
-					lo = ZeroNode
-				}
-			} else {
-				// Skip to intermediate level
-				lo, err = z.buildRecursive(ctx, spec, skipState.State, skipState.SkipTo)
-				if err != nil {
-					return NullNode, err
-				}
-			}
-		} else {
-			// Normal recursive descent
-			lo, err = z.buildRecursive(ctx, spec, loState, level-1)
-			if err != nil {
-				return NullNode, err
-			}
-		}
+		return ZeroNode, nil, nil
 	}
 	
-	// Explore 1-arc: variable IS selected (hi branch)
-	var hi NodeID
-	hiState, err := spec.GetChild(ctx, state, level, true)
+	// Descend to the next level, or skip directly to a lower one without
+	// expanding the levels in between
+	child, target, err := childLevel(child, level)
 	if err != nil {
-		// Constraint violation - prune this branch
-		hi = ZeroNode
-	} else {
-		// Handle level skipping optimization
-		if skipState, ok := hiState.(*SkipState); ok {
-			// Skip directly to target level without recursive calls
-			if skipState.SkipTo <= 0 {
-				// Skip to terminal - check validity
-				if spec.IsValid(skipState.State) {
-					hi = OneNode
-				} else {
-					hi = ZeroNode
-				}
-			} else {
-				// Skip to intermediate level
-				hi, err = z.buildRecursive(ctx, spec, skipState.State, skipState.SkipTo)
-				if err != nil {
-					return NullNode, err
-				}
-			}
-		} else {
-			// Normal recursive descent
-			hi, err = z.buildRecursive(ctx, spec, hiState, level-1)
-			if err != nil {
-				return NullNode, err
-			}
-		}
+		return NullNode, nil, err
+	}
+	return z.enterState(ctx, spec, child, target)
+}
+
+// wrapSpec applies the configured tracing, counting and validation caching
+// to spec
+func (z *ZDD) wrapSpec(spec ConstraintSpec) ConstraintSpec {
+	if z.config.Trace != nil {
+		spec = newTracedSpec(spec, z.config.Trace)
 	}
 	
-	// Create node with ZDD reduction rules
-	node := z.nodes.AddNode(level, lo, hi)
-	
-	// Cache the result for state deduplication
-	z.nodes.CacheState(state, level, node)
+	if z.counters != nil {
+		spec = countedSpec{ConstraintSpec: spec, counters: z.counters, log: z.log}
+	}
 	
-	return node, nil
+	if z.config.ValidationCache {
+		spec = cachedSpec{ConstraintSpec: spec, cache: NewValidationCache(spec)}
+	}
+	return spec
+}
+
+// specWrapper is implemented by internal specs that decorate or adapt
+// another spec
+type specWrapper interface {
+	unwrap() interface{}
+}
+
+// unwrapSpec returns the application spec behind internal spec wrappers, so
+// that its optional interfaces can be detected
+func unwrapSpec(spec ConstraintSpec) interface{} {
+	var inner interface{} = spec
+	for {
+		w, ok := inner.(specWrapper)
+		if !ok {
+			return inner
+		}
+		inner = w.unwrap()
+	}
 }
 
 // Root returns the NodeID of the ZDD root node.
@@ -339,10 +512,38 @@ func (z *ZDD) Root() NodeID {
 // This includes terminal nodes but excludes the null node.
 // The size reflects the structural complexity of the constraint problem.
 // Larger sizes indicate more complex solution spaces.
+//
+// For a ZDD registered with a Manager, only nodes reachable from its root are
+// counted, since the shared table also holds other diagrams.
 func (z *ZDD) Size() int {
+	if z.manager != nil {
+		return z.reachableSize()
+	}
 	return z.nodes.Size()
 }
 
+// reachableSize counts the terminals and the nodes reachable from the root
+func (z *ZDD) reachableSize() int {
+	seen := make(map[NodeID]bool)
+	stack := []NodeID{z.root}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		
+		if id == NullNode || id == ZeroNode || id == OneNode || seen[id] {
+			continue
+		}
+		seen[id] = true
+		
+		node, err := z.nodes.GetNode(id)
+		if err != nil {
+			continue
+		}
+		stack = append(stack, node.Lo, node.Hi)
+	}
+	return len(seen) + 2
+}
+
 // Variables returns the number of decision variables in the ZDD.
 //
 // This value is set during NewZDD() and cannot be changed.
@@ -351,15 +552,6 @@ func (z *ZDD) Variables() int {
 	return z.vars
 }
 
-// IsReduced returns true if the ZDD is in reduced canonical form.
-//
-// Currently always returns false since explicit reduction is not implemented.
-// The ZDD construction automatically applies basic reduction rules during
-// node creation, but full reduction requires additional algorithms.
-func (z *ZDD) IsReduced() bool {
-	return z.reduced
-}
-
 // GetNode retrieves a node by its ID with validation.
 //
 // This method provides safe access to ZDD nodes for traversal and analysis.
@@ -376,7 +568,8 @@ func (z *ZDD) GetNode(id NodeID) (Node, error) {
 // Count returns the total number of solutions in the ZDD.
 //
 // This is a type-safe convenience method that eliminates the need for
-// type assertions when counting solutions.
+// type assertions when counting solutions. Returns ErrCountOverflow if the
+// count exceeds the int64 range; use CountBig for such diagrams.
 func (z *ZDD) Count(ctx context.Context) (int64, error) {
 	result, err := EvaluateZDD(ctx, z, CountEvaluator{})
 	if err != nil {
@@ -385,6 +578,19 @@ func (z *ZDD) Count(ctx context.Context) (int64, error) {
 	return result.(int64), nil
 }
 
+// CountBig returns the exact number of solutions in the ZDD.
+//
+// Unlike Count it never overflows, so it suits diagrams over many variables
+// whose solution counts exceed 2^63-1. The returned value is owned by the
+// caller.
+func (z *ZDD) CountBig(ctx context.Context) (*big.Int, error) {
+	result, err := EvaluateZDD(ctx, z, BigCountEvaluator{})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*big.Int), nil
+}
+
 // FindKBest finds the k best solutions with lowest costs.
 //
 // This is a type-safe convenience method that eliminates the need for
@@ -392,6 +598,7 @@ func (z *ZDD) Count(ctx context.Context) (int64, error) {
 //
 // For k=1, this finds the single optimal solution.
 // For k>1, this finds the top k solutions ranked by cost.
+// Ties are broken in the order documented at WithStableOrder.
 func (z *ZDD) FindKBest(ctx context.Context, k int, costs []float64) ([]*Solution, error) {
 	result, err := EvaluateZDD(ctx, z, KBestEvaluator{K: k, Costs: costs})
 	if err != nil {
@@ -401,3 +608,67 @@ func (z *ZDD) FindKBest(ctx context.Context, k int, costs []float64) ([]*Solutio
 	kbest := result.(KBestResult)
 	return kbest.Solutions, nil
 }
+
+// FindKBestMax finds the k solutions with the highest costs.
+//
+// It is FindKBest for maximization objectives such as total value: costs keep
+// their sign and solutions are returned in descending cost order. Ties are
+// broken in the order documented at WithStableOrder.
+func (z *ZDD) FindKBestMax(ctx context.Context, k int, costs []float64) ([]*Solution, error) {
+	result, err := EvaluateZDD(ctx, z, KBestEvaluator{K: k, Costs: costs, Maximize: true})
+	if err != nil {
+		return nil, err
+	}
+	
+	kbest := result.(KBestResult)
+	return kbest.Solutions, nil
+}
+
+// FindKBestDistinct finds one solution for each of the k lowest distinct
+// costs.
+//
+// Unlike FindKBest, many solutions of equal cost count once, so the result
+// shows how the cost levels step up. Each level is represented by its first
+// solution in the order documented at WithStableOrder.
+func (z *ZDD) FindKBestDistinct(ctx context.Context, k int, costs []float64) ([]*Solution, error) {
+	result, err := EvaluateZDD(ctx, z, KBestEvaluator{K: k, Costs: costs, Distinct: true})
+	if err != nil {
+		return nil, err
+	}
+	
+	return result.(KBestResult).Solutions, nil
+}
+
+// FindKBestFixed finds the k best solutions that select every variable
+// fixed to true and none fixed to false.
+//
+// The fixed assignments are applied during the k-best merge, so queries such
+// as "the best plans that must include variable 2" need no rebuilt diagram.
+// Returns ErrInvalidVariable for keys outside 1..Variables().
+func (z *ZDD) FindKBestFixed(ctx context.Context, k int, costs []float64, fixed map[int]bool) ([]*Solution, error) {
+	result, err := EvaluateZDD(ctx, z, KBestEvaluator{K: k, Costs: costs, Fixed: fixed})
+	if err != nil {
+		return nil, err
+	}
+	
+	return result.(KBestResult).Solutions, nil
+}
+
+// FindKBestSparse finds the k best solutions with costs given per variable.
+//
+// Variables missing from costs cost 0, so only the few variables with
+// nonzero costs need entries. Returns ErrInvalidVariable for keys outside
+// 1..Variables().
+func (z *ZDD) FindKBestSparse(ctx context.Context, k int, costs map[int]float64) ([]*Solution, error) {
+	if costs == nil {
+		costs = map[int]float64{}
+	}
+	
+	result, err := EvaluateZDD(ctx, z, KBestEvaluator{K: k, SparseCosts: costs})
+	if err != nil {
+		return nil, err
+	}
+	
+	kbest := result.(KBestResult)
+	return kbest.Solutions, nil
+}
@@ -0,0 +1,69 @@
+package zddtest_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/zzenonn/go-zdd"
+	"github.com/zzenonn/go-zdd/zddtest"
+)
+
+// atMostSpec accepts subsets with at most max selected variables.
+type atMostSpec struct {
+	vars int
+	max  int
+}
+
+func (s *atMostSpec) Variables() int {
+	return s.vars
+}
+
+func (s *atMostSpec) InitialState() gozdd.State {
+	return gozdd.NewIntState(0)
+}
+
+func (s *atMostSpec) GetChild(ctx context.Context, state gozdd.State, level int, take bool) (gozdd.State, error) {
+	next := state.Clone().(*gozdd.IntState)
+	if take {
+		next.Values[0]++
+		if next.Values[0] > s.max {
+			return nil, fmt.Errorf("more than %d selections", s.max)
+		}
+	}
+	return next, nil
+}
+
+func (s *atMostSpec) IsValid(state gozdd.State) bool {
+	return true
+}
+
+// ExampleMeasure demonstrates recording the behavior of a single instance.
+func ExampleMeasure() {
+	rec, err := zddtest.Measure(context.Background(), zddtest.Instance{
+		Name:  "pick-two",
+		Spec:  &atMostSpec{vars: 4, max: 2},
+		Costs: []float64{0, -1, 3, -2, 5},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Count: %d, Nodes: %d, Best: %.0f\n", rec.Count, rec.Nodes, *rec.BestCost)
+
+	// Output:
+	// Count: 11, Nodes: 8, Best: -3
+}
+
+// ExampleGolden shows a golden regression test for a spec package.
+func ExampleGolden() {
+	// In a _test.go file of the package defining the spec:
+	testGolden := func(t *testing.T) {
+		zddtest.Golden(t, "testdata/golden.json",
+			zddtest.Instance{Name: "small", Spec: &atMostSpec{vars: 4, max: 2}},
+			zddtest.Instance{Name: "large", Spec: &atMostSpec{vars: 30, max: 5}},
+		)
+	}
+	_ = testGolden
+}
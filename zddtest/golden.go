@@ -0,0 +1,206 @@
+// Package zddtest provides testing helpers for authors of ZDD constraint specs.
+//
+// The Golden helper records solution counts, diagram sizes and optimal costs
+// for named problem instances and compares later runs against the recording,
+// so refactoring a spec or upgrading the library cannot silently change its
+// behavior.
+//
+// Typical usage in a spec package:
+//
+//	func TestGolden(t *testing.T) {
+//		zddtest.Golden(t, "testdata/golden.json",
+//			zddtest.Instance{Name: "small", Spec: NewMySpec(10)},
+//			zddtest.Instance{Name: "weighted", Spec: NewMySpec(20), Costs: costs},
+//		)
+//	}
+//
+// Run the tests with -zddtest.update to create or refresh the golden file.
+package zddtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zzenonn/go-zdd"
+)
+
+var update = flag.Bool("zddtest.update", false, "rewrite zddtest golden files with the current results")
+
+// costTolerance is the relative tolerance used when comparing optimal costs
+const costTolerance = 1e-9
+
+// Instance describes a named problem whose behavior is recorded.
+type Instance struct {
+	// Name identifies the instance in the golden file and must be unique
+	Name string
+
+	// Spec is the constraint specification to build
+	Spec gozdd.ConstraintSpec
+
+	// Costs is an optional cost vector (1-based); when set the optimal cost is recorded
+	Costs []float64
+
+	// Options configure the ZDD built for this instance
+	Options []gozdd.Option
+}
+
+// Record holds the observed behavior of one instance.
+type Record struct {
+	// Count is the number of solutions
+	Count int64 `json:"count"`
+
+	// Nodes is the size of the built ZDD, including terminals
+	Nodes int `json:"nodes"`
+
+	// BestCost is the minimum solution cost, absent when no costs were given
+	// or no solution exists
+	BestCost *float64 `json:"best_cost,omitempty"`
+}
+
+// Measure builds an instance and returns its record.
+func Measure(ctx context.Context, inst Instance) (Record, error) {
+	if inst.Spec == nil {
+		return Record{}, fmt.Errorf("instance %q: spec is nil", inst.Name)
+	}
+
+	z := gozdd.NewZDD(inst.Spec.Variables(), inst.Options...)
+	if err := z.Build(ctx, inst.Spec); err != nil {
+		return Record{}, fmt.Errorf("instance %q: build: %w", inst.Name, err)
+	}
+
+	count, err := z.Count(ctx)
+	if err != nil {
+		return Record{}, fmt.Errorf("instance %q: count: %w", inst.Name, err)
+	}
+
+	rec := Record{Count: count, Nodes: z.Size()}
+
+	if inst.Costs != nil && count > 0 {
+		best, err := z.FindKBest(ctx, 1, inst.Costs)
+		if err != nil {
+			return Record{}, fmt.Errorf("instance %q: optimize: %w", inst.Name, err)
+		}
+		if len(best) > 0 {
+			cost := best[0].Cost
+			rec.BestCost = &cost
+		}
+	}
+
+	return rec, nil
+}
+
+// Golden measures every instance and compares the results with the golden
+// file at path, reporting each difference as a test error.
+//
+// When the test binary runs with -zddtest.update, the file is rewritten with
+// the current results instead. Instances missing from the golden file are
+// reported as errors so new instances are not accepted unnoticed.
+func Golden(t testing.TB, path string, instances ...Instance) {
+	t.Helper()
+
+	ctx := context.Background()
+	got := make(map[string]Record, len(instances))
+
+	for _, inst := range instances {
+		if _, dup := got[inst.Name]; dup {
+			t.Fatalf("zddtest: duplicate instance name %q", inst.Name)
+		}
+
+		rec, err := Measure(ctx, inst)
+		if err != nil {
+			t.Fatalf("zddtest: %v", err)
+		}
+		got[inst.Name] = rec
+	}
+
+	if *update {
+		if err := writeGolden(path, got); err != nil {
+			t.Fatalf("zddtest: %v", err)
+		}
+		return
+	}
+
+	want, err := readGolden(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("zddtest: golden file %s does not exist; run with -zddtest.update to create it", path)
+	}
+	if err != nil {
+		t.Fatalf("zddtest: %v", err)
+	}
+
+	for _, inst := range instances {
+		w, ok := want[inst.Name]
+		if !ok {
+			t.Errorf("zddtest: instance %q is not recorded in %s; run with -zddtest.update", inst.Name, path)
+			continue
+		}
+
+		for _, diff := range compare(w, got[inst.Name]) {
+			t.Errorf("zddtest: instance %q: %s", inst.Name, diff)
+		}
+	}
+}
+
+// compare lists the differences between a recorded and an observed record
+func compare(want, got Record) []string {
+	var diffs []string
+
+	if want.Count != got.Count {
+		diffs = append(diffs, fmt.Sprintf("count = %d, want %d", got.Count, want.Count))
+	}
+	if want.Nodes != got.Nodes {
+		diffs = append(diffs, fmt.Sprintf("nodes = %d, want %d", got.Nodes, want.Nodes))
+	}
+
+	switch {
+	case want.BestCost == nil && got.BestCost == nil:
+	case want.BestCost == nil:
+		diffs = append(diffs, fmt.Sprintf("best cost = %g, want none", *got.BestCost))
+	case got.BestCost == nil:
+		diffs = append(diffs, fmt.Sprintf("best cost = none, want %g", *want.BestCost))
+	case !costEqual(*want.BestCost, *got.BestCost):
+		diffs = append(diffs, fmt.Sprintf("best cost = %g, want %g", *got.BestCost, *want.BestCost))
+	}
+
+	return diffs
+}
+
+// costEqual compares costs with a relative tolerance
+func costEqual(a, b float64) bool {
+	scale := math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+	return math.Abs(a-b) <= costTolerance*scale
+}
+
+// readGolden loads the records stored at path
+func readGolden(path string) (map[string]Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var records map[string]Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parse golden file %s: %w", path, err)
+	}
+	return records, nil
+}
+
+// writeGolden stores records at path, creating parent directories as needed
+func writeGolden(path string, records map[string]Record) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create golden directory: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}